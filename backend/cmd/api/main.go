@@ -1,8 +1,15 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"flag"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 	
 	"github.com/vietgs03/translate/backend/internal/config"
 	"github.com/vietgs03/translate/backend/internal/database"
@@ -11,6 +18,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 	"github.com/vietgs03/translate/backend/internal/middleware"
+	"github.com/vietgs03/translate/backend/internal/model"
 	"github.com/vietgs03/translate/backend/internal/handler"
 	"github.com/vietgs03/translate/backend/internal/openai"
 	"github.com/vietgs03/translate/backend/internal/repository"
@@ -19,6 +27,15 @@ import (
 	"go.uber.org/zap"
 	"github.com/vietgs03/translate/backend/internal/service/google"
 	"github.com/vietgs03/translate/backend/internal/service/translator"
+	"github.com/vietgs03/translate/backend/internal/token"
+	"github.com/vietgs03/translate/backend/internal/oidc"
+	"github.com/vietgs03/translate/backend/internal/ratelimit"
+	"github.com/vietgs03/translate/backend/internal/mail"
+	"github.com/vietgs03/translate/backend/internal/language"
+	"github.com/vietgs03/translate/backend/internal/lockout"
+	"github.com/vietgs03/translate/backend/internal/metrics"
+	"github.com/vietgs03/translate/backend/internal/tracing"
+	"github.com/vietgs03/translate/backend/internal/health"
 	"github.com/gofiber/swagger"
 	_ "github.com/vietgs03/translate/backend/docs" // swagger docs
 )
@@ -43,51 +60,129 @@ import (
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
 
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key for machine clients, created at /api-keys.
+
 type App struct {
 	config            *config.Config
 	db               *gorm.DB
 	redis            *redis.Client
 	fiber            *fiber.App
+	metrics          *fiber.App
 	openai           *openai.Client
-	cache            *cache.TranslationCache
+	cache            cache.TranslationCache
 	logger           *zap.Logger
 	authService      service.AuthService
+	apiKeyService      service.APIKeyService
+	rbacService        service.RBACService
+	projectService     service.ProjectService
 	translationService service.TranslationService
 	authHandler     *handler.AuthHandler
+	apiKeyHandler      *handler.APIKeyHandler
 	translationHandler *handler.TranslationHandler
+	cacheHandler       *handler.CacheHandler
+	jwksHandler        *handler.JWKSHandler
+	rbacHandler        *handler.RBACHandler
+	projectHandler     *handler.ProjectHandler
+	auditHandler       *handler.AuditHandler
+	languageHandler    *handler.LanguageHandler
+	healthHandler      *handler.HealthHandler
+	keys               *token.KeySet
+	denylist           token.Denylist
+	limiter            ratelimit.Limiter
 }
 
 func main() {
-	app, err := initApp()
+	flags := config.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load(flags)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if flags.PrintConfig() {
+		if err := cfg.Dump(os.Stdout); err != nil {
+			log.Fatalf("Failed to print config: %v", err)
+		}
+		return
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+
+	app, err := initApp(cfg)
 	if err != nil {
 		log.Fatalf("Failed to initialize app: %v", err)
 	}
 
+	// Stop accepting requests on SIGINT or SIGTERM, letting the ones in
+	// flight finish
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+		if err := app.fiber.ShutdownWithTimeout(10 * time.Second); err != nil {
+			log.Printf("Failed to shut down server: %v", err)
+		}
+		if err := app.metrics.Shutdown(); err != nil {
+			log.Printf("Failed to shut down metrics server: %v", err)
+		}
+	}()
+
+	go func() {
+		if err := app.metrics.Listen(fmt.Sprintf(":%s", app.config.Metrics.Port)); err != nil {
+			log.Fatalf("Failed to start metrics server: %v", err)
+		}
+	}()
+
 	// Start server
 	serverAddr := fmt.Sprintf(":%s", app.config.ServerPort)
 	log.Printf("Server starting on port %s", app.config.ServerPort)
 	if err := app.fiber.Listen(serverAddr); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
-}
 
-func initApp() (*App, error) {
-	// Load configuration
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return nil, fmt.Errorf("failed to load config: %v", err)
+	// Send the spans still buffered
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("Failed to flush traces: %v", err)
 	}
+}
 
+func initApp(cfg *config.Config) (*App, error) {
 	// Initialize database
 	db, err := database.NewPostgresDB(&cfg.Database)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize database: %v", err)
 	}
 
-	// Initialize Redis
-	redisClient, err := database.NewRedisClient(&cfg.Redis)
+	// Initialize Redis, only needed by the redis cache backend
+	var redisClient *redis.Client
+	if cfg.Cache.Backend == cache.BackendRedis {
+		redisClient, err = database.NewRedisClient(&cfg.Redis)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Redis: %v", err)
+		}
+	}
+
+	// Export the connection pool stats next to the request metrics
+	sqlDB, err := db.DB()
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize Redis: %v", err)
+		return nil, fmt.Errorf("failed to get database handle: %v", err)
+	}
+	if err := metrics.RegisterDB(sqlDB, cfg.Database.DBName); err != nil {
+		return nil, fmt.Errorf("failed to register database metrics: %v", err)
+	}
+	if redisClient != nil {
+		if err := metrics.RegisterRedis(redisClient); err != nil {
+			return nil, fmt.Errorf("failed to register Redis metrics: %v", err)
+		}
 	}
 
 	// Initialize logger
@@ -96,16 +191,38 @@ func initApp() (*App, error) {
 		return nil, fmt.Errorf("failed to create logger: %v", err)
 	}
 	defer logger.Sync()
+	// Code without a request logger in its context logs here too
+	zap.ReplaceGlobals(logger)
 
 	// Initialize cache
-	translationCache := cache.NewTranslationCache(redisClient)
+	var translationCache cache.TranslationCache
+	switch cfg.Cache.Backend {
+	case cache.BackendRedis:
+		redisCache := cache.NewRedisCache(redisClient, &cfg.Cache)
+		// Apply invalidations from other replicas and flush hit counts
+		go redisCache.Run(context.Background())
+		translationCache = redisCache
+	case cache.BackendMemory:
+		translationCache = cache.NewMemoryCache(&cfg.Cache)
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", cfg.Cache.Backend)
+	}
+	translationCache = cache.NewTracedCache(translationCache, cfg.Cache.Backend)
 
 	// Initialize OpenAI client with rate limiter
 	openaiClient := openai.NewClient(&cfg.OpenAI, redisClient)
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	userTokenRepo := repository.NewUserTokenRepository(db)
+	signingKeyRepo := repository.NewSigningKeyRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	assignmentRepo := repository.NewTranslatorAssignmentRepository(db)
+	projectRepo := repository.NewProjectRepository(db)
 	translationRepo := repository.NewTranslationRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
 
 	// Initialize translation service
 	var translatorService translator.Translator
@@ -120,39 +237,158 @@ func initApp() (*App, error) {
 		return nil, fmt.Errorf("failed to create translator service: %v", err)
 	}
 
+	// Only offer the configured languages the provider can translate
+	languages, err := language.NewRegistry(cfg.Languages.Supported, cfg.Languages.Pairs)
+	if err == nil {
+		languages, err = languages.Restrict(translatorService.Coverage())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up languages: %v", err)
+	}
+
+	// Readiness checks the dependencies; the provider only when asked to,
+	// as probing it may cost money
+	healthChecker, err := newHealthChecker(cfg, sqlDB, redisClient, translatorService)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up health checks: %v", err)
+	}
+
+	// Access tokens are signed with a rotating key set shared through the database
+	keySet, err := token.NewKeySet(context.Background(), signingKeyRepo, &cfg.JWT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signing keys: %v", err)
+	}
+	go keySet.Run(context.Background())
+
+	// Revoked access tokens, failed login counters and rate limits are
+	// shared through Redis when it is available
+	var denylist token.Denylist
+	var loginGuard lockout.Guard
+	var limiter ratelimit.Limiter
+	loginPolicy := lockout.PolicyFromConfig(&cfg.Auth)
+	if redisClient != nil {
+		denylist = token.NewRedisDenylist(redisClient)
+		loginGuard = lockout.NewRedisGuard(redisClient, loginPolicy)
+		limiter = ratelimit.NewRedisLimiter(redisClient)
+	} else {
+		denylist = token.NewMemoryDenylist()
+		loginGuard = lockout.NewMemoryGuard(loginPolicy)
+		limiter = ratelimit.NewMemoryLimiter()
+	}
+
+	// Single sign-on is optional; pending logins live next to the denylist
+	var oidcProvider *oidc.Provider
+	var oidcStates oidc.StateStore
+	if cfg.OIDC.Enabled() {
+		oidcProvider, err = oidc.NewProvider(context.Background(), &cfg.OIDC)
+		if err != nil {
+			return nil, err
+		}
+		if redisClient != nil {
+			oidcStates = oidc.NewRedisStateStore(redisClient)
+		} else {
+			oidcStates = oidc.NewMemoryStateStore()
+		}
+	}
+
+	mailer, err := mail.NewMailer(&cfg.Mail)
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize services
-	authService := service.NewAuthService(userRepo, cfg.JWT)
+	auditService := service.NewAuditService(auditRepo)
+	authService := service.NewAuthService(
+		userRepo,
+		refreshTokenRepo,
+		userTokenRepo,
+		keySet,
+		denylist,
+		loginGuard,
+		mailer,
+		cfg.JWT,
+		cfg.Auth,
+		oidcProvider,
+		oidcStates,
+		auditService,
+	)
+	rbacService := service.NewRBACService(roleRepo, userRepo, assignmentRepo, auditService)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, rbacService, auditService)
+	projectService := service.NewProjectService(projectRepo, userRepo, rbacService, auditService)
 	translationService := service.NewTranslationService(
 		translationRepo,
+		assignmentRepo,
+		rbacService,
 		translationCache,
 		translatorService,
+		auditService,
+		languages,
 	)
 
 	// Initialize handlers
-	authHandler := handler.NewAuthHandler(authService)
+	authHandler := handler.NewAuthHandler(authService, rbacService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
 	translationHandler := handler.NewTranslationHandler(translationService)
+	cacheHandler := handler.NewCacheHandler(translationCache)
+	jwksHandler := handler.NewJWKSHandler(keySet)
+	rbacHandler := handler.NewRBACHandler(rbacService)
+	projectHandler := handler.NewProjectHandler(projectService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	languageHandler := handler.NewLanguageHandler(languages, translatorService.Name())
+	healthHandler := handler.NewHealthHandler(healthChecker)
 
 	// Create Fiber app with custom error handler
-	fiberApp := fiber.New(fiber.Config{
+	fiberConfig := fiber.Config{
 		ErrorHandler: middleware.ErrorHandler,
-	})
+	}
+	// c.IP() trusts the proxy header only from the configured proxies, so
+	// clients can't pick their own rate limit bucket or audited address
+	if len(cfg.TrustedProxies) > 0 {
+		fiberConfig.EnableTrustedProxyCheck = true
+		fiberConfig.TrustedProxies = cfg.TrustedProxies
+		fiberConfig.ProxyHeader = cfg.ProxyHeader
+		fiberConfig.EnableIPValidation = true
+	}
+	fiberApp := fiber.New(fiberConfig)
 
 	// Add middleware
+	fiberApp.Use(middleware.Tracing())
+	fiberApp.Use(middleware.Metrics())
 	fiberApp.Use(middleware.Logger(logger))
 	fiberApp.Use(recover.New())
 
+	// Prometheus scrapes its own port, so the metrics never share the
+	// public listener
+	metricsApp := fiber.New(fiber.Config{DisableStartupMessage: true})
+	metricsApp.Get("/metrics", metrics.Handler(cfg.Metrics.Token))
+
 	app := &App{
 		config:            cfg,
 		db:               db,
 		redis:            redisClient,
 		fiber:            fiberApp,
+		metrics:          metricsApp,
 		openai:           openaiClient,
 		cache:            translationCache,
 		logger:           logger,
 		authService:      authService,
+		apiKeyService:      apiKeyService,
+		rbacService:        rbacService,
+		projectService:     projectService,
 		translationService: translationService,
 		authHandler:     authHandler,
+		apiKeyHandler:      apiKeyHandler,
 		translationHandler: translationHandler,
+		cacheHandler:       cacheHandler,
+		jwksHandler:        jwksHandler,
+		rbacHandler:        rbacHandler,
+		projectHandler:     projectHandler,
+		auditHandler:       auditHandler,
+		languageHandler:    languageHandler,
+		healthHandler:      healthHandler,
+		keys:               keySet,
+		denylist:           denylist,
+		limiter:            limiter,
 	}
 
 	// Setup routes
@@ -162,66 +398,234 @@ func initApp() (*App, error) {
 }
 
 func setupRoutes(app *App) {
+	// Key discovery for services verifying our tokens, outside the API prefix
+	app.fiber.Get("/.well-known/jwks.json", app.jwksHandler.JWKS)
+	// Orchestrator probes
+	app.fiber.Get("/livez", app.healthHandler.Live)
+	app.fiber.Get("/readyz", app.healthHandler.Ready)
+
 	api := app.fiber.Group("/api/v1")
 	
 	// Public routes
 	public := api.Group("/public")
-	public.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"status": "ok",
-			"services": fiber.Map{
-				"api":    "up",
-				"db":     "up",
-				"redis":  "up",
-			},
-		})
-	})
+	public.Get("/health", app.healthHandler.Ready)
+
+	api.Get("/languages", app.languageHandler.List)
+
+	allowlist := ratelimit.NewAllowlist(app.config.RateLimit.Allowlist)
 
-	// Auth routes (public)
+	// Auth routes (public), limited per client IP against credential
+	// stuffing and mail flooding
 	auth := api.Group("/auth")
-	auth.Post("/register", middleware.ValidateRequest(&service.RegisterInput{}), app.authHandler.Register)
-	auth.Post("/login", middleware.ValidateRequest(&service.LoginInput{}), app.authHandler.Login)
+	auth.Use(middleware.RateLimit(app.limiter, "auth", ratelimit.AuthPolicy(&app.config.RateLimit), allowlist))
+	auth.Post("/register", middleware.Validate[service.RegisterInput](), app.authHandler.Register)
+	auth.Post("/login", middleware.Validate[service.LoginInput](), app.authHandler.Login)
+	auth.Post("/refresh", middleware.Validate[service.RefreshInput](), app.authHandler.Refresh)
+	auth.Get("/email/verify", app.authHandler.VerifyEmail)
+	auth.Post("/email/resend", middleware.Validate[service.EmailInput](), app.authHandler.ResendVerification)
+	auth.Post("/password/forgot", middleware.Validate[service.EmailInput](), app.authHandler.ForgotPassword)
+	auth.Post("/password/reset", middleware.Validate[service.ResetPasswordInput](), app.authHandler.ResetPassword)
+	auth.Get("/oidc/login", app.authHandler.OIDCLogin)
+	auth.Get("/oidc/callback", app.authHandler.OIDCCallback)
+	auth.Post("/oidc/link",
+		middleware.JWTAuth(app.keys, app.denylist, app.apiKeyService, app.authService),
+		app.authHandler.OIDCLink,
+	)
+	auth.Post("/logout",
+		middleware.JWTAuth(app.keys, app.denylist, app.apiKeyService, app.authService),
+		middleware.Validate[service.LogoutInput](),
+		app.authHandler.Logout,
+	)
 
-	// Protected routes
+	// Protected routes, by Bearer token or X-API-Key
 	protected := api.Group("/")
-	protected.Use(middleware.JWTAuth(app.config.JWT.SecretKey))
-	protected.Use(middleware.RateLimiter())
+	protected.Use(middleware.JWTAuth(app.keys, app.denylist, app.apiKeyService, app.authService))
+	// Per user or API key, with a smaller budget for routes that may call a provider
+	protected.Use(middleware.RateLimit(app.limiter, "api", ratelimit.APIPolicy(&app.config.RateLimit), allowlist))
+	translateLimit := middleware.RateLimit(app.limiter, "translate", ratelimit.TranslatePolicy(&app.config.RateLimit), allowlist)
+
+	// Routes addressing one record by :id
+	byID := middleware.Validate[handler.IDPath]()
+	listTranslations := middleware.Validate[handler.ListTranslationsQuery]()
+
+	// API keys of the current user
+	apiKeys := protected.Group("/api-keys")
+	apiKeys.Post("/", middleware.Validate[service.CreateAPIKeyInput](), app.apiKeyHandler.Create)
+	apiKeys.Get("/", app.apiKeyHandler.List)
+	apiKeys.Patch("/:id", byID, middleware.Validate[service.UpdateAPIKeyInput](), app.apiKeyHandler.Update)
+	apiKeys.Delete("/:id", byID, app.apiKeyHandler.Revoke)
+
+	// Routes are guarded by permissions; which roles hold them is managed
+	// through the role endpoints below
+	can := func(permission string) fiber.Handler {
+		return middleware.RequirePermission(app.rbacService, permission)
+	}
 
 	// Admin routes
 	admin := protected.Group("/admin")
-	admin.Use(middleware.RequireRole("admin"))
-	admin.Put("/users/:id/role", app.authHandler.UpdateRole)
-
-	// Translation routes with role-based access
+	admin.Get("/users", can(model.PermissionUserManage), middleware.Validate[handler.ListUsersQuery](), app.authHandler.ListUsers)
+	admin.Get("/users/:id", can(model.PermissionUserManage), byID, app.authHandler.GetUser)
+	admin.Delete("/users/:id", can(model.PermissionUserManage), byID, app.authHandler.DeleteUser)
+	admin.Post("/users/:id/deactivate", can(model.PermissionUserManage), byID, app.authHandler.DeactivateUser)
+	admin.Post("/users/:id/reactivate", can(model.PermissionUserManage), byID, app.authHandler.ReactivateUser)
+	admin.Post("/users/:id/password",
+		can(model.PermissionUserManage),
+		byID,
+		middleware.Validate[service.AdminResetPasswordInput](),
+		app.authHandler.AdminResetPassword,
+	)
+	admin.Put("/users/:id/role",
+		can(model.PermissionUserManage),
+		byID,
+		middleware.Validate[handler.UpdateRoleInput](),
+		app.authHandler.UpdateRole,
+	)
+	admin.Post("/users/:id/unlock", can(model.PermissionUserManage), byID, app.authHandler.UnlockUser)
+	admin.Get("/users/:id/assignments", can(model.PermissionUserManage), byID, app.rbacHandler.ListAssignments)
+	admin.Post("/users/:id/assignments",
+		can(model.PermissionUserManage),
+		byID,
+		middleware.Validate[service.CreateAssignmentInput](),
+		app.rbacHandler.CreateAssignment,
+	)
+	admin.Delete("/assignments/:id", can(model.PermissionUserManage), byID, app.rbacHandler.DeleteAssignment)
+	admin.Get("/audit", can(model.PermissionAuditRead), middleware.Validate[handler.AuditQuery](), app.auditHandler.List)
+	admin.Get("/audit/export", can(model.PermissionAuditRead), middleware.Validate[handler.AuditQuery](), app.auditHandler.Export)
+	admin.Get("/cache/stats", can(model.PermissionCacheManage), app.cacheHandler.Stats)
+	admin.Get("/cache/entry", can(model.PermissionCacheManage), middleware.Validate[handler.CacheEntryQuery](), app.cacheHandler.Inspect)
+	admin.Delete("/cache", can(model.PermissionCacheManage), middleware.Validate[handler.CachePurgeQuery](), app.cacheHandler.Purge)
+
+	// Roles and permissions
+	manageRoles := can(model.PermissionRoleManage)
+	byName := middleware.Validate[handler.RolePath]()
+	admin.Get("/roles", manageRoles, app.rbacHandler.ListRoles)
+	admin.Post("/roles", manageRoles, middleware.Validate[service.CreateRoleInput](), app.rbacHandler.CreateRole)
+	admin.Put("/roles/:name", manageRoles, byName, middleware.Validate[service.UpdateRoleInput](), app.rbacHandler.UpdateRole)
+	admin.Delete("/roles/:name", manageRoles, byName, app.rbacHandler.DeleteRole)
+	admin.Get("/permissions", manageRoles, app.rbacHandler.ListPermissions)
+	admin.Post("/permissions", manageRoles, middleware.Validate[service.CreatePermissionInput](), app.rbacHandler.CreatePermission)
+
+	// Translation routes with permission-based access
 	translations := protected.Group("/translations")
 	
-	// Create translation - requires validation
 	translations.Post("/", 
-		middleware.ValidateRequest(&service.CreateTranslationInput{}),
-		middleware.RequireRole("user", "translator", "admin"),
+		middleware.Validate[service.CreateTranslationInput](),
+		can(model.PermissionTranslationCreate),
+		translateLimit,
 		app.translationHandler.Create,
 	)
 
-	// Read operations - any authenticated user
-	translations.Get("/:id", app.translationHandler.Get)
-	translations.Get("/", app.translationHandler.List)
+	translations.Get("/:id", byID, can(model.PermissionTranslationRead), app.translationHandler.Get)
+	translations.Get("/", can(model.PermissionTranslationRead), listTranslations, app.translationHandler.List)
 
-	// Update operations - requires translator role
+	// Who may edit depends on the translation, so the service checks it
 	translations.Put("/:id",
-		middleware.ValidateRequest(&service.UpdateTranslationInput{}),
-		middleware.RequireRole("translator", "admin"),
+		byID,
+		middleware.Validate[service.UpdateTranslationInput](),
 		app.translationHandler.Update,
 	)
 
-	// Delete operations - requires admin role
+	translations.Put("/:id/status",
+		byID,
+		middleware.Validate[service.ReviewTranslationInput](),
+		can(model.PermissionTranslationApprove),
+		app.translationHandler.Review,
+	)
+
 	translations.Delete("/:id",
-		middleware.RequireRole("admin"),
+		byID,
+		can(model.PermissionTranslationDelete),
 		app.translationHandler.Delete,
 	)
 
+	// Organizations and their projects; the service checks the caller's
+	// role in the organization on top of these
+	orgRead, orgWrite := can(model.PermissionTranslationRead), can(model.PermissionTranslationCreate)
+	byOrg := middleware.Validate[handler.OrgPath]()
+	orgs := protected.Group("/orgs")
+	orgs.Post("/", orgWrite, middleware.Validate[service.CreateOrganizationInput](), app.projectHandler.CreateOrganization)
+	orgs.Get("/", orgRead, app.projectHandler.ListOrganizations)
+	orgs.Get("/:orgID/members", orgRead, byOrg, app.projectHandler.ListOrganizationMembers)
+	orgs.Put("/:orgID/members", orgWrite, byOrg, middleware.Validate[service.OrganizationMemberInput](), app.projectHandler.AddOrganizationMember)
+	orgs.Delete("/:orgID/members/:userID", orgWrite, middleware.Validate[handler.OrgMemberPath](), app.projectHandler.RemoveOrganizationMember)
+	orgs.Post("/:orgID/projects", orgWrite, byOrg, middleware.Validate[service.CreateProjectInput](), app.projectHandler.CreateProject)
+	orgs.Get("/:orgID/projects", orgRead, byOrg, app.projectHandler.ListProjects)
+
+	// Project routes are governed by the caller's role in the project
+	// instead of global permissions; translations here only ever see the
+	// project's own rows
+	member := func(role string) fiber.Handler {
+		return middleware.RequireProjectRole(app.projectService, role)
+	}
+	projects := protected.Group("/projects/:projectID", middleware.Validate[middleware.ProjectPath]())
+	projects.Get("/", member(model.ProjectRoleViewer), app.projectHandler.GetProject)
+	projects.Get("/members", member(model.ProjectRoleViewer), app.projectHandler.ListProjectMembers)
+	projects.Put("/members",
+		member(model.ProjectRoleMaintainer),
+		middleware.Validate[service.ProjectMemberInput](),
+		app.projectHandler.AddProjectMember,
+	)
+	projects.Delete("/members/:userID", member(model.ProjectRoleMaintainer), middleware.Validate[handler.MemberPath](), app.projectHandler.RemoveProjectMember)
+
+	projectTranslations := projects.Group("/translations")
+	projectTranslations.Post("/",
+		member(model.ProjectRoleTranslator),
+		middleware.Validate[service.CreateTranslationInput](),
+		translateLimit,
+		app.translationHandler.Create,
+	)
+	projectTranslations.Get("/:id", member(model.ProjectRoleViewer), byID, app.translationHandler.Get)
+	projectTranslations.Get("/", member(model.ProjectRoleViewer), listTranslations, app.translationHandler.List)
+	projectTranslations.Put("/:id",
+		member(model.ProjectRoleTranslator),
+		byID,
+		middleware.Validate[service.UpdateTranslationInput](),
+		app.translationHandler.Update,
+	)
+	projectTranslations.Put("/:id/status",
+		member(model.ProjectRoleMaintainer),
+		byID,
+		middleware.Validate[service.ReviewTranslationInput](),
+		app.translationHandler.Review,
+	)
+	projectTranslations.Delete("/:id", member(model.ProjectRoleMaintainer), byID, app.translationHandler.Delete)
+
 	// Swagger documentation
 	app.fiber.Get("/swagger/*", swagger.New(swagger.Config{
 		URL: "/swagger/doc.json",
 		DeepLinking: false,
 	}))
-}
\ No newline at end of file
+}
+
+func newHealthChecker(cfg *config.Config, sqlDB *sql.DB, redisClient *redis.Client, provider translator.Translator) (*health.Checker, error) {
+	migrationVersion, err := database.ExpectedMigrationVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	checks := []health.Check{
+		{Name: "postgres", Run: sqlDB.PingContext},
+		{Name: "migrations", Run: func(ctx context.Context) error {
+			return database.CheckMigrations(ctx, sqlDB, migrationVersion)
+		}},
+	}
+	if redisClient != nil {
+		checks = append(checks, health.Check{Name: "redis", Run: func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		}})
+	}
+	if prober, ok := provider.(translator.Prober); ok && cfg.Health.ProviderProbe {
+		interval := time.Duration(cfg.Health.ProviderProbeInterval) * time.Second
+		checks = append(checks, health.Check{
+			Name:     "provider",
+			Run:      health.Every(interval, time.Duration(cfg.Health.Timeout)*time.Millisecond, prober.Probe),
+			Optional: true,
+		})
+	}
+
+	return health.NewChecker(
+		time.Duration(cfg.Health.Timeout)*time.Millisecond,
+		time.Duration(cfg.Health.CacheTTL)*time.Second,
+		checks...,
+	), nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/vietgs03/translate/backend/internal/cache"
+	"github.com/vietgs03/translate/backend/internal/config"
+	"github.com/vietgs03/translate/backend/internal/database"
+	"github.com/vietgs03/translate/backend/internal/model"
+	"github.com/vietgs03/translate/backend/internal/repository"
+)
+
+// cachewarm preloads translations from Postgres into the Redis cache, for
+// example after a flush or a namespace change on deploy.
+//
+//	go run ./cmd/cachewarm -source popular -n 5000
+func main() {
+	source := flag.String("source", "popular", "what to preload: popular (most looked up) or approved (highest voted approved translations)")
+	limit := flag.Int("n", 1000, "number of translations to preload")
+	flags := config.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	if *source != "popular" && *source != "approved" {
+		log.Fatal("Source must be either 'popular' or 'approved'")
+	}
+	if *limit <= 0 {
+		log.Fatal("N must be positive")
+	}
+
+	cfg, err := config.Load(flags)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.NewPostgresDB(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+
+	redisClient, err := database.NewRedisClient(&cfg.Redis)
+	if err != nil {
+		log.Fatalf("Failed to initialize Redis: %v", err)
+	}
+	defer redisClient.Close()
+
+	ctx := context.Background()
+	translationCache := cache.NewRedisCache(redisClient, &cfg.Cache)
+	translationRepo := repository.NewTranslationRepository(db)
+
+	var translations []model.Translation
+	if *source == "popular" {
+		translations, err = loadPopular(ctx, translationCache, translationRepo, *limit)
+	} else {
+		translations, err = loadApproved(ctx, translationRepo, *limit)
+	}
+	if err != nil {
+		log.Fatalf("Failed to load translations: %v", err)
+	}
+
+	warmed := 0
+	for i := range translations {
+		if err := translationCache.Set(ctx, &translations[i]); err != nil {
+			log.Printf("Failed to cache translation %d: %v", translations[i].ID, err)
+			continue
+		}
+		warmed++
+	}
+
+	log.Printf("Successfully preloaded %d of %d %s translations", warmed, len(translations), *source)
+}
+
+func loadPopular(ctx context.Context, translationCache *cache.RedisCache, repo repository.TranslationRepository, limit int) ([]model.Translation, error) {
+	ids, err := translationCache.PopularIDs(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		log.Println("No popularity data recorded yet, try -source approved")
+	}
+	return repo.GetByIDsAcrossTenants(ctx, ids)
+}
+
+// loadApproved walks approved translations of the global translation
+// memory by votes using keyset pages
+func loadApproved(ctx context.Context, repo repository.TranslationRepository, limit int) ([]model.Translation, error) {
+	filter := repository.TranslationFilter{
+		Status:    model.TranslationStatusApproved,
+		SortBy:    repository.SortVotes,
+		SortOrder: repository.SortDesc,
+	}
+
+	var translations []model.Translation
+	for len(translations) < limit {
+		filter.PageSize = repository.MaxPageSize
+		if remaining := limit - len(translations); remaining < filter.PageSize {
+			filter.PageSize = remaining
+		}
+
+		page, err := repo.List(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, page...)
+		if len(page) < filter.PageSize {
+			break
+		}
+
+		last := page[len(page)-1]
+		cursor := repository.NewCursor(filter.SortBy, filter.SortOrder, &last, repository.CursorNext)
+		filter.Cursor = &cursor
+	}
+
+	return translations, nil
+}
@@ -1,24 +1,27 @@
 package main
 
 import (
+	"flag"
 	"log"
-	"os"
 
 	"github.com/vietgs03/translate/backend/internal/config"
 	"github.com/vietgs03/translate/backend/internal/database"
 )
 
 func main() {
-	cfg, err := config.LoadConfig()
+	flags := config.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	cfg, err := config.Load(flags)
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	if len(os.Args) < 2 {
+	if flag.NArg() < 1 {
 		log.Fatal("Migration direction (up/down) is required")
 	}
 
-	direction := os.Args[1]
+	direction := flag.Arg(0)
 	if direction != "up" && direction != "down" {
 		log.Fatal("Migration direction must be either 'up' or 'down'")
 	}
@@ -0,0 +1,28 @@
+// Package audit carries the details of the HTTP request an audited action
+// happens in down to the services that record it.
+package audit
+
+import "context"
+
+// Request identifies where an action came from
+type Request struct {
+	IP        string
+	RequestID string
+}
+
+// RequestKey is the key the Request is stored under. Middleware stores it
+// in Fiber's locals, which c.UserContext() exposes through Value, so
+// services see it without any change to their signatures.
+type RequestKey struct{}
+
+// ContextWithRequest returns a copy of ctx carrying r
+func ContextWithRequest(ctx context.Context, r Request) context.Context {
+	return context.WithValue(ctx, RequestKey{}, r)
+}
+
+// RequestFromContext returns the Request of ctx, or the zero Request
+// outside of an HTTP request
+func RequestFromContext(ctx context.Context) Request {
+	r, _ := ctx.Value(RequestKey{}).(Request)
+	return r
+}
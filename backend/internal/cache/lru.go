@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lru is a size-bounded, TTL-aware least-recently-used map safe for
+// concurrent use. Expired entries are dropped lazily on access.
+type lru struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	items   map[string]*list.Element
+	order   *list.List
+	nowFunc func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+func newLRU(size int, ttl time.Duration) *lru {
+	return &lru{
+		size:    size,
+		ttl:     ttl,
+		items:   make(map[string]*list.Element),
+		order:   list.New(),
+		nowFunc: time.Now,
+	}
+}
+
+func (l *lru) Get(key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && l.nowFunc().After(entry.expiresAt) {
+		l.removeElement(elem)
+		return nil, false
+	}
+
+	l.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Peek returns an entry and its expiry without touching its recency.
+func (l *lru) Peek(key string) ([]byte, time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.items[key]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && l.nowFunc().After(entry.expiresAt) {
+		return nil, time.Time{}, false
+	}
+	return entry.value, entry.expiresAt, true
+}
+
+func (l *lru) Set(key string, value []byte) {
+	l.SetWithTTL(key, value, l.ttl)
+}
+
+func (l *lru) SetWithTTL(key string, value []byte, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = l.nowFunc().Add(ttl)
+	}
+
+	if elem, ok := l.items[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		l.order.MoveToFront(elem)
+		return
+	}
+
+	l.items[key] = l.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for l.size > 0 && l.order.Len() > l.size {
+		l.removeElement(l.order.Back())
+	}
+}
+
+func (l *lru) Delete(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elem, ok := l.items[key]; ok {
+		l.removeElement(elem)
+	}
+}
+
+// DeleteFunc removes every entry for which match returns true and reports
+// how many were removed.
+func (l *lru) DeleteFunc(match func(key string, value []byte) bool) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	removed := 0
+	for elem := l.order.Front(); elem != nil; {
+		next := elem.Next()
+		entry := elem.Value.(*lruEntry)
+		if match(entry.key, entry.value) {
+			l.removeElement(elem)
+			removed++
+		}
+		elem = next
+	}
+	return removed
+}
+
+func (l *lru) Purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.items = make(map[string]*list.Element)
+	l.order.Init()
+}
+
+func (l *lru) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+func (l *lru) removeElement(elem *list.Element) {
+	l.order.Remove(elem)
+	delete(l.items, elem.Value.(*lruEntry).key)
+}
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	t.Run("EvictsLeastRecentlyUsed", func(t *testing.T) {
+		l := newLRU(2, 0)
+		l.Set("a", []byte("1"))
+		l.Set("b", []byte("2"))
+
+		// Touch a so b becomes the eviction candidate
+		_, ok := l.Get("a")
+		assert.True(t, ok)
+
+		l.Set("c", []byte("3"))
+		assert.Equal(t, 2, l.Len())
+
+		_, ok = l.Get("b")
+		assert.False(t, ok)
+		value, ok := l.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("1"), value)
+	})
+
+	t.Run("ExpiresEntries", func(t *testing.T) {
+		now := time.Now()
+		l := newLRU(10, time.Minute)
+		l.nowFunc = func() time.Time { return now }
+
+		l.Set("a", []byte("1"))
+		_, ok := l.Get("a")
+		assert.True(t, ok)
+
+		now = now.Add(2 * time.Minute)
+		_, ok = l.Get("a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, l.Len())
+	})
+
+	t.Run("DeleteAndPurge", func(t *testing.T) {
+		l := newLRU(10, 0)
+		l.Set("a", []byte("1"))
+		l.Set("b", []byte("2"))
+
+		l.Delete("a")
+		_, ok := l.Get("a")
+		assert.False(t, ok)
+
+		l.Purge()
+		assert.Equal(t, 0, l.Len())
+	})
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vietgs03/translate/backend/internal/config"
+	"github.com/vietgs03/translate/backend/internal/model"
+)
+
+var _ TranslationCache = (*MemoryCache)(nil) // Verify interface implementation
+
+// MemoryCache keeps translations in a process-local LRU. It needs no Redis
+// server, which suits local development, tests and single-replica
+// deployments; entries are not shared between replicas.
+type MemoryCache struct {
+	namespace string
+	entries   *lru
+	stats     *statsRecorder
+}
+
+func NewMemoryCache(cfg *config.CacheConfig) *MemoryCache {
+	return &MemoryCache{
+		namespace: cfg.Namespace,
+		entries:   newLRU(cfg.MemorySize, cacheTTL(cfg)),
+		stats:     newStatsRecorder(),
+	}
+}
+
+func (c *MemoryCache) Set(ctx context.Context, translation *model.Translation) error {
+	key := generateKey(c.namespace, projectOf(translation), translation.SourceText, translation.SourceLanguage, translation.TargetLanguage)
+	data, err := json.Marshal(translation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal translation: %v", err)
+	}
+
+	c.entries.Set(key, data)
+	return nil
+}
+
+func (c *MemoryCache) Get(ctx context.Context, projectID uint, sourceText, sourceLang, targetLang string) (*model.Translation, error) {
+	data, ok := c.entries.Get(generateKey(c.namespace, projectID, sourceText, sourceLang, targetLang))
+	if !ok {
+		c.stats.miss(TierMemory, sourceLang, targetLang)
+		return nil, nil // Cache miss
+	}
+
+	// Entries are stored encoded so callers can never mutate the cached copy
+	var translation model.Translation
+	if err := json.Unmarshal(data, &translation); err != nil {
+		c.stats.failure(TierMemory, sourceLang, targetLang)
+		return nil, fmt.Errorf("failed to unmarshal translation: %v", err)
+	}
+
+	c.stats.hit(TierMemory, sourceLang, targetLang)
+	return &translation, nil
+}
+
+func (c *MemoryCache) Delete(ctx context.Context, projectID uint, sourceText, sourceLang, targetLang string) error {
+	c.entries.Delete(generateKey(c.namespace, projectID, sourceText, sourceLang, targetLang))
+	return nil
+}
+
+func (c *MemoryCache) Purge(ctx context.Context, filter PurgeFilter) (int, error) {
+	return c.entries.DeleteFunc(func(key string, value []byte) bool {
+		var translation model.Translation
+		if err := json.Unmarshal(value, &translation); err != nil {
+			return true // Drop entries that can't be decoded anyway
+		}
+		return filter.matches(&translation)
+	}), nil
+}
+
+func (c *MemoryCache) Inspect(ctx context.Context, projectID uint, sourceText, sourceLang, targetLang string) (*Entry, error) {
+	key := generateKey(c.namespace, projectID, sourceText, sourceLang, targetLang)
+	data, expiresAt, ok := c.entries.Peek(key)
+	if !ok {
+		return nil, nil
+	}
+
+	var translation model.Translation
+	if err := json.Unmarshal(data, &translation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal translation: %v", err)
+	}
+
+	entry := &Entry{Key: key, Tier: TierMemory, TTLSeconds: -1, Translation: &translation}
+	if !expiresAt.IsZero() {
+		entry.TTLSeconds = int64(time.Until(expiresAt).Seconds())
+	}
+	return entry, nil
+}
+
+func (c *MemoryCache) Stats() Stats {
+	return c.stats.snapshot()
+}
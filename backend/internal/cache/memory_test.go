@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vietgs03/translate/backend/internal/config"
+	"github.com/vietgs03/translate/backend/internal/model"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(&config.CacheConfig{Namespace: "test", TTL: 1, MemorySize: 100})
+
+	translations := []*model.Translation{
+		{ID: 1, SourceText: "commit", SourceLanguage: "en", TargetLanguage: "vi", Category: "git"},
+		{ID: 2, SourceText: "branch", SourceLanguage: "en", TargetLanguage: "vi", Category: "git"},
+		{ID: 3, SourceText: "thread", SourceLanguage: "en", TargetLanguage: "ja", Category: "os"},
+	}
+	for _, translation := range translations {
+		require.NoError(t, c.Set(ctx, translation))
+	}
+
+	t.Run("StatsPerPair", func(t *testing.T) {
+		_, _ = c.Get(ctx, 0, "commit", "en", "vi")
+		_, _ = c.Get(ctx, 0, "merge", "en", "vi")
+
+		stats := c.Stats()
+		assert.Equal(t, uint64(1), stats.Tiers[TierMemory]["en-vi"].Hits)
+		assert.Equal(t, uint64(1), stats.Tiers[TierMemory]["en-vi"].Misses)
+		assert.Equal(t, 0.5, stats.Totals[TierMemory].HitRatio)
+	})
+
+	t.Run("Inspect", func(t *testing.T) {
+		entry, err := c.Inspect(ctx, 0, "thread", "en", "ja")
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+		assert.Equal(t, TierMemory, entry.Tier)
+		assert.Equal(t, uint(3), entry.Translation.ID)
+		assert.Positive(t, entry.TTLSeconds)
+	})
+
+	t.Run("PurgeByCategory", func(t *testing.T) {
+		purged, err := c.Purge(ctx, PurgeFilter{Category: "git"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, purged)
+
+		cached, err := c.Get(ctx, 0, "thread", "en", "ja")
+		require.NoError(t, err)
+		assert.NotNil(t, cached)
+	})
+
+	t.Run("PurgeByPair", func(t *testing.T) {
+		purged, err := c.Purge(ctx, PurgeFilter{SourceLanguage: "EN", TargetLanguage: "ja"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+	})
+}
@@ -0,0 +1,409 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/vietgs03/translate/backend/internal/config"
+	"github.com/vietgs03/translate/backend/internal/logging"
+	"github.com/vietgs03/translate/backend/internal/model"
+	"go.uber.org/zap"
+)
+
+var _ TranslationCache = (*RedisCache)(nil) // Verify interface implementation
+
+const (
+	// popularityKey ranks translation IDs by lookups, whether answered from
+	// the cache or written to it after a miss. It is shared by all
+	// namespaces because IDs outlive a namespace change.
+	popularityKey = "translation:popularity"
+
+	popularityFlushInterval = 10 * time.Second
+	purgeScanCount          = 500
+)
+
+// RedisCache is a two-tier cache: a small in-process LRU in front of
+// Redis. Mutations are broadcast over Redis pub/sub so other replicas drop
+// their local copies.
+type RedisCache struct {
+	redis     *redis.Client
+	ttl       time.Duration
+	namespace string
+	local     *lru
+	instance  string
+	stats     *statsRecorder
+
+	// Lookups are counted in process and flushed to popularityKey in
+	// batches so a local hit never costs a Redis round-trip.
+	popularityMu sync.Mutex
+	popularity   map[uint]int64
+}
+
+// invalidation is published on the invalidation channel. An empty Key
+// means every local entry must be dropped.
+type invalidation struct {
+	Origin string `json:"origin"`
+	Key    string `json:"key,omitempty"`
+}
+
+func NewRedisCache(redis *redis.Client, cfg *config.CacheConfig) *RedisCache {
+	c := &RedisCache{
+		redis:      redis,
+		ttl:        cacheTTL(cfg),
+		namespace:  cfg.Namespace,
+		instance:   uuid.NewString(),
+		stats:      newStatsRecorder(),
+		popularity: make(map[uint]int64),
+	}
+	if cfg.LocalSize > 0 {
+		c.local = newLRU(cfg.LocalSize, time.Duration(cfg.LocalTTL)*time.Second)
+	}
+
+	return c
+}
+
+func (c *RedisCache) generateKey(projectID uint, sourceText, sourceLang, targetLang string) string {
+	return generateKey(c.namespace, projectID, sourceText, sourceLang, targetLang)
+}
+
+func (c *RedisCache) channel() string {
+	return fmt.Sprintf("translation:%s:invalidate", c.namespace)
+}
+
+func (c *RedisCache) Set(ctx context.Context, translation *model.Translation) error {
+	key := c.generateKey(projectOf(translation), translation.SourceText, translation.SourceLanguage, translation.TargetLanguage)
+	data, err := json.Marshal(translation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal translation: %v", err)
+	}
+
+	if err := c.redis.Set(ctx, key, data, c.ttl).Err(); err != nil {
+		return err
+	}
+
+	if c.local != nil {
+		c.local.Set(key, data)
+	}
+	c.publish(ctx, key)
+
+	// The service writes what it resolved after a miss; counting it lets
+	// entries that are popular but not cached yet rank too
+	c.recordLookup(translation.ID)
+	return nil
+}
+
+func (c *RedisCache) Get(ctx context.Context, projectID uint, sourceText, sourceLang, targetLang string) (*model.Translation, error) {
+	key := c.generateKey(projectID, sourceText, sourceLang, targetLang)
+
+	data, ok := c.getLocal(key)
+	if ok {
+		c.stats.hit(TierLocal, sourceLang, targetLang)
+	} else {
+		if c.local != nil {
+			c.stats.miss(TierLocal, sourceLang, targetLang)
+		}
+
+		var err error
+		data, err = c.redis.Get(ctx, key).Bytes()
+		if err != nil {
+			if err == redis.Nil {
+				c.stats.miss(TierRedis, sourceLang, targetLang)
+				return nil, nil // Cache miss
+			}
+			c.stats.failure(TierRedis, sourceLang, targetLang)
+			return nil, err
+		}
+		c.stats.hit(TierRedis, sourceLang, targetLang)
+
+		if c.local != nil {
+			c.local.Set(key, data)
+		}
+	}
+
+	var translation model.Translation
+	if err := json.Unmarshal(data, &translation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal translation: %v", err)
+	}
+
+	c.recordLookup(translation.ID)
+	return &translation, nil
+}
+
+func (c *RedisCache) Delete(ctx context.Context, projectID uint, sourceText, sourceLang, targetLang string) error {
+	key := c.generateKey(projectID, sourceText, sourceLang, targetLang)
+	if c.local != nil {
+		c.local.Delete(key)
+	}
+
+	if err := c.redis.Del(ctx, key).Err(); err != nil {
+		return err
+	}
+
+	c.publish(ctx, key)
+	return nil
+}
+
+// Purge scans the namespace for matching keys. A project or language pair
+// narrows the scan pattern; a category requires reading each value.
+func (c *RedisCache) Purge(ctx context.Context, filter PurgeFilter) (int, error) {
+	pattern := c.purgePattern(filter)
+
+	removed := 0
+	var cursor uint64
+	for {
+		keys, next, err := c.redis.Scan(ctx, cursor, pattern, purgeScanCount).Result()
+		if err != nil {
+			return removed, fmt.Errorf("failed to scan cache keys: %v", err)
+		}
+
+		if filter.Category != "" && len(keys) > 0 {
+			keys, err = c.keysInCategory(ctx, keys, filter.Category)
+			if err != nil {
+				return removed, err
+			}
+		}
+
+		if len(keys) > 0 {
+			deleted, err := c.redis.Del(ctx, keys...).Result()
+			if err != nil {
+				return removed, fmt.Errorf("failed to delete cache keys: %v", err)
+			}
+			removed += int(deleted)
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	// Local tiers can't be filtered by key pattern cheaply, drop them whole
+	if c.local != nil {
+		c.local.Purge()
+	}
+	c.publish(ctx, "")
+
+	return removed, nil
+}
+
+// purgePattern matches the keys filter may select. Filter values are
+// escaped so a "*" in them can't widen the purge.
+func (c *RedisCache) purgePattern(filter PurgeFilter) string {
+	project, sourceLang, targetLang := "*", "*", "*"
+	if filter.ProjectID != nil {
+		project = projectSegment(*filter.ProjectID)
+	}
+	if filter.SourceLanguage != "" {
+		sourceLang = escapeGlob(filter.SourceLanguage)
+	}
+	if filter.TargetLanguage != "" {
+		targetLang = escapeGlob(filter.TargetLanguage)
+	}
+	return keyPrefix(escapeGlob(c.namespace), project, sourceLang, targetLang) + "*"
+}
+
+// globEscaper quotes the characters special to Redis MATCH patterns
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+func escapeGlob(s string) string {
+	return globEscaper.Replace(s)
+}
+
+func (c *RedisCache) keysInCategory(ctx context.Context, keys []string, category string) ([]string, error) {
+	values, err := c.redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache entries: %v", err)
+	}
+
+	var matched []string
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue // Expired between SCAN and MGET
+		}
+
+		var translation model.Translation
+		if err := json.Unmarshal([]byte(raw), &translation); err != nil || translation.Category == category {
+			matched = append(matched, keys[i])
+		}
+	}
+	return matched, nil
+}
+
+func (c *RedisCache) Inspect(ctx context.Context, projectID uint, sourceText, sourceLang, targetLang string) (*Entry, error) {
+	key := c.generateKey(projectID, sourceText, sourceLang, targetLang)
+	entry := &Entry{Key: key}
+
+	if data, expiresAt, ok := c.peekLocal(key); ok {
+		entry.Tier = TierLocal
+		entry.TTLSeconds = -1
+		if !expiresAt.IsZero() {
+			entry.TTLSeconds = int64(time.Until(expiresAt).Seconds())
+		}
+		if err := json.Unmarshal(data, &entry.Translation); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal translation: %v", err)
+		}
+		return entry, nil
+	}
+
+	data, err := c.redis.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	ttl, err := c.redis.TTL(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Tier = TierRedis
+	entry.TTLSeconds = int64(ttl.Seconds())
+	if err := json.Unmarshal(data, &entry.Translation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal translation: %v", err)
+	}
+	return entry, nil
+}
+
+func (c *RedisCache) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+// PopularIDs returns up to n translation IDs ordered by lookups.
+func (c *RedisCache) PopularIDs(ctx context.Context, n int) ([]uint, error) {
+	members, err := c.redis.ZRevRange(ctx, popularityKey, 0, int64(n-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseUint(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
+
+// Run applies invalidations published by other replicas to the local tier
+// and flushes hit counts until ctx is cancelled.
+func (c *RedisCache) Run(ctx context.Context) {
+	if c.local != nil {
+		go c.listen(ctx)
+	}
+
+	ticker := time.NewTicker(popularityFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Use a fresh context, the run context is already done
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			c.flushPopularity(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			c.flushPopularity(ctx)
+		}
+	}
+}
+
+func (c *RedisCache) listen(ctx context.Context) {
+	sub := c.redis.Subscribe(ctx, c.channel())
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-sub.Channel():
+			if !ok {
+				return
+			}
+
+			var inv invalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+				logging.FromContext(ctx).Warn("ignoring malformed cache invalidation", zap.Error(err))
+				continue
+			}
+			if inv.Origin == c.instance {
+				continue
+			}
+
+			if inv.Key == "" {
+				c.local.Purge()
+			} else {
+				c.local.Delete(inv.Key)
+			}
+		}
+	}
+}
+
+func (c *RedisCache) recordLookup(id uint) {
+	if id == 0 {
+		return
+	}
+
+	c.popularityMu.Lock()
+	c.popularity[id]++
+	c.popularityMu.Unlock()
+}
+
+func (c *RedisCache) flushPopularity(ctx context.Context) {
+	c.popularityMu.Lock()
+	pending := c.popularity
+	c.popularity = make(map[uint]int64)
+	c.popularityMu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	pipe := c.redis.Pipeline()
+	for id, hits := range pending {
+		pipe.ZIncrBy(ctx, popularityKey, float64(hits), strconv.FormatUint(uint64(id), 10))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		logging.FromContext(ctx).Warn("failed to flush translation popularity", zap.Int("translations", len(pending)), zap.Error(err))
+	}
+}
+
+func (c *RedisCache) getLocal(key string) ([]byte, bool) {
+	if c.local == nil {
+		return nil, false
+	}
+	return c.local.Get(key)
+}
+
+func (c *RedisCache) peekLocal(key string) ([]byte, time.Time, bool) {
+	if c.local == nil {
+		return nil, time.Time{}, false
+	}
+	return c.local.Peek(key)
+}
+
+// publish tells other replicas to drop key from their local tier, or all
+// of it when key is empty. Delivery is best effort; the local TTL bounds
+// staleness if a message is lost.
+func (c *RedisCache) publish(ctx context.Context, key string) {
+	if c.local == nil {
+		return
+	}
+
+	payload, _ := json.Marshal(invalidation{Origin: c.instance, Key: key})
+	if err := c.redis.Publish(ctx, c.channel(), payload).Err(); err != nil {
+		logging.FromContext(ctx).Warn("failed to publish cache invalidation", zap.Error(err))
+	}
+}
@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vietgs03/translate/backend/internal/config"
+)
+
+func TestGenerateKey(t *testing.T) {
+	c := NewRedisCache(nil, &config.CacheConfig{Namespace: "v1", TTL: 24})
+
+	key := c.generateKey(0, "  Hello\n  world ", "EN", "vi")
+	assert.Equal(t, c.generateKey(0, "Hello world", "en", "vi"), key)
+	assert.True(t, strings.HasPrefix(key, "translation:v1:s3:p0:en:vi:"))
+	assert.NotContains(t, key, "Hello")
+
+	long := strings.Repeat("dependency injection ", 500)
+	assert.Len(t, c.generateKey(0, long, "en", "vi"), len(key))
+
+	assert.NotEqual(t, key, c.generateKey(7, "Hello world", "en", "vi"), "projects do not share entries")
+
+	other := NewRedisCache(nil, &config.CacheConfig{Namespace: "v2", TTL: 24})
+	assert.NotEqual(t, key, other.generateKey(0, "Hello world", "en", "vi"))
+}
+
+func TestPurgePattern(t *testing.T) {
+	c := NewRedisCache(nil, &config.CacheConfig{Namespace: "v1", TTL: 24})
+	project := uint(3)
+
+	assert.Equal(t, "translation:v1:s3:*:*:*:*", c.purgePattern(PurgeFilter{}))
+	assert.Equal(t, "translation:v1:s3:p3:en:vi:*", c.purgePattern(PurgeFilter{ProjectID: &project, SourceLanguage: "EN", TargetLanguage: "vi"}))
+
+	// Glob characters in filter values only match themselves
+	assert.Equal(t, `translation:v1:s3:*:\*:\[a-z\]\?:*`, c.purgePattern(PurgeFilter{SourceLanguage: "*", TargetLanguage: "[a-z]?"}))
+}
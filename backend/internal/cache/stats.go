@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"strings"
+	"sync"
+)
+
+const (
+	TierLocal  = "local"
+	TierRedis  = "redis"
+	TierMemory = "memory"
+)
+
+// Counters are the lookup outcomes for one tier and language pair.
+type Counters struct {
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	Errors   uint64  `json:"errors"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// Stats is a point-in-time copy of the counters of this process, grouped
+// by tier and then by language pair ("en-vi").
+type Stats struct {
+	Tiers  map[string]map[string]Counters `json:"tiers"`
+	Totals map[string]Counters            `json:"totals"`
+}
+
+type statsKey struct {
+	tier string
+	pair string
+}
+
+type statsRecorder struct {
+	mu       sync.Mutex
+	counters map[statsKey]*Counters
+}
+
+func newStatsRecorder() *statsRecorder {
+	return &statsRecorder{counters: make(map[statsKey]*Counters)}
+}
+
+func (r *statsRecorder) hit(tier, sourceLang, targetLang string) {
+	r.record(tier, sourceLang, targetLang, func(c *Counters) { c.Hits++ })
+}
+
+func (r *statsRecorder) miss(tier, sourceLang, targetLang string) {
+	r.record(tier, sourceLang, targetLang, func(c *Counters) { c.Misses++ })
+}
+
+func (r *statsRecorder) failure(tier, sourceLang, targetLang string) {
+	r.record(tier, sourceLang, targetLang, func(c *Counters) { c.Errors++ })
+}
+
+func (r *statsRecorder) record(tier, sourceLang, targetLang string, update func(*Counters)) {
+	key := statsKey{tier: tier, pair: languagePair(sourceLang, targetLang)}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counters, ok := r.counters[key]
+	if !ok {
+		counters = &Counters{}
+		r.counters[key] = counters
+	}
+	update(counters)
+}
+
+func (r *statsRecorder) snapshot() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := Stats{
+		Tiers:  make(map[string]map[string]Counters),
+		Totals: make(map[string]Counters),
+	}
+	for key, counters := range r.counters {
+		if stats.Tiers[key.tier] == nil {
+			stats.Tiers[key.tier] = make(map[string]Counters)
+		}
+		stats.Tiers[key.tier][key.pair] = withRatio(*counters)
+
+		total := stats.Totals[key.tier]
+		total.Hits += counters.Hits
+		total.Misses += counters.Misses
+		total.Errors += counters.Errors
+		stats.Totals[key.tier] = withRatio(total)
+	}
+
+	return stats
+}
+
+func withRatio(c Counters) Counters {
+	if lookups := c.Hits + c.Misses; lookups > 0 {
+		c.HitRatio = float64(c.Hits) / float64(lookups)
+	}
+	return c
+}
+
+func languagePair(sourceLang, targetLang string) string {
+	return strings.ToLower(sourceLang) + "-" + strings.ToLower(targetLang)
+}
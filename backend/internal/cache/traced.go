@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/vietgs03/translate/backend/internal/model"
+	"github.com/vietgs03/translate/backend/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/vietgs03/translate/backend/internal/cache")
+
+// tracedCache records a span for every call to the cache it wraps
+type tracedCache struct {
+	TranslationCache
+	backend string
+}
+
+// NewTracedCache wraps cache so each call shows up in the trace of the
+// request making it
+func NewTracedCache(cache TranslationCache, backend string) TranslationCache {
+	return &tracedCache{TranslationCache: cache, backend: backend}
+}
+
+func (c *tracedCache) start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "TranslationCache."+operation, trace.WithAttributes(
+		append(attrs, attribute.String("cache.backend", c.backend))...,
+	))
+}
+
+func (c *tracedCache) Get(ctx context.Context, projectID uint, sourceText, sourceLang, targetLang string) (*model.Translation, error) {
+	ctx, span := c.start(ctx, "Get", lookupAttributes(projectID, sourceLang, targetLang)...)
+	translation, err := c.TranslationCache.Get(ctx, projectID, sourceText, sourceLang, targetLang)
+	span.SetAttributes(attribute.Bool("cache.hit", translation != nil))
+	tracing.End(span, err)
+	return translation, err
+}
+
+func (c *tracedCache) Set(ctx context.Context, translation *model.Translation) error {
+	ctx, span := c.start(ctx, "Set", lookupAttributes(projectOf(translation), translation.SourceLanguage, translation.TargetLanguage)...)
+	err := c.TranslationCache.Set(ctx, translation)
+	tracing.End(span, err)
+	return err
+}
+
+func (c *tracedCache) Delete(ctx context.Context, projectID uint, sourceText, sourceLang, targetLang string) error {
+	ctx, span := c.start(ctx, "Delete", lookupAttributes(projectID, sourceLang, targetLang)...)
+	err := c.TranslationCache.Delete(ctx, projectID, sourceText, sourceLang, targetLang)
+	tracing.End(span, err)
+	return err
+}
+
+func (c *tracedCache) Purge(ctx context.Context, filter PurgeFilter) (int, error) {
+	ctx, span := c.start(ctx, "Purge")
+	removed, err := c.TranslationCache.Purge(ctx, filter)
+	span.SetAttributes(attribute.Int("cache.removed", removed))
+	tracing.End(span, err)
+	return removed, err
+}
+
+func (c *tracedCache) Inspect(ctx context.Context, projectID uint, sourceText, sourceLang, targetLang string) (*Entry, error) {
+	ctx, span := c.start(ctx, "Inspect", lookupAttributes(projectID, sourceLang, targetLang)...)
+	entry, err := c.TranslationCache.Inspect(ctx, projectID, sourceText, sourceLang, targetLang)
+	tracing.End(span, err)
+	return entry, err
+}
+
+// lookupAttributes describe a lookup; the text itself is left out of traces
+func lookupAttributes(projectID uint, sourceLang, targetLang string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Int("translation.project_id", int(projectID)),
+		attribute.String("translation.source_language", sourceLang),
+		attribute.String("translation.target_language", targetLang),
+	}
+}
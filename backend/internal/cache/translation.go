@@ -2,59 +2,108 @@ package cache
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 
-	"github.com/redis/go-redis/v9"
+	"github.com/vietgs03/translate/backend/internal/config"
 	"github.com/vietgs03/translate/backend/internal/model"
 )
 
-type TranslationCache struct {
-	redis *redis.Client
-	ttl   time.Duration
+// schemaVersion must be bumped whenever the cached model.Translation
+// encoding changes, so old entries are never decoded into the new shape.
+const schemaVersion = 3
+
+const (
+	BackendRedis  = "redis"
+	BackendMemory = "memory"
+)
+
+// TranslationCache stores finished translations keyed by project, source
+// text and language pair; project 0 is the global translation memory. Get
+// and Inspect return (nil, nil) on a miss.
+type TranslationCache interface {
+	Get(ctx context.Context, projectID uint, sourceText, sourceLang, targetLang string) (*model.Translation, error)
+	// Set stores the translation under its own ProjectID
+	Set(ctx context.Context, translation *model.Translation) error
+	Delete(ctx context.Context, projectID uint, sourceText, sourceLang, targetLang string) error
+	// Purge removes every entry matching the filter and returns how many were removed
+	Purge(ctx context.Context, filter PurgeFilter) (int, error)
+	Inspect(ctx context.Context, projectID uint, sourceText, sourceLang, targetLang string) (*Entry, error)
+	Stats() Stats
+}
+
+// PurgeFilter selects entries by project, language pair, category or any
+// combination. Empty fields match everything; ProjectID 0 is the global
+// translation memory.
+type PurgeFilter struct {
+	ProjectID      *uint
+	SourceLanguage string
+	TargetLanguage string
+	Category       string
 }
 
-func NewTranslationCache(redis *redis.Client) *TranslationCache {
-	return &TranslationCache{
-		redis: redis,
-		ttl:   24 * time.Hour, // Cache for 24 hours
+func (f PurgeFilter) matches(translation *model.Translation) bool {
+	if f.ProjectID != nil && *f.ProjectID != projectOf(translation) {
+		return false
+	}
+	if f.SourceLanguage != "" && !strings.EqualFold(f.SourceLanguage, translation.SourceLanguage) {
+		return false
 	}
+	if f.TargetLanguage != "" && !strings.EqualFold(f.TargetLanguage, translation.TargetLanguage) {
+		return false
+	}
+	return f.Category == "" || f.Category == translation.Category
 }
 
-func (c *TranslationCache) generateKey(sourceText, sourceLang, targetLang string) string {
-	return fmt.Sprintf("translation:%s:%s:%s", sourceLang, targetLang, sourceText)
+// Entry describes a single cached translation for administration.
+type Entry struct {
+	Key         string             `json:"key"`
+	Tier        string             `json:"tier"`
+	TTLSeconds  int64              `json:"ttl_seconds"`
+	Translation *model.Translation `json:"translation"`
 }
 
-func (c *TranslationCache) Set(ctx context.Context, translation *model.Translation) error {
-	key := c.generateKey(translation.SourceText, translation.SourceLanguage, translation.TargetLanguage)
-	data, err := json.Marshal(translation)
-	if err != nil {
-		return fmt.Errorf("failed to marshal translation: %v", err)
-	}
+// NormalizeText trims and collapses whitespace so trivially different
+// spellings of the same input share one cache entry.
+func NormalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
 
-	return c.redis.Set(ctx, key, data, c.ttl).Err()
+func generateKey(namespace string, projectID uint, sourceText, sourceLang, targetLang string) string {
+	sum := sha256.Sum256([]byte(NormalizeText(sourceText)))
+	return keyPrefix(namespace, projectSegment(projectID), sourceLang, targetLang) + hex.EncodeToString(sum[:])
 }
 
-func (c *TranslationCache) Get(ctx context.Context, sourceText, sourceLang, targetLang string) (*model.Translation, error) {
-	key := c.generateKey(sourceText, sourceLang, targetLang)
-	data, err := c.redis.Get(ctx, key).Bytes()
-	if err != nil {
-		if err == redis.Nil {
-			return nil, nil // Cache miss
-		}
-		return nil, err
-	}
+// keyPrefix is the part of the key shared by all entries of a project and
+// language pair; "*" for every part matches the whole namespace.
+func keyPrefix(namespace, project, sourceLang, targetLang string) string {
+	return fmt.Sprintf("translation:%s:s%d:%s:%s:%s:",
+		namespace,
+		schemaVersion,
+		project,
+		strings.ToLower(sourceLang),
+		strings.ToLower(targetLang),
+	)
+}
 
-	var translation model.Translation
-	if err := json.Unmarshal(data, &translation); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal translation: %v", err)
-	}
+func projectSegment(projectID uint) string {
+	return fmt.Sprintf("p%d", projectID)
+}
 
-	return &translation, nil
+func projectOf(translation *model.Translation) uint {
+	if translation.ProjectID == nil {
+		return 0
+	}
+	return *translation.ProjectID
 }
 
-func (c *TranslationCache) Delete(ctx context.Context, sourceText, sourceLang, targetLang string) error {
-	key := c.generateKey(sourceText, sourceLang, targetLang)
-	return c.redis.Del(ctx, key).Err()
-} 
\ No newline at end of file
+func cacheTTL(cfg *config.CacheConfig) time.Duration {
+	ttl := time.Duration(cfg.TTL) * time.Hour
+	if ttl <= 0 {
+		ttl = 24 * time.Hour // Cache for 24 hours
+	}
+	return ttl
+}
@@ -1,9 +1,9 @@
 package config
 
 import (
-	"github.com/joho/godotenv"
-	"os"
+	"fmt"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
@@ -11,93 +11,223 @@ type Config struct {
 	Env        string `env:"ENV" default:"development"`
 	Database   DatabaseConfig
 	Redis      RedisConfig
-	OpenAI     OpenAIConfig
+	OpenAI     OpenAIConfig `config:"openai"`
 	JWT        JWTConfig
 	Google     GoogleConfig
+	Cache      CacheConfig
+	OIDC       OIDCConfig
+	Auth       AuthConfig
+	Mail       MailConfig
+	RateLimit  RateLimitConfig
+	Languages  LanguageConfig
+	Tracing    TracingConfig
+	Health     HealthConfig
+	Metrics    MetricsConfig
+
+	// TrustedProxies are the load balancers, by IP or CIDR, whose
+	// ProxyHeader names the client address. Without them the header is
+	// ignored and clients are identified by the connection.
+	TrustedProxies []string `env:"TRUSTED_PROXIES" default:""`
+	ProxyHeader    string   `env:"PROXY_HEADER" default:"X-Forwarded-For"`
+
+	// sources records which layer set each setting, by environment variable
+	sources map[string]string
 }
 
 type DatabaseConfig struct {
 	Host     string `env:"POSTGRES_HOST" default:"localhost"`
 	Port     string `env:"POSTGRES_PORT" default:"5432"`
 	User     string `env:"POSTGRES_USER" default:"postgres"`
-	Password string `env:"POSTGRES_PASSWORD" default:"postgres"`
+	Password string `env:"POSTGRES_PASSWORD" default:"postgres" secret:"true"`
 	DBName   string `env:"POSTGRES_DB" default:"itdev_translator"`
 }
 
 type RedisConfig struct {
 	Host     string `env:"REDIS_HOST" default:"localhost"`
 	Port     string `env:"REDIS_PORT" default:"6379"`
-	Password string `env:"REDIS_PASSWORD" default:""`
+	Password string `env:"REDIS_PASSWORD" default:"" secret:"true"`
 	DB       int    `env:"REDIS_DB" default:"0"`
 }
 
 type OpenAIConfig struct {
-	APIKey string `env:"OPENAI_API_KEY" default:""`
+	APIKey string `env:"OPENAI_API_KEY" default:"" secret:"true"`
 }
 
 type JWTConfig struct {
-	SecretKey string `env:"JWT_SECRET_KEY" default:"your-secret-key"`
-	ExpiresIn int    `env:"JWT_EXPIRES_IN" default:"24"` // hours
+	// Algorithm signs access tokens: "RS256" or "EdDSA"
+	Algorithm        string `env:"JWT_ALGORITHM" default:"RS256"`
+	Issuer           string `env:"JWT_ISSUER" default:"translate-api"`
+	Audience         string `env:"JWT_AUDIENCE" default:"translate-api"`
+	AccessExpiresIn  int    `env:"JWT_ACCESS_EXPIRES_IN" default:"15"`   // minutes
+	RefreshExpiresIn int    `env:"JWT_REFRESH_EXPIRES_IN" default:"720"` // hours
+	// KeyRotation is the lifetime of a signing key before a new one takes over
+	KeyRotation int `env:"JWT_KEY_ROTATION" default:"720"` // hours
+	// KeyOverlap keeps replaced keys verifying, at least AccessExpiresIn
+	KeyOverlap int `env:"JWT_KEY_OVERLAP" default:"60"` // minutes
+	// KeyEncryptionKey is a base64 AES-256 key sealing the private signing
+	// keys stored in the database. Without it they are stored as plain PEM.
+	KeyEncryptionKey string `env:"JWT_KEY_ENCRYPTION_KEY" default:"" secret:"true"`
+}
+
+type CacheConfig struct {
+	// Backend selects the cache implementation: "redis" or "memory"
+	Backend   string `env:"CACHE_BACKEND" default:"redis"`
+	// Namespace is part of every cache key; changing it on deploy orphans all existing entries
+	Namespace string `env:"CACHE_NAMESPACE" default:"v1"`
+	TTL       int    `env:"CACHE_TTL" default:"24"` // hours
+	// LocalSize bounds the in-process LRU tier in entries; 0 disables it
+	LocalSize int `env:"CACHE_LOCAL_SIZE" default:"10000"`
+	LocalTTL  int `env:"CACHE_LOCAL_TTL" default:"300"` // seconds
+	// MemorySize bounds the "memory" backend in entries
+	MemorySize int `env:"CACHE_MEMORY_SIZE" default:"100000"`
+}
+
+type AuthConfig struct {
+	// RequireEmailVerification rejects logins until the email address is verified
+	RequireEmailVerification bool `env:"AUTH_REQUIRE_EMAIL_VERIFICATION" default:"false"`
+	VerificationTTL          int  `env:"AUTH_VERIFICATION_TTL" default:"48"`   // hours
+	PasswordResetTTL         int  `env:"AUTH_PASSWORD_RESET_TTL" default:"60"` // minutes
+	// LinkBaseURL prefixes the links sent by email
+	LinkBaseURL string `env:"AUTH_LINK_BASE_URL" default:"http://localhost:8080/api/v1/auth"`
+	// Failed logins of a username beyond LoginDelayAfter slow down each
+	// further attempt, doubling up to LoginMaxDelay
+	LoginDelayAfter int `env:"AUTH_LOGIN_DELAY_AFTER" default:"3"`
+	LoginMaxDelay   int `env:"AUTH_LOGIN_MAX_DELAY" default:"5"` // seconds
+	// Failures within LockoutWindow that lock a username or client IP for LockoutDuration
+	LockoutThreshold   int `env:"AUTH_LOCKOUT_THRESHOLD" default:"10"`
+	IPLockoutThreshold int `env:"AUTH_IP_LOCKOUT_THRESHOLD" default:"50"`
+	LockoutWindow      int `env:"AUTH_LOCKOUT_WINDOW" default:"15"`   // minutes
+	LockoutDuration    int `env:"AUTH_LOCKOUT_DURATION" default:"15"` // minutes
+}
+
+type RateLimitConfig struct {
+	Window int `env:"RATE_LIMIT_WINDOW" default:"60"` // seconds
+	// Limit is the number of requests per window of each user or API key;
+	// Tiers overrides it by role, e.g. "admin=1000,reader=50"
+	Limit int            `env:"RATE_LIMIT" default:"100"`
+	Tiers map[string]int `env:"RATE_LIMIT_TIERS" default:""`
+	// TranslateLimit is the separate budget of routes that create translations
+	TranslateLimit int            `env:"RATE_LIMIT_TRANSLATE" default:"20"`
+	TranslateTiers map[string]int `env:"RATE_LIMIT_TRANSLATE_TIERS" default:""`
+	// AuthLimit is the budget of each client IP on the public auth routes
+	AuthLimit int `env:"RATE_LIMIT_AUTH" default:"20"`
+	// Allowlist exempts internal services: IPs, CIDRs, "user:<id>" or "key:<id>"
+	Allowlist []string `env:"RATE_LIMIT_ALLOWLIST" default:""`
+}
+
+// LanguageConfig lists the BCP 47 tags translations may use. A tag covers
+// its narrower forms, so "pt" also allows "pt-BR".
+type LanguageConfig struct {
+	Supported []string `env:"LANGUAGES" default:"en,vi,ja,ko,zh-Hans,zh-Hant,fr,de,es,pt,ru,th,id"`
+	// Pairs limits translation to these directions, e.g. "en:vi,vi:en";
+	// empty allows every pair of supported languages
+	Pairs []string `env:"LANGUAGE_PAIRS" default:""`
+}
+
+// TracingConfig sends OpenTelemetry traces to an OTLP collector or stdout
+type TracingConfig struct {
+	// Exporter is "otlp", "stdout" or "none"; with "none" incoming trace
+	// context is still passed on and logged
+	Exporter string `env:"TRACING_EXPORTER" default:"none"`
+	// Endpoint is the URL of the OTLP/HTTP collector
+	Endpoint    string `env:"OTEL_EXPORTER_OTLP_ENDPOINT" default:"http://localhost:4318"`
+	ServiceName string `env:"OTEL_SERVICE_NAME" default:"translate-api"`
+	// SamplePercent of new traces are recorded; traces callers sampled always are
+	SamplePercent int `env:"TRACING_SAMPLE_PERCENT" default:"100"`
+}
+
+// HealthConfig tunes the readiness checks behind /readyz
+type HealthConfig struct {
+	Timeout int `env:"HEALTH_TIMEOUT" default:"2000"` // milliseconds, per check
+	// CacheTTL is how long a report is reused, so probes can't overload
+	// the dependencies
+	CacheTTL int `env:"HEALTH_CACHE_TTL" default:"5"` // seconds
+	// ProviderProbe also checks the translation provider can be reached,
+	// at most once per ProviderProbeInterval; it never makes the service
+	// unready, only degraded
+	ProviderProbe         bool `env:"HEALTH_PROVIDER_PROBE" default:"false"`
+	ProviderProbeInterval int  `env:"HEALTH_PROVIDER_PROBE_INTERVAL" default:"60"` // seconds
+}
+
+// MetricsConfig serves /metrics on its own port, which is not meant to be
+// exposed past the cluster network
+type MetricsConfig struct {
+	Port string `env:"METRICS_PORT" default:"9090"`
+	// Token, when set, must be sent by scrapers as a bearer token
+	Token string `env:"METRICS_TOKEN" default:"" secret:"true"`
+}
+
+type MailConfig struct {
+	// Driver selects the mailer: "smtp", "file" or "log"
+	Driver   string `env:"MAIL_DRIVER" default:"log"`
+	From     string `env:"MAIL_FROM" default:"no-reply@localhost"`
+	SMTPHost string `env:"SMTP_HOST" default:"localhost"`
+	SMTPPort string `env:"SMTP_PORT" default:"587"`
+	SMTPUser string `env:"SMTP_USERNAME" default:""`
+	SMTPPass string `env:"SMTP_PASSWORD" default:"" secret:"true"`
+	// FilePath is where the "file" driver appends messages
+	FilePath string `env:"MAIL_FILE_PATH" default:"mail.log"`
+}
+
+// OIDCConfig enables single sign-on when IssuerURL is set
+type OIDCConfig struct {
+	IssuerURL    string `env:"OIDC_ISSUER_URL" default:""`
+	ClientID     string `env:"OIDC_CLIENT_ID" default:""`
+	ClientSecret string `env:"OIDC_CLIENT_SECRET" default:"" secret:"true"`
+	RedirectURL  string `env:"OIDC_REDIRECT_URL" default:"http://localhost:8080/api/v1/auth/oidc/callback"`
+	Scopes       []string `env:"OIDC_SCOPES" default:"openid,profile,email"`
+	// RoleClaim names the ID token claim, a string or a list of strings,
+	// that is looked up in RoleMapping
+	RoleClaim string `env:"OIDC_ROLE_CLAIM" default:"groups"`
+	// RoleMapping maps claim values to roles, e.g. "translate-admins=admin,linguists=translator"
+	RoleMapping map[string]string `env:"OIDC_ROLE_MAPPING" default:""`
+	// DefaultRole is given to provisioned users no mapping matched
+	DefaultRole string `env:"OIDC_DEFAULT_ROLE" default:"translator"`
+}
+
+func (c OIDCConfig) Enabled() bool {
+	return c.IssuerURL != ""
 }
 
 type GoogleConfig struct {
 	ProjectID         string `env:"GOOGLE_PROJECT_ID"`
 	CredentialsFile   string `env:"GOOGLE_APPLICATION_CREDENTIALS"`
-	GeminiAPIKey      string `env:"GOOGLE_GEMINI_API_KEY"`
+	GeminiAPIKey      string `env:"GOOGLE_GEMINI_API_KEY" secret:"true"`
 }
 
-func LoadConfig() (*Config, error) {
-	if err := godotenv.Load(); err != nil {
-		// Don't return error if .env file doesn't exist
-		// This allows using environment variables without .env file
-	}
-
-	redisDB, err := strconv.Atoi(os.Getenv("REDIS_DB"))
-	if err != nil {
-		redisDB = 0 // Use default if not set or invalid
+// splitList parses a comma separated list, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
+}
 
-	jwtExpiresIn, err := strconv.Atoi(getEnvWithDefault("JWT_EXPIRES_IN", "24"))
-	if err != nil {
-		jwtExpiresIn = 24 // default to 24 hours if invalid
+// parseMapping parses "key=value,key=value"; malformed pairs are skipped
+func parseMapping(value string) map[string]string {
+	mapping := make(map[string]string)
+	for _, pair := range splitList(value) {
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		mapping[strings.TrimSpace(key)] = strings.TrimSpace(val)
 	}
+	return mapping
+}
 
-	return &Config{
-		ServerPort: getEnvWithDefault("SERVER_PORT", "8080"),
-		Env:        getEnvWithDefault("ENV", "development"),
-		Database: DatabaseConfig{
-			Host:     getEnvWithDefault("POSTGRES_HOST", "localhost"),
-			Port:     getEnvWithDefault("POSTGRES_PORT", "5432"),
-			User:     getEnvWithDefault("POSTGRES_USER", "postgres"),
-			Password: getEnvWithDefault("POSTGRES_PASSWORD", "postgres"),
-			DBName:   getEnvWithDefault("POSTGRES_DB", "itdev_translator"),
-		},
-		Redis: RedisConfig{
-			Host:     getEnvWithDefault("REDIS_HOST", "localhost"),
-			Port:     getEnvWithDefault("REDIS_PORT", "6379"),
-			Password: getEnvWithDefault("REDIS_PASSWORD", ""),
-			DB:       redisDB,
-		},
-		OpenAI: OpenAIConfig{
-			APIKey: getEnvWithDefault("OPENAI_API_KEY", ""),
-		},
-		JWT: JWTConfig{
-			SecretKey: getEnvWithDefault("JWT_SECRET_KEY", "your-secret-key"),
-			ExpiresIn: jwtExpiresIn,
-		},
-		Google: GoogleConfig{
-			ProjectID:         getEnvWithDefault("GOOGLE_PROJECT_ID", ""),
-			CredentialsFile:   getEnvWithDefault("GOOGLE_APPLICATION_CREDENTIALS", ""),
-			GeminiAPIKey:       getEnvWithDefault("GOOGLE_GEMINI_API_KEY", ""),
-		},
-	}, nil
-}
-
-func getEnvWithDefault(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
+// parseLimits parses "role=limit,role=limit"
+func parseLimits(value string) (map[string]int, error) {
+	limits := make(map[string]int)
+	for key, val := range parseMapping(value) {
+		limit, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("limit of %q is not a number", key)
+		}
+		limits[key] = limit
 	}
-	return value
+	return limits, nil
 }
-
@@ -0,0 +1,414 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Layers a setting can come from, later ones win
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// redacted replaces the value of secrets in the config dump
+const redacted = "******"
+
+// Flags is the command line layer: -config, -print-config and one flag per
+// setting, named after its environment variable (POSTGRES_HOST becomes
+// -postgres-host).
+type Flags struct {
+	path        string
+	printConfig bool
+	values      map[string]*flagValue
+}
+
+type flagValue struct {
+	value string
+	set   bool
+}
+
+func (v *flagValue) String() string { return v.value }
+
+func (v *flagValue) Set(value string) error {
+	v.value, v.set = value, true
+	return nil
+}
+
+// BindFlags registers the configuration flags on fs; parse fs before Load
+func BindFlags(fs *flag.FlagSet) *Flags {
+	flags := &Flags{values: make(map[string]*flagValue)}
+	fs.StringVar(&flags.path, "config", "", "configuration file, .yaml, .yml or .toml (or CONFIG_FILE)")
+	fs.BoolVar(&flags.printConfig, "print-config", false, "print the effective configuration, secrets redacted, and exit")
+
+	for _, s := range settings(&Config{}) {
+		value := &flagValue{}
+		flags.values[s.env] = value
+		fs.Var(value, flagName(s.env), fmt.Sprintf("overrides %s (default %q)", s.env, s.def))
+	}
+	return flags
+}
+
+// PrintConfig reports whether -print-config was given
+func (f *Flags) PrintConfig() bool {
+	return f != nil && f.printConfig
+}
+
+func flagName(env string) string {
+	return strings.ReplaceAll(strings.ToLower(env), "_", "-")
+}
+
+// LoadConfig loads the configuration without a command line layer
+func LoadConfig() (*Config, error) {
+	return Load(nil)
+}
+
+// Load builds the configuration from the struct tag defaults, then the
+// configuration file, then environment variables (also read from .env),
+// then flags, and validates the result. Every environment variable may be
+// given as NAME_FILE instead, naming a file that holds the value.
+func Load(flags *Flags) (*Config, error) {
+	// A missing .env file is fine, the environment may be set directly
+	_ = godotenv.Load()
+
+	cfg := &Config{sources: make(map[string]string)}
+	all := settings(cfg)
+	var problems []string
+
+	for _, s := range all {
+		if err := s.set(s.def); err != nil {
+			return nil, fmt.Errorf("invalid default of %s: %v", s.env, err)
+		}
+		cfg.sources[s.env] = SourceDefault
+	}
+
+	path := os.Getenv("CONFIG_FILE")
+	if flags != nil && flags.path != "" {
+		path = flags.path
+	}
+	if path != "" {
+		values, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, applyFile(cfg, all, values)...)
+	}
+
+	for _, s := range all {
+		value, ok, err := lookupEnv(s.env)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if err := s.set(value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", s.env, err))
+			continue
+		}
+		cfg.sources[s.env] = SourceEnv
+	}
+
+	if flags != nil {
+		for _, s := range all {
+			value := flags.values[s.env]
+			if value == nil || !value.set {
+				continue
+			}
+			if err := s.set(value.value); err != nil {
+				problems = append(problems, fmt.Sprintf("-%s: %v", flagName(s.env), err))
+				continue
+			}
+			cfg.sources[s.env] = SourceFlag
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n  %s", strings.Join(problems, "\n  "))
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// lookupEnv reads NAME, or the file named by NAME_FILE
+func lookupEnv(name string) (string, bool, error) {
+	value := os.Getenv(name)
+	file := os.Getenv(name + "_FILE")
+	if file == "" {
+		return value, value != "", nil
+	}
+	if value != "" {
+		return "", false, fmt.Errorf("set only one of %s and %s_FILE", name, name)
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", false, fmt.Errorf("%s_FILE: %v", name, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), true, nil
+}
+
+func readFile(path string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	values := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &values)
+	case ".toml":
+		err = toml.Unmarshal(content, &values)
+	default:
+		return nil, fmt.Errorf("config file %s must end in .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+	return values, nil
+}
+
+// applyFile sets the settings found in the file, keyed like
+// "database.host". A key ending in _file names a file holding the value of
+// the key without it, as with environment variables.
+func applyFile(cfg *Config, all []setting, values map[string]interface{}) []string {
+	byKey := make(map[string]setting, len(all))
+	for _, s := range all {
+		byKey[s.key] = s
+	}
+
+	var problems []string
+	for key, raw := range flatten("", values) {
+		s, ok := byKey[key]
+		fromFile := false
+		if !ok {
+			if s, ok = byKey[strings.TrimSuffix(key, "_file")]; ok && strings.HasSuffix(key, "_file") {
+				fromFile = true
+			}
+		}
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown config file key %q", key))
+			continue
+		}
+
+		value, err := fileValue(raw)
+		if err == nil && fromFile {
+			var content []byte
+			content, err = os.ReadFile(value)
+			value = strings.TrimRight(string(content), "\r\n")
+		}
+		if err == nil {
+			err = s.set(value)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		cfg.sources[s.env] = SourceFile
+	}
+	return problems
+}
+
+// flatten turns nested sections into dotted keys; lists and the values of
+// map settings such as rate limit tiers stay whole
+func flatten(prefix string, values map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{})
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if section, ok := value.(map[string]interface{}); ok && prefix == "" {
+			for k, v := range flatten(key, section) {
+				flat[k] = v
+			}
+			continue
+		}
+		flat[key] = value
+	}
+	return flat
+}
+
+// fileValue renders a parsed file value in the syntax of the environment
+// variable, so both go through the same parsing
+func fileValue(raw interface{}) (string, error) {
+	switch v := raw.(type) {
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for i, key := range keys {
+			pairs[i] = fmt.Sprintf("%s=%v", key, v[key])
+		}
+		return strings.Join(pairs, ","), nil
+	case nil:
+		return "", nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// setting is one configurable field, found through its struct tags
+type setting struct {
+	key    string // config file key, e.g. "database.host"
+	env    string
+	def    string
+	secret bool
+	field  reflect.Value
+}
+
+// settings lists the fields of cfg that have an env tag, in declaration order
+func settings(cfg *Config) []setting {
+	var all []setting
+	var walk func(v reflect.Value, prefix string)
+	walk = func(v reflect.Value, prefix string) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			key := field.Tag.Get("config")
+			if key == "" {
+				key = snakeCase(field.Name)
+			}
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+
+			if field.Type.Kind() == reflect.Struct {
+				walk(v.Field(i), key)
+				continue
+			}
+			env := field.Tag.Get("env")
+			if env == "" {
+				continue
+			}
+			all = append(all, setting{
+				key:    key,
+				env:    env,
+				def:    field.Tag.Get("default"),
+				secret: field.Tag.Get("secret") == "true",
+				field:  v.Field(i),
+			})
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem(), "")
+	return all
+}
+
+// set parses value, in the syntax of the environment variable, into the field
+func (s setting) set(value string) error {
+	switch s.field.Interface().(type) {
+	case string:
+		s.field.SetString(value)
+	case int:
+		if value == "" {
+			s.field.SetInt(0)
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		s.field.SetInt(int64(n))
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+		s.field.SetBool(b)
+	case []string:
+		s.field.Set(reflect.ValueOf(splitList(value)))
+	case map[string]string:
+		s.field.Set(reflect.ValueOf(parseMapping(value)))
+	case map[string]int:
+		limits, err := parseLimits(value)
+		if err != nil {
+			return err
+		}
+		s.field.Set(reflect.ValueOf(limits))
+	default:
+		return fmt.Errorf("unsupported type %s", s.field.Type())
+	}
+	return nil
+}
+
+// snakeCase turns a Go field name into a config file key: SMTPHost becomes
+// smtp_host and AccessExpiresIn access_expires_in
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// Dump writes the effective configuration with the layer each value came
+// from. Secrets are redacted.
+func (c *Config) Dump(w io.Writer) error {
+	for _, s := range settings(c) {
+		value := formatValue(s.field)
+		if s.secret && value != "" {
+			value = redacted
+		}
+		source := c.sources[s.env]
+		if source == "" {
+			source = SourceDefault
+		}
+		if _, err := fmt.Fprintf(w, "%-36s %-28s = %q (%s)\n", s.key, s.env, value, source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatValue(v reflect.Value) string {
+	switch value := v.Interface().(type) {
+	case []string:
+		return strings.Join(value, ",")
+	case map[string]string, map[string]int:
+		keys := v.MapKeys()
+		pairs := make([]string, len(keys))
+		for i, key := range keys {
+			pairs[i] = fmt.Sprintf("%v=%v", key.Interface(), v.MapIndex(key).Interface())
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ",")
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadLayers(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+server_port: "9000"
+database:
+  host: db.internal
+  port: 6432
+rate_limit:
+  tiers:
+    admin: 1000
+oidc:
+  scopes: [openid, email]
+`)
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("POSTGRES_HOST", "db.env")
+	t.Setenv("REDIS_PASSWORD_FILE", writeFile(t, "redis-password", "s3cret\n"))
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := BindFlags(fs)
+	require.NoError(t, fs.Parse([]string{"-server-port", "9100"}))
+
+	cfg, err := Load(flags)
+	require.NoError(t, err)
+
+	assert.Equal(t, "9100", cfg.ServerPort, "flags win over the file")
+	assert.Equal(t, "db.env", cfg.Database.Host, "env wins over the file")
+	assert.Equal(t, "6432", cfg.Database.Port)
+	assert.Equal(t, "postgres", cfg.Database.User, "defaults fill the rest")
+	assert.Equal(t, "s3cret", cfg.Redis.Password)
+	assert.Equal(t, map[string]int{"admin": 1000}, cfg.RateLimit.Tiers)
+	assert.Equal(t, []string{"openid", "email"}, cfg.OIDC.Scopes)
+
+	var dump bytes.Buffer
+	require.NoError(t, cfg.Dump(&dump))
+	assert.NotContains(t, dump.String(), "s3cret")
+	assert.Contains(t, dump.String(), `"9100" (flag)`)
+	assert.Contains(t, dump.String(), `"6432" (file)`)
+}
+
+func TestLoadTOML(t *testing.T) {
+	t.Setenv("CONFIG_FILE", writeFile(t, "config.toml", `
+env = "staging"
+
+[cache]
+backend = "memory"
+ttl = 12
+`))
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, EnvStaging, cfg.Env)
+	assert.Equal(t, "memory", cfg.Cache.Backend)
+	assert.Equal(t, 12, cfg.Cache.TTL)
+}
+
+func TestLoadRejectsInvalidValues(t *testing.T) {
+	t.Setenv("CONFIG_FILE", writeFile(t, "config.yaml", "database:\n  hots: typo\n"))
+	t.Setenv("CACHE_TTL", "a day")
+	t.Setenv("CACHE_BACKEND", "disk")
+
+	_, err := Load(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown config file key "database.hots"`)
+	assert.Contains(t, err.Error(), "CACHE_TTL")
+}
+
+func TestLoadProductionSafety(t *testing.T) {
+	t.Setenv("ENV", EnvProduction)
+
+	_, err := Load(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "POSTGRES_PASSWORD must not keep its default value")
+	assert.Contains(t, err.Error(), "AUTH_LINK_BASE_URL must not point at localhost")
+	assert.Contains(t, err.Error(), "MAIL_DRIVER must not be log")
+	assert.Contains(t, err.Error(), "JWT_KEY_ENCRYPTION_KEY is required")
+
+	t.Setenv("POSTGRES_PASSWORD", "correct-horse")
+	t.Setenv("AUTH_LINK_BASE_URL", "https://translate.example.com/api/v1/auth")
+	t.Setenv("MAIL_DRIVER", "smtp")
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("JWT_KEY_ENCRYPTION_KEY", "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	_, err = Load(nil)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/vietgs03/translate/backend/internal/language"
+)
+
+// Deployment environments
+const (
+	EnvDevelopment = "development"
+	EnvTest        = "test"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+// Validate checks that the settings make sense together. In production it
+// also refuses default secrets and links pointing at localhost.
+func (c *Config) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+	oneOf := func(name, value string, allowed ...string) {
+		for _, a := range allowed {
+			if value == a {
+				return
+			}
+		}
+		problems = append(problems, fmt.Sprintf("%s must be one of %s, got %q", name, strings.Join(allowed, ", "), value))
+	}
+
+	oneOf("ENV", c.Env, EnvDevelopment, EnvTest, EnvStaging, EnvProduction)
+	check(validPort(c.ServerPort), "SERVER_PORT must be a port number, got %q", c.ServerPort)
+	check(validPort(c.Metrics.Port), "METRICS_PORT must be a port number, got %q", c.Metrics.Port)
+	check(c.Metrics.Port != c.ServerPort, "METRICS_PORT must differ from SERVER_PORT")
+	check(validPort(c.Database.Port), "POSTGRES_PORT must be a port number, got %q", c.Database.Port)
+	check(c.Database.Host != "" && c.Database.DBName != "", "POSTGRES_HOST and POSTGRES_DB are required")
+	check(validPort(c.Redis.Port), "REDIS_PORT must be a port number, got %q", c.Redis.Port)
+	check(c.Redis.DB >= 0, "REDIS_DB must not be negative")
+
+	oneOf("JWT_ALGORITHM", c.JWT.Algorithm, "RS256", "EdDSA")
+	check(c.JWT.AccessExpiresIn > 0, "JWT_ACCESS_EXPIRES_IN must be positive")
+	check(c.JWT.RefreshExpiresIn > 0, "JWT_REFRESH_EXPIRES_IN must be positive")
+	check(c.JWT.KeyRotation > 0, "JWT_KEY_ROTATION must be positive")
+	check(c.JWT.KeyOverlap >= c.JWT.AccessExpiresIn,
+		"JWT_KEY_OVERLAP (%d minutes) must be at least JWT_ACCESS_EXPIRES_IN (%d minutes)", c.JWT.KeyOverlap, c.JWT.AccessExpiresIn)
+	if c.JWT.KeyEncryptionKey != "" {
+		kek, err := base64.StdEncoding.DecodeString(c.JWT.KeyEncryptionKey)
+		check(err == nil && len(kek) == 32, "JWT_KEY_ENCRYPTION_KEY must be 32 bytes in base64")
+	}
+
+	oneOf("CACHE_BACKEND", c.Cache.Backend, "redis", "memory")
+	check(c.Cache.TTL > 0, "CACHE_TTL must be positive")
+	check(c.Cache.LocalSize >= 0 && c.Cache.LocalTTL >= 0, "CACHE_LOCAL_SIZE and CACHE_LOCAL_TTL must not be negative")
+	check(c.Cache.MemorySize > 0, "CACHE_MEMORY_SIZE must be positive")
+
+	check(c.Auth.VerificationTTL > 0, "AUTH_VERIFICATION_TTL must be positive")
+	check(c.Auth.PasswordResetTTL > 0, "AUTH_PASSWORD_RESET_TTL must be positive")
+	check(c.Auth.LockoutWindow > 0 && c.Auth.LockoutDuration > 0, "AUTH_LOCKOUT_WINDOW and AUTH_LOCKOUT_DURATION must be positive")
+	check(validURL(c.Auth.LinkBaseURL), "AUTH_LINK_BASE_URL must be an absolute URL, got %q", c.Auth.LinkBaseURL)
+
+	oneOf("MAIL_DRIVER", c.Mail.Driver, "smtp", "file", "log")
+	if c.Mail.Driver == "smtp" {
+		check(c.Mail.SMTPHost != "" && validPort(c.Mail.SMTPPort), "SMTP_HOST and SMTP_PORT are required by the smtp mail driver")
+	}
+
+	if c.OIDC.Enabled() {
+		check(validURL(c.OIDC.IssuerURL), "OIDC_ISSUER_URL must be an absolute URL, got %q", c.OIDC.IssuerURL)
+		check(c.OIDC.ClientID != "" && c.OIDC.ClientSecret != "", "OIDC_CLIENT_ID and OIDC_CLIENT_SECRET are required when OIDC_ISSUER_URL is set")
+		check(validURL(c.OIDC.RedirectURL), "OIDC_REDIRECT_URL must be an absolute URL, got %q", c.OIDC.RedirectURL)
+	}
+
+	check(c.RateLimit.Window > 0, "RATE_LIMIT_WINDOW must be positive")
+	check(c.RateLimit.Limit > 0 && c.RateLimit.TranslateLimit > 0 && c.RateLimit.AuthLimit > 0,
+		"RATE_LIMIT, RATE_LIMIT_TRANSLATE and RATE_LIMIT_AUTH must be positive")
+	if len(c.TrustedProxies) > 0 {
+		check(c.ProxyHeader != "", "PROXY_HEADER is required with TRUSTED_PROXIES")
+	}
+
+	oneOf("TRACING_EXPORTER", c.Tracing.Exporter, "otlp", "stdout", "none")
+	if c.Tracing.Exporter == "otlp" {
+		check(validURL(c.Tracing.Endpoint), "OTEL_EXPORTER_OTLP_ENDPOINT must be an absolute URL, got %q", c.Tracing.Endpoint)
+	}
+	check(c.Tracing.SamplePercent >= 0 && c.Tracing.SamplePercent <= 100, "TRACING_SAMPLE_PERCENT must be between 0 and 100")
+
+	check(c.Health.Timeout > 0, "HEALTH_TIMEOUT must be positive")
+	check(c.Health.CacheTTL >= 0, "HEALTH_CACHE_TTL must not be negative")
+	if c.Health.ProviderProbe {
+		check(c.Health.ProviderProbeInterval > 0, "HEALTH_PROVIDER_PROBE_INTERVAL must be positive")
+	}
+
+	if _, err := language.NewRegistry(c.Languages.Supported, c.Languages.Pairs); err != nil {
+		problems = append(problems, fmt.Sprintf("LANGUAGES and LANGUAGE_PAIRS: %v", err))
+	}
+
+	if c.Env == EnvProduction {
+		problems = append(problems, c.productionProblems()...)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// productionProblems lists settings that are only acceptable outside production
+func (c *Config) productionProblems() []string {
+	var problems []string
+	for _, s := range settings(c) {
+		if s.secret && s.def != "" && s.field.String() == s.def {
+			problems = append(problems, fmt.Sprintf("%s must not keep its default value in production", s.env))
+		}
+	}
+	if c.Database.Password == "" {
+		problems = append(problems, "POSTGRES_PASSWORD is required in production")
+	}
+	if isLocalURL(c.Auth.LinkBaseURL) {
+		problems = append(problems, "AUTH_LINK_BASE_URL must not point at localhost in production")
+	}
+	if c.OIDC.Enabled() && isLocalURL(c.OIDC.RedirectURL) {
+		problems = append(problems, "OIDC_REDIRECT_URL must not point at localhost in production")
+	}
+	if c.JWT.KeyEncryptionKey == "" {
+		// Anyone reading the database could otherwise sign tokens
+		problems = append(problems, "JWT_KEY_ENCRYPTION_KEY is required in production")
+	}
+	if c.Mail.Driver == "log" {
+		// Verification and reset mails would never reach anyone
+		problems = append(problems, "MAIL_DRIVER must not be log in production")
+	}
+	return problems
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n < 65536
+}
+
+func validURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+func isLocalURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
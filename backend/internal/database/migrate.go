@@ -1,15 +1,26 @@
 package database
 
 import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/vietgs03/translate/backend/internal/config"
 )
 
+// migrationFiles ship inside the binaries, so the API knows which schema
+// version it was built for
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
 func RunMigrations(cfg *config.DatabaseConfig, direction string) error {
 	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
 		cfg.User,
@@ -19,10 +30,11 @@ func RunMigrations(cfg *config.DatabaseConfig, direction string) error {
 		cfg.DBName,
 	)
 
-	m, err := migrate.New(
-		"file://internal/database/migrations",
-		dsn,
-	)
+	source, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return fmt.Errorf("failed to read migrations: %v", err)
+	}
+	m, err := migrate.NewWithSourceInstance("iofs", source, dsn)
 	if err != nil {
 		return fmt.Errorf("failed to create migrate instance: %v", err)
 	}
@@ -40,4 +52,52 @@ func RunMigrations(cfg *config.DatabaseConfig, direction string) error {
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
+
+// ExpectedMigrationVersion is the version of the newest migration built in
+func ExpectedMigrationVersion() (uint, error) {
+	source, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %v", err)
+	}
+	defer source.Close()
+
+	version, err := source.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %v", err)
+	}
+	for {
+		next, err := source.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %v", err)
+		}
+		version = next
+	}
+}
+
+// CheckMigrations fails unless the database schema is exactly at expected
+// and no migration was left half applied
+func CheckMigrations(ctx context.Context, db *sql.DB, expected uint) error {
+	var version uint
+	var dirty bool
+	err := db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no migrations applied, expected version %d", expected)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read migration version: %v", err)
+	}
+
+	switch {
+	case dirty:
+		return fmt.Errorf("migration %d failed and left the schema dirty", version)
+	case version < expected:
+		return fmt.Errorf("schema at version %d, behind expected %d", version, expected)
+	case version > expected:
+		return fmt.Errorf("schema at version %d, ahead of expected %d", version, expected)
+	}
+	return nil
+}
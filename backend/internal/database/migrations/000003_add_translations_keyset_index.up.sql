@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_translations_created_at_id ON translations(created_at DESC, id DESC);
\ No newline at end of file
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_translations_votes_id;
+DROP INDEX IF EXISTS idx_translations_updated_at_id;
+DROP INDEX IF EXISTS idx_translations_provider;
+DROP INDEX IF EXISTS idx_translations_status;
+DROP INDEX IF EXISTS idx_translations_created_by;
+
+ALTER TABLE translations ALTER COLUMN votes DROP NOT NULL;
+
+ALTER TABLE translations DROP COLUMN IF EXISTS provider;
+ALTER TABLE translations DROP COLUMN IF EXISTS status;
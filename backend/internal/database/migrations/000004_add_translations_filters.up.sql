@@ -0,0 +1,11 @@
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'pending';
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS provider VARCHAR(50);
+
+UPDATE translations SET votes = 0 WHERE votes IS NULL;
+ALTER TABLE translations ALTER COLUMN votes SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_translations_created_by ON translations(created_by);
+CREATE INDEX IF NOT EXISTS idx_translations_status ON translations(status);
+CREATE INDEX IF NOT EXISTS idx_translations_provider ON translations(provider);
+CREATE INDEX IF NOT EXISTS idx_translations_updated_at_id ON translations(updated_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_translations_votes_id ON translations(votes DESC, id DESC);
@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    parent VARCHAR(50) REFERENCES roles(name) ON UPDATE CASCADE ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR(100) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('translation:read', 'Read and list translations'),
+    ('translation:create', 'Request new translations'),
+    ('translation:update', 'Edit translations'),
+    ('translation:delete', 'Delete translations'),
+    ('translation:approve', 'Approve or reject translations'),
+    ('user:manage', 'Manage user accounts and their roles'),
+    ('role:manage', 'Manage roles and permissions'),
+    ('cache:manage', 'Inspect and purge the translation cache')
+ON CONFLICT DO NOTHING;
+
+-- The built-in roles reproduce the previous hard-coded role checks
+INSERT INTO roles (name, description, parent) VALUES
+    ('reader', 'Read-only access', NULL),
+    ('user', 'Can request translations', 'reader'),
+    ('translator', 'Can edit translations', 'user'),
+    ('admin', 'Full access', 'translator')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('reader', 'translation:read'),
+    ('user', 'translation:create'),
+    ('translator', 'translation:update'),
+    ('admin', 'translation:delete'),
+    ('admin', 'translation:approve'),
+    ('admin', 'user:manage'),
+    ('admin', 'role:manage'),
+    ('admin', 'cache:manage')
+ON CONFLICT DO NOTHING;
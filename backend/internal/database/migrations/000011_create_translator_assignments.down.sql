@@ -0,0 +1,4 @@
+DELETE FROM role_permissions WHERE permission = 'translation:manage';
+DELETE FROM permissions WHERE name = 'translation:manage';
+
+DROP TABLE IF EXISTS translator_assignments;
@@ -0,0 +1,24 @@
+-- Scopes a translator may edit: a language pair, a category, or both.
+-- Empty columns match anything.
+CREATE TABLE IF NOT EXISTS translator_assignments (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_language VARCHAR(10) NOT NULL DEFAULT '',
+    target_language VARCHAR(10) NOT NULL DEFAULT '',
+    category VARCHAR(50) NOT NULL DEFAULT '',
+    lead BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (source_language <> '' OR target_language <> '' OR category <> '')
+);
+
+CREATE INDEX idx_translator_assignments_user_id ON translator_assignments(user_id);
+CREATE UNIQUE INDEX idx_translator_assignments_scope
+    ON translator_assignments(user_id, source_language, target_language, category);
+
+INSERT INTO permissions (name, description) VALUES
+    ('translation:manage', 'Edit any translation regardless of assignments')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'translation:manage')
+ON CONFLICT DO NOTHING;
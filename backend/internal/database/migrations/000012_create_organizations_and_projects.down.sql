@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_translations_project_languages;
+ALTER TABLE translations DROP COLUMN IF EXISTS project_id;
+
+DROP TABLE IF EXISTS project_members;
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
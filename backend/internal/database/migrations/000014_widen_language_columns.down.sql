@@ -0,0 +1,8 @@
+-- Fails while any stored tag is longer than 10 characters
+ALTER TABLE translator_assignments
+    ALTER COLUMN source_language TYPE VARCHAR(10),
+    ALTER COLUMN target_language TYPE VARCHAR(10);
+
+ALTER TABLE translations
+    ALTER COLUMN source_language TYPE VARCHAR(10),
+    ALTER COLUMN target_language TYPE VARCHAR(10);
@@ -0,0 +1,9 @@
+-- BCP 47 tags such as zh-Hant-TW or sr-Latn-RS outgrow VARCHAR(10); 35 is
+-- the length BCP 47 asks implementations to support.
+ALTER TABLE translations
+    ALTER COLUMN source_language TYPE VARCHAR(35),
+    ALTER COLUMN target_language TYPE VARCHAR(35);
+
+ALTER TABLE translator_assignments
+    ALTER COLUMN source_language TYPE VARCHAR(35),
+    ALTER COLUMN target_language TYPE VARCHAR(35);
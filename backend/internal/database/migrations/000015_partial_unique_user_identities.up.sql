@@ -0,0 +1,11 @@
+-- Soft-deleted users keep their rows, so uniqueness only applies to live
+-- accounts; otherwise a deleted user's username or email can never be
+-- registered again.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_username_key;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_active ON users(username) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_active ON users(email) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_users_oidc_identity;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_oidc_identity ON users(oidc_issuer, oidc_subject) WHERE oidc_subject IS NOT NULL AND oidc_subject <> '' AND deleted_at IS NULL;
@@ -25,6 +25,9 @@ func NewPostgresDB(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %v", err)
 	}
+	if err := db.Use(tracingPlugin{}); err != nil {
+		return nil, fmt.Errorf("failed to trace database queries: %v", err)
+	}
 
 	log.Println("Connected to PostgreSQL database")
 	return db, nil
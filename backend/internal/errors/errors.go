@@ -1,50 +1,189 @@
+// Package errors is the error model shared by services, middleware and
+// handlers. Every error meant for a client is an *AppError: its Type decides
+// the HTTP status and its Code is a stable, machine-readable identifier
+// clients can switch on. The message of database and internal errors is
+// only logged, never sent.
 package errors
 
-import "fmt"
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+)
 
 type ErrorType string
 
 const (
-	NotFound       ErrorType = "NOT_FOUND"
-	ValidationErr  ErrorType = "VALIDATION_ERROR"
-	DatabaseErr    ErrorType = "DATABASE_ERROR"
-	Unauthorized   ErrorType = "UNAUTHORIZED"
-	InternalError  ErrorType = "INTERNAL_ERROR"
+	NotFound      ErrorType = "NOT_FOUND"
+	ValidationErr ErrorType = "VALIDATION_ERROR"
+	DatabaseErr   ErrorType = "DATABASE_ERROR"
+	Unauthorized  ErrorType = "UNAUTHORIZED"
+	Forbidden     ErrorType = "FORBIDDEN"
+	Conflict      ErrorType = "CONFLICT"
+	RateLimited   ErrorType = "RATE_LIMITED"
+	InternalError ErrorType = "INTERNAL_ERROR"
+)
+
+// Codes more specific than the type, for errors clients act on
+const (
+	CodeInvalidCredentials = "INVALID_CREDENTIALS"
+	CodeAccountDisabled    = "ACCOUNT_DISABLED"
+	CodeEmailNotVerified   = "EMAIL_NOT_VERIFIED"
+	CodeInvalidToken       = "INVALID_TOKEN"
+	CodeTokenRevoked       = "TOKEN_REVOKED"
+	CodeTokenExpired       = "TOKEN_EXPIRED"
+	// The pair is made of valid languages but not offered, see GET /languages
+	CodeUnsupportedLanguagePair = "UNSUPPORTED_LANGUAGE_PAIR"
 )
 
+// Status is the HTTP status errors of the type are answered with
+func (t ErrorType) Status() int {
+	switch t {
+	case NotFound:
+		return http.StatusNotFound
+	case ValidationErr:
+		return http.StatusBadRequest
+	case Unauthorized:
+		return http.StatusUnauthorized
+	case Forbidden:
+		return http.StatusForbidden
+	case Conflict:
+		return http.StatusConflict
+	case RateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Exposed reports whether the message of errors of the type may be sent to
+// clients. Database and internal errors carry driver messages and queries.
+func (t ErrorType) Exposed() bool {
+	return t.Status() < http.StatusInternalServerError
+}
+
+// FieldError describes what is wrong with one field of the request
+type FieldError struct {
+	Field   string `json:"field" example:"source_text"`
+	Message string `json:"message" example:"source_text is required"`
+}
+
 type AppError struct {
-	Type    ErrorType `json:"type"`
-	Message string    `json:"message"`
+	Type    ErrorType
+	Code    string
+	Message string
+	Fields  []FieldError
+	// Err is the cause, when the message was built with %w
+	Err error
 }
 
-func (e AppError) Error() string {
+func (e *AppError) Error() string {
 	return e.Message
 }
 
-func NewNotFoundError(format string, args ...interface{}) error {
-	return AppError{
-		Type:    NotFound,
-		Message: fmt.Sprintf(format, args...),
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, ErrNotFound) true for every not-found error, and
+// a target with a code match only errors with that code
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	if !ok || t.Message != "" {
+		return false
+	}
+	return t.Type == e.Type && (t.Code == "" || t.Code == e.Code)
+}
+
+// Targets for errors.Is, one per type
+var (
+	ErrNotFound     = &AppError{Type: NotFound}
+	ErrValidation   = &AppError{Type: ValidationErr}
+	ErrDatabase     = &AppError{Type: DatabaseErr}
+	ErrUnauthorized = &AppError{Type: Unauthorized}
+	ErrForbidden    = &AppError{Type: Forbidden}
+	ErrConflict     = &AppError{Type: Conflict}
+	ErrRateLimited  = &AppError{Type: RateLimited}
+	ErrInternal     = &AppError{Type: InternalError}
+)
+
+// NewError builds an error of type t with a specific code. The message is
+// formatted like fmt.Errorf, so %w keeps the cause reachable.
+func NewError(t ErrorType, code string, format string, args ...interface{}) error {
+	formatted := fmt.Errorf(format, args...)
+	err := &AppError{
+		Type:    t,
+		Code:    code,
+		Message: formatted.Error(),
 	}
+	switch formatted.(type) {
+	case interface{ Unwrap() error }, interface{ Unwrap() []error }:
+		err.Err = formatted
+	}
+	return err
+}
+
+func NewNotFoundError(format string, args ...interface{}) error {
+	return NewError(NotFound, string(NotFound), format, args...)
 }
 
 func NewValidationError(format string, args ...interface{}) error {
-	return AppError{
+	return NewError(ValidationErr, string(ValidationErr), format, args...)
+}
+
+// NewFieldValidationError reports problems with individual request fields
+func NewFieldValidationError(fields ...FieldError) error {
+	return &AppError{
 		Type:    ValidationErr,
-		Message: fmt.Sprintf(format, args...),
+		Code:    string(ValidationErr),
+		Message: "validation failed",
+		Fields:  fields,
 	}
 }
 
 func NewDatabaseError(format string, args ...interface{}) error {
-	return AppError{
-		Type:    DatabaseErr,
-		Message: fmt.Sprintf(format, args...),
-	}
+	return NewError(DatabaseErr, string(DatabaseErr), format, args...)
 }
 
 func NewUnauthorizedError(format string, args ...interface{}) error {
-	return AppError{
-		Type:    Unauthorized,
-		Message: fmt.Sprintf(format, args...),
+	return NewError(Unauthorized, string(Unauthorized), format, args...)
+}
+
+func NewForbiddenError(format string, args ...interface{}) error {
+	return NewError(Forbidden, string(Forbidden), format, args...)
+}
+
+func NewConflictError(format string, args ...interface{}) error {
+	return NewError(Conflict, string(Conflict), format, args...)
+}
+
+func NewRateLimitError(format string, args ...interface{}) error {
+	return NewError(RateLimited, string(RateLimited), format, args...)
+}
+
+func NewInternalError(format string, args ...interface{}) error {
+	return NewError(InternalError, string(InternalError), format, args...)
+}
+
+// Is, As and Unwrap forward to the standard library, so importers of this
+// package don't need both
+func Is(err, target error) bool { return stderrors.Is(err, target) }
+
+func As(err error, target interface{}) bool { return stderrors.As(err, target) }
+
+func Unwrap(err error) error { return stderrors.Unwrap(err) }
+
+// From returns the AppError in err's chain. Any other error becomes an
+// internal error wrapping it.
+func From(err error) *AppError {
+	var appErr *AppError
+	if stderrors.As(err, &appErr) {
+		return appErr
 	}
-} 
\ No newline at end of file
+	return &AppError{
+		Type:    InternalError,
+		Code:    string(InternalError),
+		Message: err.Error(),
+		Err:     err,
+	}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/middleware"
+	"github.com/vietgs03/translate/backend/internal/service"
+	"github.com/vietgs03/translate/backend/internal/types"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// @Summary Create API key
+// @Description Create an API key for the current user. The key is only returned in this response; send it in the X-API-Key header.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body service.CreateAPIKeyInput true "Key name and scope"
+// @Success 201 {object} service.CreatedAPIKey
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Router /api-keys [post]
+func (h *APIKeyHandler) Create(c *fiber.Ctx) error {
+	input := *middleware.Payload[service.CreateAPIKeyInput](c)
+
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	created, err := h.apiKeyService.Create(c.UserContext(), user, input)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(created)
+}
+
+// @Summary List API keys
+// @Description List the API keys of the current user, including revoked ones
+// @Tags api-keys
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Success 200 {array} model.APIKey
+// @Failure 401 {object} types.Problem
+// @Router /api-keys [get]
+func (h *APIKeyHandler) List(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	keys, err := h.apiKeyService.List(c.UserContext(), user)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(keys)
+}
+
+// @Summary Rename API key
+// @Description Change the label of an API key
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API key ID"
+// @Param input body service.UpdateAPIKeyInput true "New name"
+// @Success 200 {object} model.APIKey
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /api-keys/{id} [patch]
+func (h *APIKeyHandler) Update(c *fiber.Ctx) error {
+	id := middleware.Payload[IDPath](c).ID
+
+	input := *middleware.Payload[service.UpdateAPIKeyInput](c)
+
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	apiKey, err := h.apiKeyService.UpdateLabel(c.UserContext(), user, id, input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(apiKey)
+}
+
+// @Summary Revoke API key
+// @Description Revoke an API key. Requests with it are rejected from now on.
+// @Tags api-keys
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "API key ID"
+// @Success 204
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *fiber.Ctx) error {
+	id := middleware.Payload[IDPath](c).ID
+
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	if err := h.apiKeyService.Revoke(c.UserContext(), user, id); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
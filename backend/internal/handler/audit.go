@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/logging"
+	"github.com/vietgs03/translate/backend/internal/middleware"
+	"github.com/vietgs03/translate/backend/internal/model"
+	"github.com/vietgs03/translate/backend/internal/repository"
+	"github.com/vietgs03/translate/backend/internal/service"
+	"github.com/vietgs03/translate/backend/internal/types"
+	"go.uber.org/zap"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// AuditQuery is the query string of the audit listing and export. Export
+// streams every match, so it ignores the page fields.
+type AuditQuery struct {
+	Actor      string `query:"actor"`
+	ActorID    *uint  `query:"actor_id"`
+	Action     string `query:"action"`
+	TargetType string `query:"target_type"`
+	TargetID   string `query:"target_id"`
+	From       string `query:"from"`
+	To         string `query:"to"`
+	Page       int    `query:"page" default:"1" validate:"min=1"`
+	PageSize   int    `query:"page_size" default:"10" validate:"page_size"`
+}
+
+// @Summary List audit events
+// @Description Query the security audit log, newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param actor query string false "Actor username"
+// @Param actor_id query int false "Actor user ID"
+// @Param action query string false "Action, e.g. auth.login_failed"
+// @Param target_type query string false "Target type, e.g. user"
+// @Param target_id query string false "Target ID"
+// @Param from query string false "Events at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Events before (RFC 3339 or YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page" default(10)
+// @Success 200 {object} types.PaginatedResponse{data=[]model.AuditEvent}
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Router /admin/audit [get]
+func (h *AuditHandler) List(c *fiber.Ctx) error {
+	query := middleware.Payload[AuditQuery](c)
+	filter, err := parseAuditFilter(query)
+	if err != nil {
+		return err
+	}
+	filter.Page = query.Page
+	filter.PageSize = query.PageSize
+
+	events, total, err := h.auditService.List(c.UserContext(), filter)
+	if err != nil {
+		return err
+	}
+
+	response := types.PaginatedResponse{
+		Data: events,
+		Pagination: types.Pagination{
+			Page:     filter.Page,
+			PageSize: filter.PageSize,
+			Total:    &total,
+		},
+	}
+	if int64(filter.Page*filter.PageSize) < total {
+		response.Links.Next = pageLink(c, map[string]string{"page": strconv.Itoa(filter.Page + 1)})
+	}
+	if filter.Page > 1 {
+		response.Links.Prev = pageLink(c, map[string]string{"page": strconv.Itoa(filter.Page - 1)})
+	}
+
+	return c.JSON(response)
+}
+
+// @Summary Export audit events
+// @Description Download every matching audit event as JSON Lines, oldest first. The export itself is audited.
+// @Tags admin
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Param actor query string false "Actor username"
+// @Param actor_id query int false "Actor user ID"
+// @Param action query string false "Action, e.g. auth.login_failed"
+// @Param target_type query string false "Target type, e.g. user"
+// @Param target_id query string false "Target ID"
+// @Param from query string false "Events at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Events before (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {string} string "One audit event per line"
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Router /admin/audit/export [get]
+func (h *AuditHandler) Export(c *fiber.Ctx) error {
+	filter, err := parseAuditFilter(middleware.Payload[AuditQuery](c))
+	if err != nil {
+		return err
+	}
+
+	h.auditService.Record(c.UserContext(), service.AuditEntry{
+		Action: model.AuditActionAuditExported,
+		After:  map[string]string{"query": string(c.Request().URI().QueryString())},
+	})
+
+	c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	c.Set(fiber.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="audit-%s.jsonl"`, time.Now().UTC().Format("20060102T150405Z")))
+
+	// Streamed after the handler returns, so it can't use the request context
+	logger := logging.FromContext(c.UserContext())
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.auditService.Export(context.Background(), filter, w); err != nil {
+			logger.Error("failed to export audit events", zap.Error(err))
+		}
+	})
+	return nil
+}
+
+// parseAuditFilter builds the filters shared by listing and export
+func parseAuditFilter(query *AuditQuery) (repository.AuditFilter, error) {
+	filter := repository.AuditFilter{
+		Actor:      query.Actor,
+		ActorID:    query.ActorID,
+		Action:     query.Action,
+		TargetType: query.TargetType,
+		TargetID:   query.TargetID,
+	}
+
+	var err error
+	if filter.From, err = parseTimeQuery("from", query.From); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeQuery("to", query.To); err != nil {
+		return filter, err
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, errors.NewValidationError("from must be before to")
+	}
+	return filter, nil
+}
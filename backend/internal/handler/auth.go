@@ -1,19 +1,25 @@
 package handler
 
 import (
+	"strconv"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/vietgs03/translate/backend/internal/middleware"
 	"github.com/vietgs03/translate/backend/internal/service"
 	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/repository"
 	"github.com/vietgs03/translate/backend/internal/types"
 )
 
 type AuthHandler struct {
 	authService service.AuthService
+	rbacService service.RBACService
 }
 
-func NewAuthHandler(authService service.AuthService) *AuthHandler {
+func NewAuthHandler(authService service.AuthService, rbacService service.RBACService) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
+		rbacService: rbacService,
 	}
 }
 
@@ -24,16 +30,13 @@ func NewAuthHandler(authService service.AuthService) *AuthHandler {
 // @Produce json
 // @Param input body service.RegisterInput true "Registration details"
 // @Success 201 {object} model.User
-// @Failure 400 {object} types.APIError
-// @Failure 500 {object} types.APIError
+// @Failure 400 {object} types.Problem
+// @Failure 500 {object} types.Problem
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *fiber.Ctx) error {
-	var input service.RegisterInput
-	if err := c.BodyParser(&input); err != nil {
-		return types.NewValidationError("invalid request body")
-	}
+	input := *middleware.Payload[service.RegisterInput](c)
 
-	user, err := h.authService.Register(c.Context(), input)
+	user, err := h.authService.Register(c.UserContext(), input)
 	if err != nil {
 		return err
 	}
@@ -48,45 +51,448 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 // @Produce json
 // @Param input body service.LoginInput true "Login credentials"
 // @Success 200 {object} types.LoginResponse
-// @Failure 400 {object} types.APIError
-// @Failure 401 {object} types.APIError
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
-	var input service.LoginInput
-	if err := c.BodyParser(&input); err != nil {
+	input := *middleware.Payload[service.LoginInput](c)
+	input.IP = c.IP()
+
+	tokens, err := h.authService.Login(c.UserContext(), input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(tokens)
+}
+
+// @Summary Single sign-on login
+// @Description Redirect to the OpenID Connect provider to log in. The provider sends the browser back to /auth/oidc/callback.
+// @Tags auth
+// @Success 302
+// @Failure 400 {object} types.Problem
+// @Router /auth/oidc/login [get]
+func (h *AuthHandler) OIDCLogin(c *fiber.Ctx) error {
+	authURL, err := h.authService.OIDCAuthURL(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// OIDCLinkResponse is where to send the browser to link single sign-on
+type OIDCLinkResponse struct {
+	URL string `json:"url" example:"https://idp.example.com/authorize?state=..."`
+}
+
+// @Summary Link single sign-on
+// @Description Start linking an OpenID Connect identity to the current account. Open the returned URL in the browser; the callback links the identity and logs in. Not available to API keys.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} handler.OIDCLinkResponse
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 403 {object} types.Problem
+// @Router /auth/oidc/link [post]
+func (h *AuthHandler) OIDCLink(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	authURL, err := h.authService.OIDCLinkURL(c.UserContext(), user)
+	if err != nil {
 		return err
 	}
 
-	token, err := h.authService.Login(c.Context(), input)
+	return c.JSON(OIDCLinkResponse{URL: authURL})
+}
+
+// @Summary Single sign-on callback
+// @Description Complete a single sign-on login or link. Users are created on their first login; an email already registered must be linked from its account first.
+// @Tags auth
+// @Produce json
+// @Param code query string true "Authorization code"
+// @Param state query string true "State from the login redirect"
+// @Success 200 {object} types.LoginResponse
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 409 {object} types.Problem
+// @Router /auth/oidc/callback [get]
+func (h *AuthHandler) OIDCCallback(c *fiber.Ctx) error {
+	if providerErr := c.Query("error"); providerErr != "" {
+		return errors.NewUnauthorizedError("single sign-on failed: %s %s", providerErr, c.Query("error_description"))
+	}
+
+	code, state := c.Query("code"), c.Query("state")
+	if code == "" || state == "" {
+		return errors.NewValidationError("code and state are required")
+	}
+
+	tokens, err := h.authService.LoginWithOIDC(c.UserContext(), state, code)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"token": token,
+	return c.JSON(tokens)
+}
+
+// @Summary Verify email address
+// @Description Confirm an email address with the token from the verification email
+// @Tags auth
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} types.APIResponse
+// @Failure 400 {object} types.Problem
+// @Router /auth/email/verify [get]
+func (h *AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		return errors.NewValidationError("token is required")
+	}
+
+	if err := h.authService.VerifyEmail(c.UserContext(), token); err != nil {
+		return err
+	}
+
+	return c.JSON(types.APIResponse{
+		Status:  "success",
+		Message: "Email address verified",
 	})
 }
 
-func (h *AuthHandler) UpdateRole(c *fiber.Ctx) error {
-	var input struct {
-		UserID uint   `json:"user_id"`
-		Role   string `json:"role" validate:"required,oneof=user translator admin"`
+// @Summary Resend verification email
+// @Description Send a new verification email. The response is the same whether or not the address is registered.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body service.EmailInput true "Email address"
+// @Success 202 {object} types.APIResponse
+// @Failure 400 {object} types.Problem
+// @Router /auth/email/resend [post]
+func (h *AuthHandler) ResendVerification(c *fiber.Ctx) error {
+	input := *middleware.Payload[service.EmailInput](c)
+
+	if err := h.authService.ResendVerification(c.UserContext(), input.Email); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(types.APIResponse{
+		Status:  "success",
+		Message: "If the address is registered and unverified, a verification email is on its way",
+	})
+}
+
+// @Summary Forgot password
+// @Description Email a single-use password reset token. The response is the same whether or not the address is registered.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body service.EmailInput true "Email address"
+// @Success 202 {object} types.APIResponse
+// @Failure 400 {object} types.Problem
+// @Router /auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	input := *middleware.Payload[service.EmailInput](c)
+
+	if err := h.authService.ForgotPassword(c.UserContext(), input.Email); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(types.APIResponse{
+		Status:  "success",
+		Message: "If the address is registered, a password reset email is on its way",
+	})
+}
+
+// @Summary Reset password
+// @Description Set a new password with a token from the reset email. All sessions of the user are logged out.
+// @Tags auth
+// @Accept json
+// @Param input body service.ResetPasswordInput true "Reset token and new password"
+// @Success 204
+// @Failure 400 {object} types.Problem
+// @Router /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	input := *middleware.Payload[service.ResetPasswordInput](c)
+
+	if err := h.authService.ResetPassword(c.UserContext(), input); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access token and refresh token. Each refresh token can be used once.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body service.RefreshInput true "Refresh token"
+// @Success 200 {object} types.LoginResponse
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	input := *middleware.Payload[service.RefreshInput](c)
+
+	tokens, err := h.authService.Refresh(c.UserContext(), input.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(tokens)
+}
+
+// @Summary Logout
+// @Description Revoke the current access token and the given refresh token. Not available to API keys.
+// @Tags auth
+// @Accept json
+// @Security BearerAuth
+// @Param input body service.LogoutInput false "Refresh token to revoke"
+// @Success 204
+// @Failure 401 {object} types.Problem
+// @Failure 403 {object} types.Problem
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	input := *middleware.Payload[service.LogoutInput](c)
+
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	if err := h.authService.Logout(c.UserContext(), user, input.RefreshToken); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// @Summary Unlock user
+// @Description Lift a login lockout caused by repeated failed logins
+// @Tags admin
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /admin/users/{id}/unlock [post]
+func (h *AuthHandler) UnlockUser(c *fiber.Ctx) error {
+	id := middleware.Payload[IDPath](c).ID
+
+	if err := h.authService.UnlockUser(c.UserContext(), id); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListUsersQuery is the query string of the user listing
+type ListUsersQuery struct {
+	Query    string `query:"q" validate:"max=100"`
+	Role     string `query:"role" validate:"max=50"`
+	Active   *bool  `query:"active"`
+	Page     int    `query:"page" default:"1" validate:"min=1"`
+	PageSize int    `query:"page_size" default:"10" validate:"page_size"`
+}
+
+// @Summary List users
+// @Description List users, optionally searching username and email and filtering by role or status
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Search term"
+// @Param role query string false "Role"
+// @Param active query bool false "Active status"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page" default(10)
+// @Success 200 {object} types.PaginatedResponse{data=[]model.User}
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Router /admin/users [get]
+func (h *AuthHandler) ListUsers(c *fiber.Ctx) error {
+	query := middleware.Payload[ListUsersQuery](c)
+	filter := repository.UserFilter{
+		Query:    query.Query,
+		Role:     query.Role,
+		Active:   query.Active,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}
+
+	users, total, err := h.authService.ListUsers(c.UserContext(), filter)
+	if err != nil {
+		return err
+	}
+
+	response := types.PaginatedResponse{
+		Data: users,
+		Pagination: types.Pagination{
+			Page:     filter.Page,
+			PageSize: filter.PageSize,
+			Total:    &total,
+		},
+	}
+	if int64(filter.Page*filter.PageSize) < total {
+		response.Links.Next = pageLink(c, map[string]string{"page": strconv.Itoa(filter.Page + 1)})
+	}
+	if filter.Page > 1 {
+		response.Links.Prev = pageLink(c, map[string]string{"page": strconv.Itoa(filter.Page - 1)})
+	}
+
+	return c.JSON(response)
+}
+
+// @Summary Get user
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} model.User
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /admin/users/{id} [get]
+func (h *AuthHandler) GetUser(c *fiber.Ctx) error {
+	id := middleware.Payload[IDPath](c).ID
+
+	user, err := h.authService.GetUser(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(user)
+}
+
+// @Summary Deactivate user
+// @Description Block a user from logging in and end all of their sessions
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} model.User
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /admin/users/{id}/deactivate [post]
+func (h *AuthHandler) DeactivateUser(c *fiber.Ctx) error {
+	return h.setUserActive(c, false)
+}
+
+// @Summary Reactivate user
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} model.User
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /admin/users/{id}/reactivate [post]
+func (h *AuthHandler) ReactivateUser(c *fiber.Ctx) error {
+	return h.setUserActive(c, true)
+}
+
+func (h *AuthHandler) setUserActive(c *fiber.Ctx, active bool) error {
+	id := middleware.Payload[IDPath](c).ID
+
+	actor, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	user, err := h.authService.SetUserActive(c.UserContext(), actor, id, active)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(user)
+}
+
+// @Summary Reset user password
+// @Description Set a new password and end all sessions of the user, or leave the password empty to email them a reset token
+// @Tags admin
+// @Accept json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param input body service.AdminResetPasswordInput false "New password"
+// @Success 204
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /admin/users/{id}/password [post]
+func (h *AuthHandler) AdminResetPassword(c *fiber.Ctx) error {
+	id := middleware.Payload[IDPath](c).ID
+
+	input := *middleware.Payload[service.AdminResetPasswordInput](c)
+
+	if err := h.authService.AdminResetPassword(c.UserContext(), id, input); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// @Summary Delete user
+// @Description Delete a user and end all of their sessions
+// @Tags admin
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /admin/users/{id} [delete]
+func (h *AuthHandler) DeleteUser(c *fiber.Ctx) error {
+	id := middleware.Payload[IDPath](c).ID
+
+	actor, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
 	}
 
-	if err := c.BodyParser(&input); err != nil {
-		return errors.NewValidationError("invalid request body: %v", err)
+	if err := h.authService.DeleteUser(c.UserContext(), actor, id); err != nil {
+		return err
 	}
 
-	// Only admin can update roles
-	currentUser := c.Locals("user").(*types.JWTClaims)
-	if currentUser.Role != "admin" {
-		return errors.NewUnauthorizedError("only admin can update roles")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// UpdateRoleInput is the body of a role change
+type UpdateRoleInput struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// @Summary Change user role
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param input body handler.UpdateRoleInput true "New role"
+// @Success 200 {object} model.User
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /admin/users/{id}/role [put]
+func (h *AuthHandler) UpdateRole(c *fiber.Ctx) error {
+	id := middleware.Payload[IDPath](c).ID
+
+	input := *middleware.Payload[UpdateRoleInput](c)
+
+	exists, err := h.rbacService.RoleExists(c.UserContext(), input.Role)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.NewValidationError("role %q does not exist", input.Role)
 	}
 
-	user, err := h.authService.UpdateRole(c.Context(), input.UserID, input.Role)
+	user, err := h.authService.UpdateRole(c.UserContext(), id, input.Role)
 	if err != nil {
 		return err
 	}
 
 	return c.JSON(user)
-} 
\ No newline at end of file
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/vietgs03/translate/backend/internal/cache"
+	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/middleware"
+)
+
+type CacheHandler struct {
+	cache cache.TranslationCache
+}
+
+func NewCacheHandler(translationCache cache.TranslationCache) *CacheHandler {
+	return &CacheHandler{
+		cache: translationCache,
+	}
+}
+
+// @Summary Cache statistics
+// @Description Hit, miss and error counters of this replica per cache tier and language pair
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} cache.Stats
+// @Failure 401 {object} types.Problem
+// @Router /admin/cache/stats [get]
+func (h *CacheHandler) Stats(c *fiber.Ctx) error {
+	return c.JSON(h.cache.Stats())
+}
+
+// CachePurgeQuery selects the cache entries to purge
+type CachePurgeQuery struct {
+	ProjectID      *uint  `query:"project_id"`
+	SourceLanguage string `query:"source_lang"`
+	TargetLanguage string `query:"target_lang"`
+	Category       string `query:"category"`
+}
+
+// @Summary Purge cache entries
+// @Description Remove cached translations of a project, a language pair, a category, or a combination
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param project_id query int false "Project ID, 0 for the global translation memory"
+// @Param source_lang query string false "Source language, requires target_lang"
+// @Param target_lang query string false "Target language, requires source_lang"
+// @Param category query string false "Category"
+// @Success 200 {object} map[string]int
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Router /admin/cache [delete]
+func (h *CacheHandler) Purge(c *fiber.Ctx) error {
+	query := middleware.Payload[CachePurgeQuery](c)
+	filter := cache.PurgeFilter{
+		ProjectID: query.ProjectID,
+		Category:  query.Category,
+	}
+
+	// Keys hold canonical tags, as CreateTranslation stores them
+	var err error
+	if filter.SourceLanguage, err = parseLanguageQuery("source_lang", query.SourceLanguage); err != nil {
+		return err
+	}
+	if filter.TargetLanguage, err = parseLanguageQuery("target_lang", query.TargetLanguage); err != nil {
+		return err
+	}
+
+	if (filter.SourceLanguage == "") != (filter.TargetLanguage == "") {
+		return errors.NewValidationError("source_lang and target_lang must be given together")
+	}
+	if filter.ProjectID == nil && filter.SourceLanguage == "" && filter.Category == "" {
+		return errors.NewValidationError("a project, a language pair or a category is required")
+	}
+
+	purged, err := h.cache.Purge(c.UserContext(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"purged": purged,
+	})
+}
+
+// CacheEntryQuery addresses a single cached translation
+type CacheEntryQuery struct {
+	SourceText     string `query:"source_text" validate:"required"`
+	SourceLanguage string `query:"source_lang" validate:"required"`
+	TargetLanguage string `query:"target_lang" validate:"required"`
+	ProjectID      uint   `query:"project_id"`
+}
+
+// @Summary Inspect cache entry
+// @Description Show where a translation is cached and how long it stays there
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param source_text query string true "Source text"
+// @Param source_lang query string true "Source language"
+// @Param target_lang query string true "Target language"
+// @Param project_id query int false "Project ID, omit for the global translation memory"
+// @Success 200 {object} cache.Entry
+// @Failure 400 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /admin/cache/entry [get]
+func (h *CacheHandler) Inspect(c *fiber.Ctx) error {
+	query := middleware.Payload[CacheEntryQuery](c)
+
+	sourceLang, err := parseLanguageQuery("source_lang", query.SourceLanguage)
+	if err != nil {
+		return err
+	}
+	targetLang, err := parseLanguageQuery("target_lang", query.TargetLanguage)
+	if err != nil {
+		return err
+	}
+
+	entry, err := h.cache.Inspect(c.UserContext(), query.ProjectID, query.SourceText, sourceLang, targetLang)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return errors.NewNotFoundError("cache entry not found")
+	}
+
+	return c.JSON(entry)
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/vietgs03/translate/backend/internal/health"
+)
+
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+	}
+}
+
+// Live answers as long as the process serves requests; it checks no
+// dependency, so an outage of one doesn't get the service restarted. It is
+// mounted at /livez, outside the documented API base path.
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(fiber.Map{"status": health.StatusOK})
+}
+
+// @Summary Check readiness
+// @Description Checks Postgres, Redis, the migration version and, when enabled, the translation provider, each with its latency. The report is reused for a few seconds. A failing provider only degrades the service; any other failing check makes it unavailable. Also mounted at /readyz.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /public/health [get]
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	report := h.checker.Check(c.UserContext())
+
+	status := fiber.StatusOK
+	if !report.Ready() {
+		status = fiber.StatusServiceUnavailable
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(status).JSON(report)
+}
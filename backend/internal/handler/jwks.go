@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vietgs03/translate/backend/internal/token"
+)
+
+type JWKSHandler struct {
+	keys *token.KeySet
+}
+
+func NewJWKSHandler(keys *token.KeySet) *JWKSHandler {
+	return &JWKSHandler{
+		keys: keys,
+	}
+}
+
+// JWKS serves the public keys for verifying our access tokens. It is
+// mounted at /.well-known/jwks.json, outside the documented API base path.
+func (h *JWKSHandler) JWKS(c *fiber.Ctx) error {
+	// New keys are published token.JWKSMaxAge before they sign
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", int(token.JWKSMaxAge.Seconds())))
+	return c.JSON(h.keys.JWKS())
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/vietgs03/translate/backend/internal/language"
+)
+
+type LanguageHandler struct {
+	languages *language.Registry
+	provider  string
+}
+
+func NewLanguageHandler(languages *language.Registry, provider string) *LanguageHandler {
+	return &LanguageHandler{
+		languages: languages,
+		provider:  provider,
+	}
+}
+
+// LanguagesResponse lists what can be translated. Pairs is left out when
+// every pair of different languages is supported.
+type LanguagesResponse struct {
+	Provider  string              `json:"provider" example:"openai"`
+	Languages []language.Language `json:"languages"`
+	Pairs     []language.Pair     `json:"pairs,omitempty"`
+}
+
+// @Summary List supported languages
+// @Description Languages, as BCP 47 tags, and language pairs translations can be created for. A tag also covers its narrower forms: "pt" allows "pt-BR".
+// @Tags translations
+// @Produce json
+// @Success 200 {object} handler.LanguagesResponse
+// @Router /languages [get]
+func (h *LanguageHandler) List(c *fiber.Ctx) error {
+	return c.JSON(LanguagesResponse{
+		Provider:  h.provider,
+		Languages: h.languages.Languages(),
+		Pairs:     h.languages.Pairs(),
+	})
+}
@@ -0,0 +1,334 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/middleware"
+	"github.com/vietgs03/translate/backend/internal/model"
+	"github.com/vietgs03/translate/backend/internal/service"
+	"github.com/vietgs03/translate/backend/internal/types"
+)
+
+type ProjectHandler struct {
+	projectService service.ProjectService
+}
+
+func NewProjectHandler(projectService service.ProjectService) *ProjectHandler {
+	return &ProjectHandler{
+		projectService: projectService,
+	}
+}
+
+// OrgPath is the :orgID parameter of organization routes
+type OrgPath struct {
+	OrgID uint `params:"orgID" validate:"required"`
+}
+
+// OrgMemberPath addresses one member of an organization
+type OrgMemberPath struct {
+	OrgID  uint `params:"orgID" validate:"required"`
+	UserID uint `params:"userID" validate:"required"`
+}
+
+// MemberPath is the :userID parameter of project member routes
+type MemberPath struct {
+	UserID uint `params:"userID" validate:"required"`
+}
+
+// scopeFrom returns the project resolved by middleware.RequireProjectRole,
+// or the global scope on routes outside /projects
+func scopeFrom(c *fiber.Ctx) service.Scope {
+	project, ok := c.Locals("project").(*model.Project)
+	if !ok {
+		return service.GlobalScope
+	}
+	role, _ := c.Locals("project_role").(string)
+	return service.Scope{Project: project, Role: role}
+}
+
+// @Summary Create organization
+// @Description Create an organization; the caller becomes its owner
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body service.CreateOrganizationInput true "Organization details"
+// @Success 201 {object} model.Organization
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 403 {object} types.Problem
+// @Router /orgs [post]
+func (h *ProjectHandler) CreateOrganization(c *fiber.Ctx) error {
+	input := *middleware.Payload[service.CreateOrganizationInput](c)
+
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	org, err := h.projectService.CreateOrganization(c.UserContext(), user, input)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(org)
+}
+
+// @Summary List organizations
+// @Description List the organizations the caller belongs to
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.Organization
+// @Failure 401 {object} types.Problem
+// @Failure 403 {object} types.Problem
+// @Router /orgs [get]
+func (h *ProjectHandler) ListOrganizations(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	orgs, err := h.projectService.ListOrganizations(c.UserContext(), user)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(orgs)
+}
+
+// @Summary List organization members
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param orgID path int true "Organization ID"
+// @Success 200 {array} model.OrganizationMember
+// @Failure 401 {object} types.Problem
+// @Failure 403 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /orgs/{orgID}/members [get]
+func (h *ProjectHandler) ListOrganizationMembers(c *fiber.Ctx) error {
+	orgID := middleware.Payload[OrgPath](c).OrgID
+
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	members, err := h.projectService.ListOrganizationMembers(c.UserContext(), user, orgID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(members)
+}
+
+// @Summary Add organization member
+// @Description Add a user to an organization or change their role. Owners only.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param orgID path int true "Organization ID"
+// @Param input body service.OrganizationMemberInput true "User and role"
+// @Success 200 {object} model.OrganizationMember
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 403 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /orgs/{orgID}/members [put]
+func (h *ProjectHandler) AddOrganizationMember(c *fiber.Ctx) error {
+	orgID := middleware.Payload[OrgPath](c).OrgID
+
+	input := *middleware.Payload[service.OrganizationMemberInput](c)
+
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	member, err := h.projectService.AddOrganizationMember(c.UserContext(), user, orgID, input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(member)
+}
+
+// @Summary Remove organization member
+// @Tags projects
+// @Security BearerAuth
+// @Param orgID path int true "Organization ID"
+// @Param userID path int true "User ID"
+// @Success 204
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 403 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /orgs/{orgID}/members/{userID} [delete]
+func (h *ProjectHandler) RemoveOrganizationMember(c *fiber.Ctx) error {
+	path := middleware.Payload[OrgMemberPath](c)
+
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	if err := h.projectService.RemoveOrganizationMember(c.UserContext(), user, path.OrgID, path.UserID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// @Summary Create project
+// @Description Create a project in an organization. Owners only.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param orgID path int true "Organization ID"
+// @Param input body service.CreateProjectInput true "Project details"
+// @Success 201 {object} model.Project
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 403 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /orgs/{orgID}/projects [post]
+func (h *ProjectHandler) CreateProject(c *fiber.Ctx) error {
+	orgID := middleware.Payload[OrgPath](c).OrgID
+
+	input := *middleware.Payload[service.CreateProjectInput](c)
+
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	project, err := h.projectService.CreateProject(c.UserContext(), user, orgID, input)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(project)
+}
+
+// @Summary List projects
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param orgID path int true "Organization ID"
+// @Success 200 {array} model.Project
+// @Failure 401 {object} types.Problem
+// @Failure 403 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /orgs/{orgID}/projects [get]
+func (h *ProjectHandler) ListProjects(c *fiber.Ctx) error {
+	orgID := middleware.Payload[OrgPath](c).OrgID
+
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	projects, err := h.projectService.ListProjects(c.UserContext(), user, orgID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(projects)
+}
+
+// @Summary Get project
+// @Description Get a project and the caller's role in it
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param projectID path int true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /projects/{projectID} [get]
+func (h *ProjectHandler) GetProject(c *fiber.Ctx) error {
+	scope := scopeFrom(c)
+	return c.JSON(fiber.Map{
+		"project": scope.Project,
+		"role":    scope.Role,
+	})
+}
+
+// @Summary List project members
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param projectID path int true "Project ID"
+// @Success 200 {array} model.ProjectMember
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /projects/{projectID}/members [get]
+func (h *ProjectHandler) ListProjectMembers(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	members, err := h.projectService.ListProjectMembers(c.UserContext(), user, scopeFrom(c).ProjectID())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(members)
+}
+
+// @Summary Add project member
+// @Description Add a user to a project or change their project role. Maintainers only.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param projectID path int true "Project ID"
+// @Param input body service.ProjectMemberInput true "User and role"
+// @Success 200 {object} model.ProjectMember
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /projects/{projectID}/members [put]
+func (h *ProjectHandler) AddProjectMember(c *fiber.Ctx) error {
+	input := *middleware.Payload[service.ProjectMemberInput](c)
+
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	member, err := h.projectService.AddProjectMember(c.UserContext(), user, scopeFrom(c).ProjectID(), input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(member)
+}
+
+// @Summary Remove project member
+// @Tags projects
+// @Security BearerAuth
+// @Param projectID path int true "Project ID"
+// @Param userID path int true "User ID"
+// @Success 204
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /projects/{projectID}/members/{userID} [delete]
+func (h *ProjectHandler) RemoveProjectMember(c *fiber.Ctx) error {
+	userID := middleware.Payload[MemberPath](c).UserID
+
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	if err := h.projectService.RemoveProjectMember(c.UserContext(), user, scopeFrom(c).ProjectID(), userID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
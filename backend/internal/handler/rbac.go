@@ -0,0 +1,208 @@
+package handler
+
+import (
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vietgs03/translate/backend/internal/middleware"
+	"github.com/vietgs03/translate/backend/internal/service"
+)
+
+type RBACHandler struct {
+	rbacService service.RBACService
+}
+
+func NewRBACHandler(rbacService service.RBACService) *RBACHandler {
+	return &RBACHandler{
+		rbacService: rbacService,
+	}
+}
+
+// @Summary List roles
+// @Description List roles with their direct and inherited permissions
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} service.RoleDetails
+// @Failure 401 {object} types.Problem
+// @Router /admin/roles [get]
+func (h *RBACHandler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.rbacService.ListRoles(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(roles)
+}
+
+// @Summary Create role
+// @Description Create a role, optionally inheriting the permissions of a parent role
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body service.CreateRoleInput true "Role details"
+// @Success 201 {object} service.RoleDetails
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Router /admin/roles [post]
+func (h *RBACHandler) CreateRole(c *fiber.Ctx) error {
+	input := *middleware.Payload[service.CreateRoleInput](c)
+
+	role, err := h.rbacService.CreateRole(c.UserContext(), input)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(role)
+}
+
+// @Summary Update role
+// @Description Replace the description, parent and direct permissions of a role
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Role name"
+// @Param input body service.UpdateRoleInput true "Role details"
+// @Success 200 {object} service.RoleDetails
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /admin/roles/{name} [put]
+func (h *RBACHandler) UpdateRole(c *fiber.Ctx) error {
+	input := *middleware.Payload[service.UpdateRoleInput](c)
+
+	role, err := h.rbacService.UpdateRole(c.UserContext(), middleware.Payload[RolePath](c).Name, input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(role)
+}
+
+// @Summary Delete role
+// @Description Delete a role that no user holds and no role inherits from
+// @Tags admin
+// @Security BearerAuth
+// @Param name path string true "Role name"
+// @Success 204
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /admin/roles/{name} [delete]
+func (h *RBACHandler) DeleteRole(c *fiber.Ctx) error {
+	if err := h.rbacService.DeleteRole(c.UserContext(), middleware.Payload[RolePath](c).Name); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RolePath is the :name parameter of routes addressing a single role
+type RolePath struct {
+	Name string `params:"name" validate:"required,max=50"`
+}
+
+// @Summary List permissions
+// @Description List all permissions that can be granted to roles
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.Permission
+// @Failure 401 {object} types.Problem
+// @Router /admin/permissions [get]
+func (h *RBACHandler) ListPermissions(c *fiber.Ctx) error {
+	permissions, err := h.rbacService.ListPermissions(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(permissions)
+}
+
+// @Summary Create permission
+// @Description Register a permission so it can be granted to roles
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body service.CreatePermissionInput true "Permission details"
+// @Success 201 {object} model.Permission
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Router /admin/permissions [post]
+func (h *RBACHandler) CreatePermission(c *fiber.Ctx) error {
+	input := *middleware.Payload[service.CreatePermissionInput](c)
+
+	permission, err := h.rbacService.CreatePermission(c.UserContext(), input)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(permission)
+}
+
+// @Summary List translator assignments
+// @Description List the language pairs and categories a user may edit
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {array} model.TranslatorAssignment
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /admin/users/{id}/assignments [get]
+func (h *RBACHandler) ListAssignments(c *fiber.Ctx) error {
+	id := middleware.Payload[IDPath](c).ID
+
+	assignments, err := h.rbacService.ListAssignments(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(assignments)
+}
+
+// @Summary Assign translator
+// @Description Allow a user to edit translations of a language pair and/or category. Leads may also edit approved translations.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param input body service.CreateAssignmentInput true "Assignment scope"
+// @Success 201 {object} model.TranslatorAssignment
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /admin/users/{id}/assignments [post]
+func (h *RBACHandler) CreateAssignment(c *fiber.Ctx) error {
+	id := middleware.Payload[IDPath](c).ID
+
+	input := *middleware.Payload[service.CreateAssignmentInput](c)
+
+	assignment, err := h.rbacService.CreateAssignment(c.UserContext(), id, input)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(assignment)
+}
+
+// @Summary Remove translator assignment
+// @Tags admin
+// @Security BearerAuth
+// @Param id path int true "Assignment ID"
+// @Success 204
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /admin/assignments/{id} [delete]
+func (h *RBACHandler) DeleteAssignment(c *fiber.Ctx) error {
+	id := middleware.Payload[IDPath](c).ID
+
+	if err := h.rbacService.DeleteAssignment(c.UserContext(), id); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/language"
+	"github.com/vietgs03/translate/backend/internal/middleware"
 	"github.com/vietgs03/translate/backend/internal/repository"
 	"github.com/vietgs03/translate/backend/internal/service"
 	"github.com/vietgs03/translate/backend/internal/types"
@@ -28,15 +32,13 @@ func NewTranslationHandler(ts service.TranslationService) *TranslationHandler {
 // @Security BearerAuth
 // @Param input body service.CreateTranslationInput true "Translation details"
 // @Success 201 {object} model.Translation
-// @Failure 400 {object} types.APIError
-// @Failure 401 {object} types.APIError
-// @Failure 403 {object} types.APIError
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 403 {object} types.Problem
 // @Router /translations [post]
+// @Router /projects/{projectID}/translations [post]
 func (h *TranslationHandler) Create(c *fiber.Ctx) error {
-	var input service.CreateTranslationInput
-	if err := c.BodyParser(&input); err != nil {
-		return errors.NewValidationError("invalid request body: %v", err)
-	}
+	input := *middleware.Payload[service.CreateTranslationInput](c)
 
 	// Get user from JWT claims
 	user, ok := c.Locals("user").(*types.JWTClaims)
@@ -46,7 +48,7 @@ func (h *TranslationHandler) Create(c *fiber.Ctx) error {
 	
 	input.CreatedBy = user.Username
 
-	translation, err := h.translationService.CreateTranslation(c.Context(), input)
+	translation, err := h.translationService.CreateTranslation(c.UserContext(), scopeFrom(c), input)
 	if err != nil {
 		return err
 	}
@@ -55,12 +57,9 @@ func (h *TranslationHandler) Create(c *fiber.Ctx) error {
 }
 
 func (h *TranslationHandler) Get(c *fiber.Ctx) error {
-	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
-	if err != nil {
-		return errors.NewValidationError("Invalid ID format")
-	}
+	id := middleware.Payload[IDPath](c).ID
 
-	translation, err := h.translationService.GetTranslation(c.Context(), uint(id))
+	translation, err := h.translationService.GetTranslation(c.UserContext(), scopeFrom(c), id)
 	if err != nil {
 		return err
 	}
@@ -68,57 +67,203 @@ func (h *TranslationHandler) Get(c *fiber.Ctx) error {
 	return c.JSON(translation)
 }
 
+// ListTranslationsQuery is the query string of the translation listing.
+// Languages and dates stay strings here; List canonicalizes and parses them.
+type ListTranslationsQuery struct {
+	SourceLanguage string `query:"source_lang"`
+	TargetLanguage string `query:"target_lang"`
+	Category       string `query:"category" validate:"max=50"`
+	CreatedBy      string `query:"created_by" validate:"max=255"`
+	Status         string `query:"status" validate:"omitempty,oneof=pending approved rejected"`
+	Provider       string `query:"provider" validate:"max=50"`
+	MinVotes       *int   `query:"min_votes" validate:"omitempty,min=0"`
+	CreatedAfter   string `query:"created_after"`
+	CreatedBefore  string `query:"created_before"`
+	UpdatedAfter   string `query:"updated_after"`
+	UpdatedBefore  string `query:"updated_before"`
+	Sort           string `query:"sort" default:"created_at" validate:"oneof=created_at updated_at votes"`
+	Order          string `query:"order" default:"desc" validate:"oneof=asc desc"`
+	Cursor         string `query:"cursor"`
+	// Page switches to offset pagination
+	Page     *int   `query:"page" validate:"omitempty,min=1"`
+	PageSize int    `query:"page_size" default:"10" validate:"page_size"`
+	Count    string `query:"count" default:"none" validate:"oneof=none exact estimated"`
+}
+
 // @Summary List translations
-// @Description Get a list of translations with pagination
+// @Description Get a list of translations. Without page the listing uses keyset pagination: follow next_cursor/prev_cursor or links.next/links.prev. Sending page switches to offset pagination.
 // @Tags translations
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param page query int false "Page number"
-// @Param page_size query int false "Page size"
+// @Param source_lang query string false "Source language, a BCP 47 tag"
+// @Param target_lang query string false "Target language, a BCP 47 tag"
+// @Param category query string false "Category"
+// @Param created_by query string false "Author username"
+// @Param status query string false "Review status" Enums(pending, approved, rejected)
+// @Param provider query string false "Translation provider" Enums(openai, gemini, manual)
+// @Param min_votes query int false "Minimum number of votes"
+// @Param created_after query string false "Created at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param created_before query string false "Created before (RFC 3339 or YYYY-MM-DD)"
+// @Param updated_after query string false "Updated at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param updated_before query string false "Updated before (RFC 3339 or YYYY-MM-DD)"
+// @Param sort query string false "Sort field" Enums(created_at, updated_at, votes)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param cursor query string false "Opaque cursor from a previous page"
+// @Param page query int false "Page number (offset pagination)"
+// @Param page_size query int false "Page size (max 100)"
+// @Param count query string false "Total count mode" Enums(none, exact, estimated)
 // @Success 200 {object} types.PaginatedResponse{data=[]model.Translation}
-// @Failure 400 {object} types.APIError
-// @Failure 401 {object} types.APIError
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
 // @Router /translations [get]
+// @Router /projects/{projectID}/translations [get]
 func (h *TranslationHandler) List(c *fiber.Ctx) error {
+	query := middleware.Payload[ListTranslationsQuery](c)
 	filter := repository.TranslationFilter{
-		SourceLanguage: c.Query("source_lang"),
-		TargetLanguage: c.Query("target_lang"),
-		Category:       c.Query("category"),
+		Category:  query.Category,
+		CreatedBy: query.CreatedBy,
+		Status:    query.Status,
+		Provider:  query.Provider,
+		MinVotes:  query.MinVotes,
+		SortBy:    repository.SortField(query.Sort),
+		SortOrder: repository.SortOrder(query.Order),
+		PageSize:  query.PageSize,
+	}
+	if query.Page != nil {
+		filter.Page = *query.Page
+	}
+
+	var err error
+	if filter.SourceLanguage, err = parseLanguageQuery("source_lang", query.SourceLanguage); err != nil {
+		return err
+	}
+	if filter.TargetLanguage, err = parseLanguageQuery("target_lang", query.TargetLanguage); err != nil {
+		return err
+	}
+	if filter.CreatedAfter, err = parseTimeQuery("created_after", query.CreatedAfter); err != nil {
+		return err
+	}
+	if filter.CreatedBefore, err = parseTimeQuery("created_before", query.CreatedBefore); err != nil {
+		return err
+	}
+	if filter.UpdatedAfter, err = parseTimeQuery("updated_after", query.UpdatedAfter); err != nil {
+		return err
+	}
+	if filter.UpdatedBefore, err = parseTimeQuery("updated_before", query.UpdatedBefore); err != nil {
+		return err
+	}
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return errors.NewValidationError("created_after must be before created_before")
+	}
+	if filter.UpdatedAfter != nil && filter.UpdatedBefore != nil && !filter.UpdatedAfter.Before(*filter.UpdatedBefore) {
+		return errors.NewValidationError("updated_after must be before updated_before")
 	}
 
-	// Parse pagination
-	page, _ := strconv.Atoi(c.Query("page", "1"))
-	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
-	filter.Page = page
-	filter.PageSize = pageSize
+	if query.Cursor != "" {
+		if filter.Page > 0 {
+			return errors.NewValidationError("page and cursor cannot be used together")
+		}
+		cursor, err := repository.DecodeCursor(query.Cursor)
+		if err != nil {
+			return errors.NewValidationError("invalid cursor")
+		}
+		if cursor.Sort != filter.SortBy || cursor.Order != filter.SortOrder {
+			return errors.NewValidationError("cursor does not match the requested sort")
+		}
+		filter.Cursor = cursor
+	}
 
-	translations, err := h.translationService.ListTranslations(c.Context(), filter)
+	page, err := h.translationService.ListTranslations(c.UserContext(), scopeFrom(c), filter, repository.CountMode(query.Count))
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"data": translations,
-		"pagination": fiber.Map{
-			"page":      page,
-			"page_size": pageSize,
+	response := types.PaginatedResponse{
+		Data: page.Items,
+		Pagination: types.Pagination{
+			Page:           filter.Page,
+			PageSize:       filter.PageSize,
+			Total:          page.Total,
+			TotalEstimated: page.TotalEstimated,
+			NextCursor:     page.NextCursor,
+			PrevCursor:     page.PrevCursor,
 		},
-	})
+	}
+
+	if filter.Page > 0 {
+		if page.HasNext {
+			response.Links.Next = pageLink(c, map[string]string{"page": strconv.Itoa(filter.Page + 1)})
+		}
+		if page.HasPrev {
+			response.Links.Prev = pageLink(c, map[string]string{"page": strconv.Itoa(filter.Page - 1)})
+		}
+	} else {
+		if page.NextCursor != "" {
+			response.Links.Next = pageLink(c, map[string]string{"cursor": page.NextCursor})
+		}
+		if page.PrevCursor != "" {
+			response.Links.Prev = pageLink(c, map[string]string{"cursor": page.PrevCursor})
+		}
+	}
+
+	return c.JSON(response)
 }
 
-func (h *TranslationHandler) Update(c *fiber.Ctx) error {
-	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+// parseTimeQuery parses an optional RFC 3339 timestamp or YYYY-MM-DD date
+// query parameter
+func parseTimeQuery(name, raw string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return &t, nil
+		}
+	}
+
+	return nil, errors.NewValidationError("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", name)
+}
+
+// parseLanguageQuery canonicalizes a BCP 47 tag parameter, so "pt-br" finds
+// translations stored as "pt-BR"
+func parseLanguageQuery(name, raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+	tag, err := language.Canonicalize(raw)
 	if err != nil {
-		return errors.NewValidationError("Invalid ID format")
+		return "", errors.NewValidationError("%s: %v", name, err)
 	}
+	return tag, nil
+}
+
+// IDPath is the :id parameter of routes addressing a single record
+type IDPath struct {
+	ID uint `params:"id" validate:"required"`
+}
 
-	var input service.UpdateTranslationInput
-	if err := c.BodyParser(&input); err != nil {
-		return errors.NewValidationError("Invalid request body")
+// pageLink rebuilds the current request URL with the given query parameters replaced
+func pageLink(c *fiber.Ctx, params map[string]string) string {
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+	for key, value := range params {
+		query.Set(key, value)
 	}
+	return c.BaseURL() + c.Path() + "?" + query.Encode()
+}
 
-	translation, err := h.translationService.UpdateTranslation(c.Context(), uint(id), input)
+func (h *TranslationHandler) Update(c *fiber.Ctx) error {
+	id := middleware.Payload[IDPath](c).ID
+
+	input := *middleware.Payload[service.UpdateTranslationInput](c)
+
+	user, ok := c.Locals("user").(*types.JWTClaims)
+	if !ok {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	translation, err := h.translationService.UpdateTranslation(c.UserContext(), user, scopeFrom(c), id, input)
 	if err != nil {
 		return err
 	}
@@ -126,13 +271,36 @@ func (h *TranslationHandler) Update(c *fiber.Ctx) error {
 	return c.JSON(translation)
 }
 
-func (h *TranslationHandler) Delete(c *fiber.Ctx) error {
-	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+// @Summary Review translation
+// @Description Approve or reject a translation, or put it back to pending
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Translation ID"
+// @Param input body service.ReviewTranslationInput true "New status"
+// @Success 200 {object} model.Translation
+// @Failure 400 {object} types.Problem
+// @Failure 401 {object} types.Problem
+// @Failure 404 {object} types.Problem
+// @Router /translations/{id}/status [put]
+func (h *TranslationHandler) Review(c *fiber.Ctx) error {
+	id := middleware.Payload[IDPath](c).ID
+
+	input := *middleware.Payload[service.ReviewTranslationInput](c)
+
+	translation, err := h.translationService.ReviewTranslation(c.UserContext(), scopeFrom(c), id, input)
 	if err != nil {
-		return errors.NewValidationError("Invalid ID format")
+		return err
 	}
 
-	if err := h.translationService.DeleteTranslation(c.Context(), uint(id)); err != nil {
+	return c.JSON(translation)
+}
+
+func (h *TranslationHandler) Delete(c *fiber.Ctx) error {
+	id := middleware.Payload[IDPath](c).ID
+
+	if err := h.translationService.DeleteTranslation(c.UserContext(), scopeFrom(c), id); err != nil {
 		return err
 	}
 
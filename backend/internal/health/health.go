@@ -0,0 +1,191 @@
+// Package health runs the readiness checks of the service. Checks run in
+// parallel, each under its own timeout, and a report is reused for a short
+// while so frequent orchestrator probes don't reach the dependencies.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vietgs03/translate/backend/internal/logging"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// Statuses of a check and of a whole report
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+)
+
+// Check is one dependency readiness depends on
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+	// Optional checks failing leave the service ready but degraded
+	Optional bool
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Status    string  `json:"status" example:"ok"`
+	LatencyMS float64 `json:"latency_ms" example:"1.3"`
+	// Error is logged rather than sent, it may name internal hosts
+	Error string `json:"-"`
+}
+
+// Report is the outcome of all checks
+type Report struct {
+	Status    string            `json:"status" example:"ok"`
+	CheckedAt time.Time         `json:"checked_at"`
+	Checks    map[string]Result `json:"checks"`
+}
+
+// Ready reports whether every required check passed
+func (r Report) Ready() bool {
+	return r.Status != StatusUnavailable
+}
+
+// Checker reports on a fixed set of checks
+type Checker struct {
+	checks  []Check
+	timeout time.Duration
+	ttl     time.Duration
+
+	mu     sync.Mutex
+	last   Report
+	flight singleflight.Group
+}
+
+// NewChecker runs checks with timeout each and reuses a report for ttl
+func NewChecker(timeout, ttl time.Duration, checks ...Check) *Checker {
+	return &Checker{
+		checks:  checks,
+		timeout: timeout,
+		ttl:     ttl,
+	}
+}
+
+// Check returns the latest report, running the checks when it is older
+// than the TTL. Concurrent callers share one run.
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.Lock()
+	last := c.last
+	c.mu.Unlock()
+	if !last.CheckedAt.IsZero() && time.Since(last.CheckedAt) < c.ttl {
+		return last
+	}
+
+	report, _, _ := c.flight.Do("check", func() (interface{}, error) {
+		// A probe giving up must not fail the checks others wait for
+		report := c.run(context.WithoutCancel(ctx))
+		c.mu.Lock()
+		c.last = report
+		c.mu.Unlock()
+		return report, nil
+	})
+	return report.(Report)
+}
+
+func (c *Checker) run(ctx context.Context) Report {
+	report := Report{
+		Status:    StatusOK,
+		CheckedAt: time.Now(),
+		Checks:    make(map[string]Result, len(c.checks)),
+	}
+
+	results := make([]Result, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = c.runOne(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for i, check := range c.checks {
+		result := results[i]
+		report.Checks[check.Name] = result
+		if result.Status == StatusOK {
+			continue
+		}
+		logging.FromContext(ctx).Warn("health check failed",
+			zap.String("check", check.Name),
+			zap.Bool("optional", check.Optional),
+			zap.String("error", result.Error),
+		)
+		if !check.Optional {
+			report.Status = StatusUnavailable
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (c *Checker) runOne(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// Checks ignoring their context still can't hold up the report
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- check.Run(ctx) }()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", c.timeout)
+	}
+	result := Result{
+		Status:    StatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusUnavailable
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Every reuses the outcome of run for interval, for checks too expensive
+// to run on every report. Reused outcomes report almost no latency. Only
+// one run is in flight at a time, bounded by timeout; callers giving up on
+// it don't cancel it for the others.
+func Every(interval, timeout time.Duration, run func(ctx context.Context) error) func(ctx context.Context) error {
+	var (
+		mu      sync.Mutex
+		last    time.Time
+		lastErr error
+		flight  singleflight.Group
+	)
+	return func(ctx context.Context) error {
+		mu.Lock()
+		fresh, err := !last.IsZero() && time.Since(last) < interval, lastErr
+		mu.Unlock()
+		if fresh {
+			return err
+		}
+
+		results := flight.DoChan("run", func() (interface{}, error) {
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+			defer cancel()
+			err := run(ctx)
+			mu.Lock()
+			last, lastErr = time.Now(), err
+			mu.Unlock()
+			return nil, err
+		})
+		select {
+		case result := <-results:
+			return result.Err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
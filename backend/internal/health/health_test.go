@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	failing := func(context.Context) error { return errors.New("connection refused") }
+
+	t.Run("optional failures degrade, required ones make it unavailable", func(t *testing.T) {
+		report := NewChecker(time.Second, 0,
+			Check{Name: "postgres", Run: ok},
+			Check{Name: "provider", Run: failing, Optional: true},
+		).Check(context.Background())
+		assert.Equal(t, StatusDegraded, report.Status)
+		assert.True(t, report.Ready())
+		assert.Equal(t, StatusOK, report.Checks["postgres"].Status)
+		assert.Equal(t, StatusUnavailable, report.Checks["provider"].Status)
+
+		report = NewChecker(time.Second, 0,
+			Check{Name: "postgres", Run: failing},
+			Check{Name: "provider", Run: ok, Optional: true},
+		).Check(context.Background())
+		assert.Equal(t, StatusUnavailable, report.Status)
+		assert.False(t, report.Ready())
+		assert.Equal(t, "connection refused", report.Checks["postgres"].Error)
+	})
+
+	t.Run("a check ignoring its context times out", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		stuck := func(context.Context) error {
+			<-release
+			return nil
+		}
+
+		start := time.Now()
+		report := NewChecker(20*time.Millisecond, 0, Check{Name: "redis", Run: stuck}).Check(context.Background())
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, StatusUnavailable, report.Status)
+		assert.Contains(t, report.Checks["redis"].Error, "timed out")
+		assert.GreaterOrEqual(t, report.Checks["redis"].LatencyMS, float64(20))
+	})
+
+	t.Run("probes within the TTL reuse one run", func(t *testing.T) {
+		var runs atomic.Int32
+		counted := func(context.Context) error {
+			runs.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}
+		checker := NewChecker(time.Second, time.Minute, Check{Name: "postgres", Run: counted})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				require.Equal(t, StatusOK, checker.Check(context.Background()).Status)
+			}()
+		}
+		wg.Wait()
+		checker.Check(context.Background())
+		assert.Equal(t, int32(1), runs.Load())
+	})
+}
+
+func TestEvery(t *testing.T) {
+	t.Run("a slow run doesn't hold up callers", func(t *testing.T) {
+		var runs atomic.Int32
+		release := make(chan struct{})
+		slow := Every(time.Minute, time.Minute, func(context.Context) error {
+			runs.Add(1)
+			<-release
+			return errors.New("provider unreachable")
+		})
+
+		// Every caller gives up on its own deadline while one run is in flight
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+				defer cancel()
+				start := time.Now()
+				assert.ErrorIs(t, slow(ctx), context.DeadlineExceeded)
+				assert.Less(t, time.Since(start), time.Second)
+			}()
+		}
+		wg.Wait()
+
+		close(release)
+		assert.Eventually(t, func() bool {
+			return slow(context.Background()) != nil
+		}, time.Second, 5*time.Millisecond)
+		assert.EqualError(t, slow(context.Background()), "provider unreachable", "the outcome is reused")
+		assert.Equal(t, int32(1), runs.Load())
+	})
+
+	t.Run("a run is bounded by the timeout", func(t *testing.T) {
+		probe := Every(time.Minute, 20*time.Millisecond, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.ErrorIs(t, probe(context.Background()), context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,259 @@
+// Package language parses BCP 47 language tags and holds the registry of
+// languages and language pairs the service translates between.
+package language
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// MaxTagLength is the longest tag accepted, the length BCP 47 asks
+// implementations to support
+const MaxTagLength = 35
+
+// Canonicalize parses a BCP 47 tag and returns its canonical form, so
+// "pt-br" becomes "pt-BR" and the deprecated "iw" becomes "he". Tags of
+// unknown languages, "und" and tags with extensions or private use subtags
+// are rejected.
+func Canonicalize(tag string) (string, error) {
+	if len(tag) > MaxTagLength {
+		return "", fmt.Errorf("%q is longer than %d characters", tag, MaxTagLength)
+	}
+	parsed, err := language.Parse(tag)
+	if err != nil {
+		return "", fmt.Errorf("%q is not a valid BCP 47 language tag", tag)
+	}
+	if _, confidence := parsed.Base(); confidence != language.Exact || parsed == language.Und {
+		return "", fmt.Errorf("%q does not name a language", tag)
+	}
+	if len(parsed.Extensions()) > 0 {
+		return "", fmt.Errorf("%q has extensions, which are not supported", tag)
+	}
+	return parsed.String(), nil
+}
+
+// Language describes a supported language
+type Language struct {
+	Tag        string `json:"tag" example:"pt-BR"`
+	Name       string `json:"name" example:"Brazilian Portuguese"`
+	NativeName string `json:"native_name" example:"português"`
+}
+
+// Pair is a direction of translation
+type Pair struct {
+	Source string `json:"source" example:"en"`
+	Target string `json:"target" example:"vi"`
+}
+
+// Coverage is what a translation provider declares it can translate: any
+// pair of its Languages, or only its Pairs when there are any. Empty
+// Languages means any language. Pairs are written "source:target".
+type Coverage struct {
+	Languages []string
+	Pairs     []string
+}
+
+// Registry is the set of supported languages and, optionally, the pairs
+// between them. A tag is supported when it or a broader tag is
+// registered: with "pt" registered, "pt-BR" is supported too.
+type Registry struct {
+	languages []Language
+	known     map[string]bool
+	// pairs is nil when every pair of different languages is supported
+	pairs map[Pair]bool
+}
+
+// NewRegistry builds a registry of tags, limited to pairs ("source:target")
+// when any are given
+func NewRegistry(tags []string, pairs []string) (*Registry, error) {
+	r := &Registry{known: make(map[string]bool)}
+	var problems []string
+	for _, tag := range tags {
+		canonical, err := Canonicalize(strings.TrimSpace(tag))
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if r.known[canonical] {
+			continue
+		}
+		r.known[canonical] = true
+		r.languages = append(r.languages, describe(canonical))
+	}
+
+	if len(pairs) > 0 {
+		r.pairs = make(map[Pair]bool)
+	}
+	for _, raw := range pairs {
+		source, target, ok := strings.Cut(raw, ":")
+		if !ok {
+			problems = append(problems, fmt.Sprintf("language pair %q must look like source:target", raw))
+			continue
+		}
+		pair, err := r.pair(source, target)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("language pair %q: %v", raw, err))
+			continue
+		}
+		r.pairs[pair] = true
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	if len(r.languages) == 0 {
+		return nil, fmt.Errorf("no supported languages")
+	}
+	return r, nil
+}
+
+func (r *Registry) pair(source, target string) (Pair, error) {
+	s, err := r.resolve(source)
+	if err != nil {
+		return Pair{}, err
+	}
+	t, err := r.resolve(target)
+	if err != nil {
+		return Pair{}, err
+	}
+	return Pair{Source: s, Target: t}, nil
+}
+
+// resolve returns the registered tag a tag from configuration falls under
+func (r *Registry) resolve(tag string) (string, error) {
+	canonical, err := Canonicalize(strings.TrimSpace(tag))
+	if err != nil {
+		return "", err
+	}
+	registered, ok := r.match(canonical)
+	if !ok {
+		return "", fmt.Errorf("%s is not a supported language", canonical)
+	}
+	return registered, nil
+}
+
+// match returns the registered tag a canonical tag falls under, trying the
+// tag itself and then dropping subtags from the end
+func (r *Registry) match(tag string) (string, bool) {
+	for tag != "" {
+		if r.known[tag] {
+			return tag, true
+		}
+		i := strings.LastIndexByte(tag, '-')
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	return "", false
+}
+
+// Languages lists the supported languages in the order they were registered
+func (r *Registry) Languages() []Language {
+	return append([]Language(nil), r.languages...)
+}
+
+// Pairs lists the supported pairs, or nil when every pair of different
+// languages is supported
+func (r *Registry) Pairs() []Pair {
+	if r.pairs == nil {
+		return nil
+	}
+	pairs := make([]Pair, 0, len(r.pairs))
+	for pair := range r.pairs {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Source != pairs[j].Source {
+			return pairs[i].Source < pairs[j].Source
+		}
+		return pairs[i].Target < pairs[j].Target
+	})
+	return pairs
+}
+
+// Supported reports whether the canonical tag is a supported language
+func (r *Registry) Supported(tag string) bool {
+	_, ok := r.match(tag)
+	return ok
+}
+
+// SupportsPair reports whether translating between the canonical tags is
+// supported
+func (r *Registry) SupportsPair(source, target string) bool {
+	s, ok := r.match(source)
+	if !ok {
+		return false
+	}
+	t, ok := r.match(target)
+	if !ok {
+		return false
+	}
+	return r.pairs == nil || r.pairs[Pair{Source: s, Target: t}]
+}
+
+// Restrict returns the part of the registry a provider with coverage c can
+// serve
+func (r *Registry) Restrict(c Coverage) (*Registry, error) {
+	if len(c.Languages) == 0 && len(c.Pairs) == 0 {
+		return r, nil
+	}
+	provider := r
+	if len(c.Languages) > 0 {
+		var err error
+		if provider, err = NewRegistry(c.Languages, nil); err != nil {
+			return nil, fmt.Errorf("invalid provider coverage: %v", err)
+		}
+	}
+	// Provider pairs resolve against the provider's own languages
+	if len(c.Pairs) > 0 {
+		withPairs, err := NewRegistry(languageTags(provider), c.Pairs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid provider coverage: %v", err)
+		}
+		provider = withPairs
+	}
+
+	restricted := &Registry{known: make(map[string]bool)}
+	for _, l := range r.languages {
+		if provider.Supported(l.Tag) {
+			restricted.known[l.Tag] = true
+			restricted.languages = append(restricted.languages, l)
+		}
+	}
+	if r.pairs != nil || provider.pairs != nil {
+		restricted.pairs = make(map[Pair]bool)
+		for _, source := range restricted.languages {
+			for _, target := range restricted.languages {
+				if source.Tag != target.Tag && r.SupportsPair(source.Tag, target.Tag) && provider.SupportsPair(source.Tag, target.Tag) {
+					restricted.pairs[Pair{Source: source.Tag, Target: target.Tag}] = true
+				}
+			}
+		}
+	}
+	if len(restricted.languages) == 0 {
+		return nil, fmt.Errorf("the provider supports none of the configured languages")
+	}
+	return restricted, nil
+}
+
+func languageTags(r *Registry) []string {
+	tags := make([]string, len(r.languages))
+	for i, l := range r.languages {
+		tags[i] = l.Tag
+	}
+	return tags
+}
+
+func describe(tag string) Language {
+	t := language.MustParse(tag)
+	return Language{
+		Tag:        tag,
+		Name:       display.English.Tags().Name(t),
+		NativeName: display.Self.Name(t),
+	}
+}
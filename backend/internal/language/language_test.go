@@ -0,0 +1,81 @@
+package language
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalize(t *testing.T) {
+	canonical := map[string]string{
+		"en":         "en",
+		"EN":         "en",
+		"pt-br":      "pt-BR",
+		"zh-hant":    "zh-Hant",
+		"sr_Latn":    "sr-Latn",
+		"iw":         "he",
+		"es-419":     "es-419",
+		"zh-Hant-TW": "zh-Hant-TW",
+	}
+	for input, want := range canonical {
+		got, err := Canonicalize(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"", "zz", "und", "english", "en-US-x-private", "en-" + strings.Repeat("x", 40)} {
+		_, err := Canonicalize(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r, err := NewRegistry([]string{"en", "vi", "pt", "zh-Hant", "EN"}, nil)
+	require.NoError(t, err)
+	assert.Len(t, r.Languages(), 4)
+	assert.Equal(t, Language{Tag: "pt", Name: "Portuguese", NativeName: "português"}, r.Languages()[2])
+	assert.Nil(t, r.Pairs())
+
+	assert.True(t, r.Supported("pt-BR"), "narrower forms are covered")
+	assert.True(t, r.Supported("zh-Hant-TW"))
+	assert.False(t, r.Supported("zh-Hans"), "a sibling script is not")
+	assert.False(t, r.Supported("ja"))
+	assert.True(t, r.SupportsPair("en", "pt-BR"))
+
+	_, err = NewRegistry([]string{"en", "klingon"}, nil)
+	assert.Error(t, err)
+	_, err = NewRegistry([]string{"en", "vi"}, []string{"en:ja"})
+	assert.Error(t, err, "pairs must use registered languages")
+	_, err = NewRegistry([]string{"en", "vi"}, []string{"en-vi"})
+	assert.Error(t, err)
+
+	pairs, err := NewRegistry([]string{"en", "vi", "ja"}, []string{"en:vi", "en-GB:ja"})
+	require.NoError(t, err)
+	assert.Equal(t, []Pair{{"en", "ja"}, {"en", "vi"}}, pairs.Pairs())
+	assert.True(t, pairs.SupportsPair("en-US", "vi"))
+	assert.False(t, pairs.SupportsPair("vi", "en"))
+}
+
+func TestRestrict(t *testing.T) {
+	r, err := NewRegistry([]string{"en", "vi", "pt-BR", "ja"}, nil)
+	require.NoError(t, err)
+
+	same, err := r.Restrict(Coverage{})
+	require.NoError(t, err)
+	assert.Same(t, r, same)
+
+	// The provider knows "pt", which covers the configured "pt-BR"
+	narrowed, err := r.Restrict(Coverage{Languages: []string{"en", "pt", "ja"}, Pairs: []string{"en:pt", "en:ja", "ja:en"}})
+	require.NoError(t, err)
+	var tags []string
+	for _, l := range narrowed.Languages() {
+		tags = append(tags, l.Tag)
+	}
+	assert.Equal(t, []string{"en", "pt-BR", "ja"}, tags)
+	assert.Equal(t, []Pair{{"en", "ja"}, {"en", "pt-BR"}, {"ja", "en"}}, narrowed.Pairs())
+
+	_, err = r.Restrict(Coverage{Languages: []string{"fr"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+// Package lockout counts failed logins per username and per client IP to
+// slow down and eventually lock out password guessing.
+package lockout
+
+import (
+	"context"
+	"time"
+
+	"github.com/vietgs03/translate/backend/internal/config"
+)
+
+// Policy decides how failures turn into delays and lockouts
+type Policy struct {
+	// DelayAfter failures of a username, each further attempt waits twice as long
+	DelayAfter int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	// UserThreshold and IPThreshold failures within Window lock for Duration
+	UserThreshold int
+	IPThreshold   int
+	Window        time.Duration
+	Duration      time.Duration
+}
+
+func PolicyFromConfig(cfg *config.AuthConfig) Policy {
+	return Policy{
+		DelayAfter:    cfg.LoginDelayAfter,
+		BaseDelay:     time.Second,
+		MaxDelay:      time.Duration(cfg.LoginMaxDelay) * time.Second,
+		UserThreshold: cfg.LockoutThreshold,
+		IPThreshold:   cfg.IPLockoutThreshold,
+		Window:        time.Duration(cfg.LockoutWindow) * time.Minute,
+		Duration:      time.Duration(cfg.LockoutDuration) * time.Minute,
+	}
+}
+
+// Delay is how long an attempt waits after the given number of failures
+func (p Policy) Delay(failures int) time.Duration {
+	if p.DelayAfter <= 0 || failures < p.DelayAfter {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := p.DelayAfter; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// State is what is known about a login attempt before checking the password
+type State struct {
+	UserFailures int
+	IPFailures   int
+	UserLocked   bool
+	IPLocked     bool
+	// Delay is how long the attempt should wait before it is answered
+	Delay time.Duration
+}
+
+func (s State) Locked() bool {
+	return s.UserLocked || s.IPLocked
+}
+
+// Guard records failed logins. Usernames are counted whether or not the
+// account exists, so lockouts behave the same for unknown usernames.
+type Guard interface {
+	Check(ctx context.Context, username, ip string) (State, error)
+	// RecordFailure counts a failed attempt and returns the state after it;
+	// reaching a threshold locks the username or IP.
+	RecordFailure(ctx context.Context, username, ip string) (State, error)
+	// RecordSuccess forgets the failures of a username
+	RecordSuccess(ctx context.Context, username string) error
+	// Unlock lifts a username lockout and forgets its failures
+	Unlock(ctx context.Context, username string) error
+}
@@ -0,0 +1,19 @@
+package lockout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyDelay(t *testing.T) {
+	policy := Policy{DelayAfter: 3, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	assert.Equal(t, time.Duration(0), policy.Delay(2))
+	assert.Equal(t, time.Second, policy.Delay(3))
+	assert.Equal(t, 2*time.Second, policy.Delay(4))
+	assert.Equal(t, 4*time.Second, policy.Delay(5))
+	assert.Equal(t, 5*time.Second, policy.Delay(6))
+	assert.Equal(t, 5*time.Second, policy.Delay(100))
+}
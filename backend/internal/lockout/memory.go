@@ -0,0 +1,121 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryGuard is the single-process fallback used when no Redis server is
+// configured.
+type memoryGuard struct {
+	mu       sync.Mutex
+	policy   Policy
+	failures map[string]counter
+	locks    map[string]time.Time
+}
+
+type counter struct {
+	count   int
+	expires time.Time
+}
+
+func NewMemoryGuard(policy Policy) Guard {
+	return &memoryGuard{
+		policy:   policy,
+		failures: make(map[string]counter),
+		locks:    make(map[string]time.Time),
+	}
+}
+
+func (g *memoryGuard) Check(ctx context.Context, username, ip string) (State, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	userFailures := g.count(failKey("user", username), now)
+	return State{
+		UserFailures: userFailures,
+		IPFailures:   g.count(failKey("ip", ip), now),
+		UserLocked:   g.locked(lockKey("user", username), now),
+		IPLocked:     g.locked(lockKey("ip", ip), now),
+		Delay:        g.policy.Delay(userFailures),
+	}, nil
+}
+
+func (g *memoryGuard) RecordFailure(ctx context.Context, username, ip string) (State, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	g.evictExpired(now)
+	state := State{
+		UserFailures: g.increment(failKey("user", username), now),
+		IPFailures:   g.increment(failKey("ip", ip), now),
+	}
+	if g.policy.UserThreshold > 0 && state.UserFailures >= g.policy.UserThreshold {
+		g.lock("user", username, now)
+		state.UserLocked = true
+	}
+	if g.policy.IPThreshold > 0 && state.IPFailures >= g.policy.IPThreshold {
+		g.lock("ip", ip, now)
+		state.IPLocked = true
+	}
+	return state, nil
+}
+
+func (g *memoryGuard) RecordSuccess(ctx context.Context, username string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.failures, failKey("user", username))
+	return nil
+}
+
+func (g *memoryGuard) Unlock(ctx context.Context, username string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.failures, failKey("user", username))
+	delete(g.locks, lockKey("user", username))
+	return nil
+}
+
+func (g *memoryGuard) count(key string, now time.Time) int {
+	c, ok := g.failures[key]
+	if !ok || now.After(c.expires) {
+		return 0
+	}
+	return c.count
+}
+
+func (g *memoryGuard) increment(key string, now time.Time) int {
+	c, ok := g.failures[key]
+	if !ok || now.After(c.expires) {
+		c = counter{expires: now.Add(g.policy.Window)}
+	}
+	c.count++
+	g.failures[key] = c
+	return c.count
+}
+
+func (g *memoryGuard) locked(key string, now time.Time) bool {
+	until, ok := g.locks[key]
+	return ok && now.Before(until)
+}
+
+func (g *memoryGuard) lock(kind, value string, now time.Time) {
+	g.locks[lockKey(kind, value)] = now.Add(g.policy.Duration)
+	delete(g.failures, failKey(kind, value))
+}
+
+func (g *memoryGuard) evictExpired(now time.Time) {
+	for key, c := range g.failures {
+		if now.After(c.expires) {
+			delete(g.failures, key)
+		}
+	}
+	for key, until := range g.locks {
+		if now.After(until) {
+			delete(g.locks, key)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package lockout
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// incrementScript counts a failure, starting the window on the first one.
+// Setting the expiry in the same call means a counter can't be left behind
+// without one, which would lock the key out for good; one left by an older
+// release gets its expiry here.
+var incrementScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 or redis.call("PTTL", KEYS[1]) < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+type redisGuard struct {
+	redis  *redis.Client
+	policy Policy
+}
+
+func NewRedisGuard(redis *redis.Client, policy Policy) Guard {
+	return &redisGuard{redis: redis, policy: policy}
+}
+
+func failKey(kind, value string) string {
+	return fmt.Sprintf("auth:login:fail:%s:%s", kind, value)
+}
+
+func lockKey(kind, value string) string {
+	return fmt.Sprintf("auth:login:lock:%s:%s", kind, value)
+}
+
+func (g *redisGuard) Check(ctx context.Context, username, ip string) (State, error) {
+	values, err := g.redis.MGet(ctx,
+		failKey("user", username), failKey("ip", ip),
+		lockKey("user", username), lockKey("ip", ip),
+	).Result()
+	if err != nil {
+		return State{}, fmt.Errorf("failed to check login failures: %v", err)
+	}
+
+	userFailures := toInt(values[0])
+	return State{
+		UserFailures: userFailures,
+		IPFailures:   toInt(values[1]),
+		UserLocked:   values[2] != nil,
+		IPLocked:     values[3] != nil,
+		Delay:        g.policy.Delay(userFailures),
+	}, nil
+}
+
+func (g *redisGuard) RecordFailure(ctx context.Context, username, ip string) (State, error) {
+	userFailures, err := g.increment(ctx, failKey("user", username))
+	if err != nil {
+		return State{}, err
+	}
+	ipFailures, err := g.increment(ctx, failKey("ip", ip))
+	if err != nil {
+		return State{}, err
+	}
+
+	state := State{UserFailures: userFailures, IPFailures: ipFailures}
+	if g.policy.UserThreshold > 0 && userFailures >= g.policy.UserThreshold {
+		if err := g.lock(ctx, "user", username); err != nil {
+			return state, err
+		}
+		state.UserLocked = true
+	}
+	if g.policy.IPThreshold > 0 && ipFailures >= g.policy.IPThreshold {
+		if err := g.lock(ctx, "ip", ip); err != nil {
+			return state, err
+		}
+		state.IPLocked = true
+	}
+	return state, nil
+}
+
+func (g *redisGuard) RecordSuccess(ctx context.Context, username string) error {
+	return g.redis.Del(ctx, failKey("user", username)).Err()
+}
+
+func (g *redisGuard) Unlock(ctx context.Context, username string) error {
+	return g.redis.Del(ctx, failKey("user", username), lockKey("user", username)).Err()
+}
+
+// increment counts within a fixed window starting at the first failure
+func (g *redisGuard) increment(ctx context.Context, key string) (int, error) {
+	count, err := incrementScript.Run(ctx, g.redis, []string{key}, g.policy.Window.Milliseconds()).Int()
+	if err != nil {
+		return 0, fmt.Errorf("failed to record login failure: %v", err)
+	}
+	return count, nil
+}
+
+// lock starts a lockout and resets the counter, so the next lockout takes
+// another full threshold of failures
+func (g *redisGuard) lock(ctx context.Context, kind, value string) error {
+	_, err := g.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, lockKey(kind, value), 1, g.policy.Duration)
+		pipe.Del(ctx, failKey(kind, value))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to lock %s: %v", kind, err)
+	}
+	return nil
+}
+
+func toInt(value interface{}) int {
+	s, ok := value.(string)
+	if !ok {
+		return 0
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,39 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+	mailer := NewFileMailer(path, "no-reply@example.com")
+
+	require.NoError(t, mailer.Send(context.Background(), Message{To: "alice@example.com", Subject: "First", Body: "one\ntwo"}))
+	require.NoError(t, mailer.Send(context.Background(), Message{To: "bob@example.com", Subject: "Second", Body: "three"}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "To: alice@example.com\r\n")
+	assert.Contains(t, content, "Subject: Second\r\n")
+	assert.Contains(t, content, "one\r\ntwo")
+}
+
+func TestFormatKeepsHeadersOnOneLine(t *testing.T) {
+	message := string(format("no-reply@example.com", Message{
+		To:      "alice@example.com\r\nBcc: mallory@example.com",
+		Subject: "Hello\nX-Injected: yes",
+		Body:    "body",
+	}))
+
+	assert.Contains(t, message, "To: alice@example.com  Bcc: mallory@example.com\r\n")
+	assert.Contains(t, message, "Subject: Hello X-Injected: yes\r\n")
+	assert.NotContains(t, message, "\nBcc:")
+	assert.NotContains(t, message, "\nX-Injected:")
+}
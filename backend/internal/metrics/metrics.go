@@ -0,0 +1,155 @@
+// Package metrics holds the Prometheus collectors of the service and the
+// handler exposing them. Labels only ever take values from small fixed
+// sets, such as registered route templates, status codes and provider
+// names, never anything taken from a request as is.
+package metrics
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+	"github.com/vietgs03/translate/backend/internal/service/translator"
+)
+
+const namespace = "translate"
+
+// Where a translation lookup was answered from; coalesced lookups shared
+// the answer of an identical one in flight
+const (
+	LookupCacheHit     = "cache_hit"
+	LookupDatabaseHit  = "db_hit"
+	LookupProviderCall = "provider_call"
+	LookupCoalesced    = "coalesced"
+)
+
+// Registry holds every collector of the process, Go runtime and process
+// stats included
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of HTTP requests by method, route template and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	translationLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "translation",
+		Name:      "lookups_total",
+		Help:      "Translation lookups by where they were answered from: cache_hit, db_hit, provider_call or coalesced.",
+	}, []string{"outcome"})
+
+	providerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "provider",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of translation provider calls by provider and result.",
+		// Providers answer in seconds rather than milliseconds
+		Buckets: []float64{.1, .25, .5, 1, 2.5, 5, 10, 20, 30, 60},
+	}, []string{"provider", "result"})
+
+	providerErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "provider",
+		Name:      "errors_total",
+		Help:      "Failed translation provider calls by provider and kind of failure.",
+	}, []string{"provider", "type"})
+
+	providerThrottled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "provider",
+		Name:      "throttled_total",
+		Help:      "Translation provider calls refused by the local rate limiter before reaching the provider.",
+	}, []string{"provider"})
+
+	rateLimitRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ratelimit",
+		Name:      "rejections_total",
+		Help:      "Requests rejected by the rate limiter by bucket.",
+	}, []string{"bucket"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		translationLookups,
+		providerDuration,
+		providerErrors,
+		providerThrottled,
+		rateLimitRejections,
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format. A non-empty
+// token must be sent as a bearer token.
+func Handler(token string) fiber.Handler {
+	serve := adaptor.HTTPHandler(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+	if token == "" {
+		return serve
+	}
+	want := []byte("Bearer " + token)
+	return func(c *fiber.Ctx) error {
+		if subtle.ConstantTimeCompare([]byte(c.Get(fiber.HeaderAuthorization)), want) != 1 {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
+		return serve(c)
+	}
+}
+
+// ObserveHTTPRequest records a finished request. route must be the
+// template the request matched, not its path.
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	httpRequestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// TranslationLookup counts a lookup answered with outcome, one of the
+// Lookup constants
+func TranslationLookup(outcome string) {
+	translationLookups.WithLabelValues(outcome).Inc()
+}
+
+// ObserveProviderCall records a call to a translation provider and, when
+// it failed, the kind of failure. Calls the local rate limiter refused never
+// reached the provider, so they are only counted as throttled.
+func ObserveProviderCall(provider string, duration time.Duration, err error) {
+	if errors.Is(err, translator.ErrThrottled) {
+		providerThrottled.WithLabelValues(provider).Inc()
+		return
+	}
+	result := "success"
+	if err != nil {
+		result = "error"
+		providerErrors.WithLabelValues(provider, translator.ErrorKind(err)).Inc()
+	}
+	providerDuration.WithLabelValues(provider, result).Observe(duration.Seconds())
+}
+
+// RateLimited counts a request the rate limiter rejected
+func RateLimited(bucket string) {
+	rateLimitRejections.WithLabelValues(bucket).Inc()
+}
+
+// RegisterDB exports the connection pool stats of db
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// RegisterRedis exports the connection pool stats of client
+func RegisterRedis(client *redis.Client) error {
+	return Registry.Register(&redisCollector{client: client})
+}
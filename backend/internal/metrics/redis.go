@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	redisHits = prometheus.NewDesc(
+		namespace+"_redis_pool_hits_total",
+		"Times a free connection was found in the Redis pool.", nil, nil)
+	redisMisses = prometheus.NewDesc(
+		namespace+"_redis_pool_misses_total",
+		"Times no free connection was found in the Redis pool.", nil, nil)
+	redisTimeouts = prometheus.NewDesc(
+		namespace+"_redis_pool_timeouts_total",
+		"Times waiting for a Redis connection timed out.", nil, nil)
+	redisStale = prometheus.NewDesc(
+		namespace+"_redis_pool_stale_connections_total",
+		"Stale connections removed from the Redis pool.", nil, nil)
+	redisTotal = prometheus.NewDesc(
+		namespace+"_redis_pool_connections",
+		"Connections in the Redis pool.", nil, nil)
+	redisIdle = prometheus.NewDesc(
+		namespace+"_redis_pool_idle_connections",
+		"Idle connections in the Redis pool.", nil, nil)
+)
+
+// redisCollector reads the pool stats of a client on every scrape
+type redisCollector struct {
+	client *redis.Client
+}
+
+func (c *redisCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{redisHits, redisMisses, redisTimeouts, redisStale, redisTotal, redisIdle} {
+		ch <- desc
+	}
+}
+
+func (c *redisCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(redisHits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(redisMisses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(redisTimeouts, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(redisStale, prometheus.CounterValue, float64(stats.StaleConns))
+	ch <- prometheus.MustNewConstMetric(redisTotal, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(redisIdle, prometheus.GaugeValue, float64(stats.IdleConns))
+}
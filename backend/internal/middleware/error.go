@@ -1,29 +1,53 @@
 package middleware
 
 import (
+	"net/http"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/vietgs03/translate/backend/internal/audit"
+	"github.com/vietgs03/translate/backend/internal/errors"
 	"github.com/vietgs03/translate/backend/internal/types"
 )
 
+const mimeProblemJSON = "application/problem+json"
+
+// ErrorHandler answers errors with an RFC 7807 problem document. AppErrors
+// keep their type and code; Fiber's own errors, such as unknown routes, get a
+// code derived from their status; anything else is an internal error whose
+// message stays in the logs.
 func ErrorHandler(c *fiber.Ctx, err error) error {
-	// Convert error to APIError
-	apiError := &types.APIError{
-		Error: err.Error(),
+	problem := types.Problem{
+		Type:      "about:blank",
+		Instance:  c.Path(),
+		RequestID: requestID(c),
 	}
 
-	code := fiber.StatusInternalServerError
-
-	switch err.(type) {
-	case *fiber.Error:
-		e := err.(*fiber.Error)
-		code = e.Code
-	case *types.ValidationError:
-		code = fiber.StatusBadRequest
-	case *types.UnauthorizedError:
-		code = fiber.StatusUnauthorized
-	case *types.NotFoundError:
-		code = fiber.StatusNotFound
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		problem.Status = fiberErr.Code
+		problem.Code = strings.ToUpper(strings.ReplaceAll(http.StatusText(fiberErr.Code), " ", "_"))
+		problem.Detail = fiberErr.Message
+	} else {
+		appErr := errors.From(err)
+		problem.Status = appErr.Type.Status()
+		problem.Code = appErr.Code
+		problem.Errors = appErr.Fields
+		if appErr.Type.Exposed() {
+			problem.Detail = appErr.Message
+		}
 	}
+	if problem.Code == "" {
+		problem.Code = string(errors.InternalError)
+	}
+	problem.Title = http.StatusText(problem.Status)
+
+	return c.Status(problem.Status).JSON(problem, mimeProblemJSON)
+}
 
-	return c.Status(code).JSON(apiError)
-}
\ No newline at end of file
+func requestID(c *fiber.Ctx) string {
+	if r, ok := c.Locals(audit.RequestKey{}).(audit.Request); ok {
+		return r.RequestID
+	}
+	return c.Get(fiber.HeaderXRequestID)
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/types"
+	"go.uber.org/zap"
+)
+
+func TestErrorHandler(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(Logger(zap.NewNop()))
+	app.Get("/missing", func(c *fiber.Ctx) error {
+		return fmt.Errorf("loading: %w", errors.NewNotFoundError("translation not found"))
+	})
+	app.Get("/invalid", func(c *fiber.Ctx) error {
+		return errors.NewFieldValidationError(errors.FieldError{Field: "source_text", Message: "source_text is required"})
+	})
+	app.Get("/forbidden", func(c *fiber.Ctx) error {
+		return errors.NewForbiddenError("insufficient permissions: %s required", "translation:delete")
+	})
+	app.Get("/database", func(c *fiber.Ctx) error {
+		return errors.NewDatabaseError("failed to save translation: %v", "pq: duplicate key value")
+	})
+	app.Get("/unknown", func(c *fiber.Ctx) error {
+		return fmt.Errorf("dial tcp 10.0.0.5:6379: connection refused")
+	})
+
+	get := func(path, requestID string) (int, string, types.Problem) {
+		req := httptest.NewRequest("GET", path, nil)
+		if requestID != "" {
+			req.Header.Set(fiber.HeaderXRequestID, requestID)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var problem types.Problem
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+		assert.Equal(t, "application/problem+json", resp.Header.Get(fiber.HeaderContentType))
+		assert.Equal(t, resp.Header.Get(fiber.HeaderXRequestID), problem.RequestID)
+		return resp.StatusCode, problem.Code, problem
+	}
+
+	status, code, problem := get("/missing", "req-1")
+	assert.Equal(t, fiber.StatusNotFound, status)
+	assert.Equal(t, "NOT_FOUND", code)
+	assert.Equal(t, "translation not found", problem.Detail)
+	assert.Equal(t, "req-1", problem.RequestID)
+	assert.Equal(t, "/missing", problem.Instance)
+
+	status, code, problem = get("/invalid", "")
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, "VALIDATION_ERROR", code)
+	assert.Equal(t, []errors.FieldError{{Field: "source_text", Message: "source_text is required"}}, problem.Errors)
+	assert.NotEmpty(t, problem.RequestID)
+
+	status, code, _ = get("/forbidden", "")
+	assert.Equal(t, fiber.StatusForbidden, status)
+	assert.Equal(t, "FORBIDDEN", code)
+
+	// Database and unknown errors don't reach the client
+	status, code, problem = get("/database", "")
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Equal(t, "DATABASE_ERROR", code)
+	assert.Empty(t, problem.Detail)
+
+	status, code, problem = get("/unknown", "")
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Equal(t, "INTERNAL_ERROR", code)
+	assert.Empty(t, problem.Detail)
+
+	status, code, _ = get("/no-such-route", "")
+	assert.Equal(t, fiber.StatusNotFound, status)
+	assert.Equal(t, "NOT_FOUND", code)
+}
+
+func TestErrorsIsAndAs(t *testing.T) {
+	cause := fmt.Errorf("connection reset")
+	err := fmt.Errorf("create: %w", errors.NewDatabaseError("failed to save translation: %w", cause))
+
+	assert.True(t, errors.Is(err, errors.ErrDatabase))
+	assert.False(t, errors.Is(err, errors.ErrNotFound))
+	assert.True(t, errors.Is(err, cause))
+
+	var appErr *errors.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, errors.DatabaseErr, appErr.Type)
+
+	expired := errors.NewError(errors.Unauthorized, errors.CodeTokenExpired, "token expired")
+	assert.True(t, errors.Is(expired, errors.ErrUnauthorized))
+	assert.True(t, errors.Is(expired, &errors.AppError{Type: errors.Unauthorized, Code: errors.CodeTokenExpired}))
+	assert.False(t, errors.Is(expired, &errors.AppError{Type: errors.Unauthorized, Code: errors.CodeTokenRevoked}))
+}
@@ -1,16 +1,40 @@
 package middleware
 
 import (
+	"context"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/token"
 	"github.com/vietgs03/translate/backend/internal/types"
 )
 
-func JWTAuth(secretKey string) fiber.Handler {
+// APIKeyAuthenticator resolves an X-API-Key header to a principal
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*types.JWTClaims, error)
+}
+
+// UserStatusChecker reports whether a user may still use the API. It runs
+// on every request, so implementations should answer from a short cache.
+type UserStatusChecker interface {
+	IsActive(ctx context.Context, userID uint) (bool, error)
+}
+
+// JWTAuth authenticates a request by Bearer JWT or, for machine clients,
+// by X-API-Key. Both put a *types.JWTClaims into the "user" local.
+func JWTAuth(keys *token.KeySet, denylist token.Denylist, apiKeys APIKeyAuthenticator, users UserStatusChecker) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		if apiKey := c.Get("X-API-Key"); apiKey != "" {
+			claims, err := apiKeys.Authenticate(c.UserContext(), apiKey)
+			if err != nil {
+				return err
+			}
+			c.Locals("user", claims)
+			return c.Next()
+		}
+
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
 			return errors.NewUnauthorizedError("missing authorization header")
@@ -21,17 +45,46 @@ func JWTAuth(secretKey string) fiber.Handler {
 			return errors.NewUnauthorizedError("invalid token format")
 		}
 
-		token, err := jwt.ParseWithClaims(tokenString, &types.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return []byte(secretKey), nil
-		})
+		parsed, err := keys.Parse(tokenString, &types.JWTClaims{})
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return errors.NewError(errors.Unauthorized, errors.CodeTokenExpired, "token expired")
+		}
+		if err != nil {
+			return errors.NewError(errors.Unauthorized, errors.CodeInvalidToken, "invalid token: %v", err)
+		}
+
+		claims, ok := parsed.Claims.(*types.JWTClaims)
+		if !ok || !parsed.Valid {
+			return errors.NewError(errors.Unauthorized, errors.CodeInvalidToken, "invalid token claims")
+		}
+
+		// Reject revoked tokens
+		if claims.ID != "" {
+			revoked, err := denylist.IsRevoked(c.UserContext(), claims.ID)
+			if err != nil {
+				return err
+			}
+			if revoked {
+				return errors.NewError(errors.Unauthorized, errors.CodeTokenRevoked, "token has been revoked")
+			}
+		}
 
+		// Tokens issued before the last revocation of their user
+		version, err := denylist.TokenVersion(c.UserContext(), claims.UserID)
 		if err != nil {
-			return errors.NewUnauthorizedError("invalid token: %v", err)
+			return err
+		}
+		if claims.TokenVersion < version {
+			return errors.NewError(errors.Unauthorized, errors.CodeTokenRevoked, "token has been revoked")
 		}
 
-		claims, ok := token.Claims.(*types.JWTClaims)
-		if !ok || !token.Valid {
-			return errors.NewUnauthorizedError("invalid token claims")
+		// Deactivated and deleted users lose access before their tokens expire
+		active, err := users.IsActive(c.UserContext(), claims.UserID)
+		if err != nil {
+			return err
+		}
+		if !active {
+			return errors.NewError(errors.Unauthorized, errors.CodeAccountDisabled, "account is disabled")
 		}
 
 		// Add claims to context
@@ -40,19 +93,30 @@ func JWTAuth(secretKey string) fiber.Handler {
 	}
 }
 
-func RequireRole(roles ...string) fiber.Handler {
+// PermissionChecker resolves whether a principal holds a permission
+type PermissionChecker interface {
+	Allows(ctx context.Context, principal *types.JWTClaims, permission string) (bool, error)
+}
+
+// RequirePermission allows the request only if the caller holds every one
+// of the given permissions
+func RequirePermission(checker PermissionChecker, permissions ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		user, ok := c.Locals("user").(*types.JWTClaims)
 		if !ok {
 			return errors.NewUnauthorizedError("user not authenticated")
 		}
 
-		for _, role := range roles {
-			if user.Role == role {
-				return c.Next()
+		for _, permission := range permissions {
+			allowed, err := checker.Allows(c.UserContext(), user, permission)
+			if err != nil {
+				return err
+			}
+			if !allowed {
+				return errors.NewForbiddenError("insufficient permissions: %s required", permission)
 			}
 		}
 
-		return errors.NewUnauthorizedError("insufficient permissions")
+		return c.Next()
 	}
-} 
\ No newline at end of file
+}
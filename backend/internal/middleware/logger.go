@@ -4,7 +4,12 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 	"github.com/google/uuid"
+	"github.com/vietgs03/translate/backend/internal/audit"
+	"github.com/vietgs03/translate/backend/internal/logging"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -15,21 +20,35 @@ func Logger(logger *zap.Logger) fiber.Handler {
 		method := c.Method()
 
 		// Get request ID from header or generate new one
-		requestID := c.Get("X-Request-ID")
+		requestID := utils.CopyString(c.Get(fiber.HeaderXRequestID))
 		if requestID == "" {
 			requestID = uuid.NewString()
-			c.Set("X-Request-ID", requestID)
 		}
-
-		// Process request
+		c.Set(fiber.HeaderXRequestID, requestID)
+		c.Locals(audit.RequestKey{}, audit.Request{IP: c.IP(), RequestID: requestID})
+
+		// Handlers pass c.UserContext() on to services. It carries the
+		// trace, a logger tagged with the request ID and, like c.Context(),
+		// Fiber's locals.
+		ctx := c.UserContext()
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("request.id", requestID))
+		ctx = logging.NewContext(ctx, logger.With(zap.String("request_id", requestID)))
+		c.SetUserContext(localsContext{Context: ctx, locals: c.Context()})
+
+		// Process request. Errors are answered here rather than after the
+		// chain returns, so the log shows the status the client got.
 		err := c.Next()
+		if err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				c.Status(fiber.StatusInternalServerError)
+			}
+		}
 
 		// Log request details
 		duration := time.Since(start)
 		status := c.Response().StatusCode()
 
-		logger.Info("http request",
-			zap.String("request_id", requestID),
+		logging.FromContext(ctx).Info("http request",
 			zap.String("method", method),
 			zap.String("path", path),
 			zap.Int("status", status),
@@ -39,6 +58,6 @@ func Logger(logger *zap.Logger) fiber.Handler {
 			zap.Error(err),
 		)
 
-		return err
+		return nil
 	}
-} 
\ No newline at end of file
+}
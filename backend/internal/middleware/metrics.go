@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vietgs03/translate/backend/internal/metrics"
+)
+
+// Metrics records the duration and status of every request. It must run
+// before Logger, which answers errors, so the status is the one sent.
+//
+// Requests are labelled with the template of the route that handled them,
+// "/api/v1/translations/:id" rather than the path. Requests no route
+// matched carry the prefix of the last middleware they passed, so the label
+// only ever holds registered paths.
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+		metrics.ObserveHTTPRequest(c.Method(), c.Route().Path, c.Response().StatusCode(), time.Since(start))
+		return err
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/metrics"
+	"github.com/vietgs03/translate/backend/internal/service/translator"
+	"go.uber.org/zap"
+)
+
+func TestMetrics(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(Metrics())
+	app.Use(Logger(zap.NewNop()))
+	app.Get("/metrics", metrics.Handler(""))
+	app.Get("/metrics-private", metrics.Handler("scrape-token"))
+	app.Get("/metrics-test/:id", func(c *fiber.Ctx) error {
+		if c.Params("id") == "missing" {
+			return errors.NewNotFoundError("no such item")
+		}
+		return c.SendString("ok")
+	})
+
+	get := func(target string) (int, string) {
+		resp, err := app.Test(httptest.NewRequest("GET", target, nil), -1)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		content, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(content)
+	}
+
+	for i := 0; i < 3; i++ {
+		status, _ := get(fmt.Sprintf("/metrics-test/%d", i))
+		require.Equal(t, fiber.StatusOK, status)
+	}
+	status, _ := get("/metrics-test/missing")
+	require.Equal(t, fiber.StatusNotFound, status)
+	status, _ = get("/no/such/route")
+	require.Equal(t, fiber.StatusNotFound, status)
+
+	// Calls our own rate limiter refused never reached the provider
+	metrics.ObserveProviderCall("metrics-test", time.Second, translator.ErrThrottled)
+
+	status, content := get("/metrics")
+	require.Equal(t, fiber.StatusOK, status)
+
+	// A token keeps the metrics from anyone who can reach the port
+	status, _ = get("/metrics-private")
+	assert.Equal(t, fiber.StatusUnauthorized, status)
+	req := httptest.NewRequest("GET", "/metrics-private", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer scrape-token")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// Requests are counted by route template, with the status the error
+	// handler answered
+	assert.Contains(t, content, `translate_http_request_duration_seconds_count{method="GET",route="/metrics-test/:id",status="200"} 3`)
+	assert.Contains(t, content, `translate_http_request_duration_seconds_count{method="GET",route="/metrics-test/:id",status="404"} 1`)
+	assert.NotContains(t, content, "/no/such/route")
+	assert.NotContains(t, content, `route="/metrics-test/1"`)
+	assert.Contains(t, content, `translate_provider_throttled_total{provider="metrics-test"} 1`)
+	assert.NotContains(t, content, `translate_provider_request_duration_seconds_count{provider="metrics-test"`)
+	assert.NotContains(t, content, `translate_provider_errors_total{provider="metrics-test"`)
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/model"
+	"github.com/vietgs03/translate/backend/internal/types"
+)
+
+// ProjectResolver looks up a project and the caller's effective role in it
+type ProjectResolver interface {
+	ResolveProject(ctx context.Context, actor *types.JWTClaims, projectID uint) (*model.Project, string, error)
+}
+
+// ProjectPath addresses a project. Mount Validate[ProjectPath] ahead of
+// RequireProjectRole.
+type ProjectPath struct {
+	ProjectID uint `params:"projectID" validate:"required,min=1"`
+}
+
+// RequireProjectRole resolves the validated :projectID route parameter and
+// allows the request only if the caller's role in that project is at least
+// min. The project and role are stored in the "project" and "project_role"
+// locals.
+func RequireProjectRole(resolver ProjectResolver, min string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := c.Locals("user").(*types.JWTClaims)
+		if !ok {
+			return errors.NewUnauthorizedError("user not authenticated")
+		}
+
+		path := Payload[ProjectPath](c)
+		project, role, err := resolver.ResolveProject(c.UserContext(), user, path.ProjectID)
+		if err != nil {
+			return err
+		}
+		if !model.ProjectRoleAtLeast(role, min) {
+			return errors.NewForbiddenError("insufficient project role: %s required", min)
+		}
+
+		c.Locals("project", project)
+		c.Locals("project_role", role)
+		return c.Next()
+	}
+}
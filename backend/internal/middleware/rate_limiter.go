@@ -1,23 +1,62 @@
 package middleware
 
 import (
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/logging"
+	"github.com/vietgs03/translate/backend/internal/metrics"
+	"github.com/vietgs03/translate/backend/internal/ratelimit"
+	"github.com/vietgs03/translate/backend/internal/types"
+	"go.uber.org/zap"
 )
 
-func RateLimiter() fiber.Handler {
-	return limiter.New(limiter.Config{
-		Max:        100,
-		Expiration: 1 * time.Minute,
-		KeyGenerator: func(c *fiber.Ctx) string {
-			return c.IP()
-		},
-		LimitReached: func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"error": "Rate limit exceeded",
-			})
-		},
-	})
-} 
\ No newline at end of file
+// RateLimit counts requests against the bucket of the authenticated API key
+// or user, falling back to the client IP before authentication. The
+// RateLimit-* headers describe the tightest budget the request used.
+// Requests are let through when the counters can't be reached.
+func RateLimit(limiter ratelimit.Limiter, bucket string, policy ratelimit.Policy, allowlist *ratelimit.Allowlist) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := bucket + ":ip:" + c.IP()
+		role := ""
+		var userID, apiKeyID uint
+		if user, ok := c.Locals("user").(*types.JWTClaims); ok {
+			userID, apiKeyID, role = user.UserID, user.APIKeyID, user.Role
+			if apiKeyID != 0 {
+				key = bucket + ":key:" + strconv.FormatUint(uint64(apiKeyID), 10)
+			} else {
+				key = bucket + ":user:" + strconv.FormatUint(uint64(userID), 10)
+			}
+		}
+
+		if allowlist.Allows(c.IP(), userID, apiKeyID) {
+			return c.Next()
+		}
+
+		limit := policy.LimitFor(role)
+		result, err := limiter.Allow(c.UserContext(), key, limit, policy.Window)
+		if err != nil {
+			logging.FromContext(c.UserContext()).Error("failed to check rate limit", zap.Error(err))
+			return c.Next()
+		}
+
+		reset := strconv.Itoa(int(math.Ceil(result.Reset.Seconds())))
+		if remaining, err := strconv.Atoi(string(c.Response().Header.Peek("RateLimit-Remaining"))); err != nil || result.Remaining <= remaining || !result.Allowed {
+			c.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			c.Set("RateLimit-Reset", reset)
+			c.Set("RateLimit-Policy", strconv.Itoa(limit)+";w="+strconv.Itoa(int(policy.Window/time.Second)))
+		}
+
+		if !result.Allowed {
+			metrics.RateLimited(bucket)
+			c.Set(fiber.HeaderRetryAfter, reset)
+			return errors.NewRateLimitError("rate limit exceeded, retry in %s seconds", reset)
+		}
+
+		return c.Next()
+	}
+}
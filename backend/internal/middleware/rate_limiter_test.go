@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vietgs03/translate/backend/internal/ratelimit"
+	"github.com/vietgs03/translate/backend/internal/types"
+)
+
+func TestRateLimit(t *testing.T) {
+	// Test requests come from 0.0.0.0, trusted to name the client
+	app := fiber.New(fiber.Config{
+		ErrorHandler:            ErrorHandler,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          []string{"0.0.0.0"},
+		ProxyHeader:             fiber.HeaderXForwardedFor,
+		EnableIPValidation:      true,
+	})
+	app.Use(func(c *fiber.Ctx) error {
+		if c.Get("X-Test-User") != "" {
+			c.Locals("user", &types.JWTClaims{UserID: 7, Username: "svc"})
+		}
+		return c.Next()
+	})
+	policy := ratelimit.Policy{Window: time.Minute, Limit: 2}
+	allowlist := ratelimit.NewAllowlist([]string{"10.0.0.0/8", "user:7"})
+	app.Use(RateLimit(ratelimit.NewMemoryLimiter(), "auth", policy, allowlist))
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	get := func(ip string, user bool) (int, map[string]string) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(fiber.HeaderXForwardedFor, ip)
+		if user {
+			req.Header.Set("X-Test-User", "1")
+		}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		headers := map[string]string{}
+		for _, name := range []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Policy", fiber.HeaderRetryAfter} {
+			headers[name] = resp.Header.Get(name)
+		}
+		return resp.StatusCode, headers
+	}
+
+	status, headers := get("203.0.113.1", false)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, "2", headers["RateLimit-Limit"])
+	assert.Equal(t, "1", headers["RateLimit-Remaining"])
+	assert.Equal(t, "2;w=60", headers["RateLimit-Policy"])
+
+	status, _ = get("203.0.113.1", false)
+	assert.Equal(t, fiber.StatusOK, status)
+	status, headers = get("203.0.113.1", false)
+	assert.Equal(t, fiber.StatusTooManyRequests, status)
+	assert.Equal(t, "0", headers["RateLimit-Remaining"])
+	assert.NotEmpty(t, headers[fiber.HeaderRetryAfter])
+
+	status, _ = get("203.0.113.2", false)
+	assert.Equal(t, fiber.StatusOK, status, "every client IP has its own budget")
+
+	for i := 0; i < 3; i++ {
+		status, headers = get("10.1.2.3", false)
+		assert.Equal(t, fiber.StatusOK, status, "allowlisted networks are not limited")
+		assert.Empty(t, headers["RateLimit-Limit"])
+
+		status, _ = get("203.0.113.1", true)
+		assert.Equal(t, fiber.StatusOK, status, "allowlisted users are not limited")
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vietgs03/translate/backend/internal/audit"
+	"github.com/vietgs03/translate/backend/internal/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	core, logs := observer.New(zap.InfoLevel)
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(Tracing())
+	app.Use(Logger(zap.New(core)))
+	app.Get("/items/:id", func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		// Locals stay readable through the user context
+		assert.NotEmpty(t, audit.RequestFromContext(ctx).RequestID)
+		logging.FromContext(ctx).Info("handled")
+		return c.SendString("ok")
+	})
+
+	req := httptest.NewRequest("GET", "/items/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set(fiber.HeaderXRequestID, "req-1")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// The server span continues the caller's trace and is named after the route
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET /items/:id", span.Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	assert.Contains(t, span.Attributes(), attribute.String("request.id", "req-1"))
+
+	// Every log line of the request carries the request ID and the trace
+	require.Equal(t, 2, logs.Len())
+	for _, entry := range logs.All() {
+		fields := entry.ContextMap()
+		assert.Equal(t, "req-1", fields["request_id"], entry.Message)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", fields["trace_id"], entry.Message)
+	}
+}
@@ -1,46 +1,243 @@
 package middleware
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/repository"
 )
 
-var validate = validator.New()
+// validate is safe for concurrent use and caches struct metadata, so one
+// instance serves every route
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// Report fields under the name the client sent them by
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"params", "query", "json"} {
+			name := strings.Split(field.Tag.Get(tag), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+	// page_size keeps page sizes within what the repositories serve
+	v.RegisterValidation("page_size", func(fl validator.FieldLevel) bool {
+		n := fl.Field().Int()
+		return n >= 1 && n <= repository.MaxPageSize
+	})
+	return v
+}
+
+// payloadKey stores the validated *T in the locals. Each T has its own
+// key, so a route can validate its path and its body separately.
+type payloadKey[T any] struct{}
+
+// Validate parses a new T for every request and validates it before the
+// handler runs, which reads it with Payload. Fields tagged params are read
+// from the path, fields tagged query from the query string, where a default
+// tag fills in absent values, and all other fields from the JSON body. An
+// empty body leaves them zero for the validate tags to judge.
+func Validate[T any]() fiber.Handler {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	queryFields := taggedFields(t, "query")
+	pathFields := taggedFields(t, "params")
+	// Types made only of query and path fields leave the body to others
+	readBody := t.NumField() > len(queryFields)+len(pathFields)
 
-func ValidateRequest(payload interface{}) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		if err := c.BodyParser(payload); err != nil {
-			return errors.NewValidationError("invalid request body: %v", err)
+		payload := new(T)
+		if readBody && len(c.Body()) > 0 {
+			if err := c.BodyParser(payload); err != nil {
+				return bodyError(err)
+			}
+		}
+
+		v := reflect.ValueOf(payload).Elem()
+		fields := decodeFields(v, queryFields, c.Query)
+		fields = append(fields, decodeFields(v, pathFields, c.Params)...)
+		if len(fields) > 0 {
+			return errors.NewFieldValidationError(fields...)
 		}
 
 		if err := validate.Struct(payload); err != nil {
-			if validationErrors, ok := err.(validator.ValidationErrors); ok {
-				errorMessages := make([]string, len(validationErrors))
-				for i, err := range validationErrors {
-					errorMessages[i] = formatValidationError(err)
-				}
-				return errors.NewValidationError("validation failed: %v", errorMessages)
+			validationErrors, ok := err.(validator.ValidationErrors)
+			if !ok {
+				return errors.NewValidationError("validation failed: %v", err)
+			}
+			fields := make([]errors.FieldError, len(validationErrors))
+			for i, err := range validationErrors {
+				fields[i] = errors.FieldError{Field: err.Field(), Message: formatValidationError(err)}
 			}
-			return errors.NewValidationError("validation failed")
+			return errors.NewFieldValidationError(fields...)
 		}
 
-		c.Locals("validated", payload)
+		c.Locals(payloadKey[T]{}, payload)
 		return c.Next()
 	}
 }
 
+// Payload returns the *T validated for this request. Only handlers mounted
+// behind Validate[T] may call it; anywhere else it panics.
+func Payload[T any](c *fiber.Ctx) *T {
+	payload, ok := c.Locals(payloadKey[T]{}).(*T)
+	if !ok {
+		panic(fmt.Sprintf("middleware: no validated %T payload, mount Validate on the route", *new(T)))
+	}
+	return payload
+}
+
+// taggedField is a struct field filled from the query string or path
+type taggedField struct {
+	index []int
+	name  string
+	def   string
+}
+
+func taggedFields(t reflect.Type, tag string) []taggedField {
+	var fields []taggedField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		fields = append(fields, taggedField{index: field.Index, name: name, def: field.Tag.Get("default")})
+	}
+	return fields
+}
+
+// decodeFields sets the fields from get, overwriting whatever the body put
+// there, and reports the values that don't fit the field type
+func decodeFields(v reflect.Value, fields []taggedField, get func(name string, defaultValue ...string) string) []errors.FieldError {
+	var problems []errors.FieldError
+	for _, f := range fields {
+		field := v.FieldByIndex(f.index)
+		field.Set(reflect.Zero(field.Type()))
+
+		raw := get(f.name)
+		if raw == "" {
+			raw = f.def
+		}
+		if raw == "" {
+			continue
+		}
+		if !setField(field, raw) {
+			problems = append(problems, errors.FieldError{Field: f.name, Message: f.name + " " + describeKind(field.Type())})
+		}
+	}
+	return problems
+}
+
+func setField(field reflect.Value, raw string) bool {
+	switch field.Kind() {
+	case reflect.Ptr:
+		value := reflect.New(field.Type().Elem())
+		if !setField(value.Elem(), raw) {
+			return false
+		}
+		field.Set(value)
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return false
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return false
+		}
+		field.SetUint(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return false
+		}
+		field.SetBool(b)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return false
+		}
+		field.SetFloat(n)
+	default:
+		return false
+	}
+	return true
+}
+
+func describeKind(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "must be an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "must be a non-negative integer"
+	case reflect.Bool:
+		return "must be true or false"
+	case reflect.Float32, reflect.Float64:
+		return "must be a number"
+	case reflect.String:
+		return "must be a string"
+	default:
+		return "is invalid"
+	}
+}
+
+// bodyError names the offending field when the JSON has the wrong type
+func bodyError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return errors.NewFieldValidationError(errors.FieldError{
+			Field:   typeErr.Field,
+			Message: typeErr.Field + " " + describeKind(typeErr.Type),
+		})
+	}
+	return errors.NewValidationError("invalid request body: %v", err)
+}
+
 func formatValidationError(err validator.FieldError) string {
+	isText := err.Kind() == reflect.String
 	switch err.Tag() {
 	case "required":
 		return err.Field() + " is required"
-	case "min":
+	case "min", "gte":
+		if isText {
+			return err.Field() + " must be at least " + err.Param() + " characters long"
+		}
 		return err.Field() + " must be at least " + err.Param()
-	case "max":
+	case "max", "lte":
+		if isText {
+			return err.Field() + " must be at most " + err.Param() + " characters long"
+		}
 		return err.Field() + " must be at most " + err.Param()
 	case "len":
 		return err.Field() + " must be exactly " + err.Param() + " characters long"
+	case "oneof":
+		return err.Field() + " must be one of " + strings.Join(strings.Fields(err.Param()), ", ")
+	case "email":
+		return err.Field() + " must be a valid email address"
+	case "alphanum":
+		return err.Field() + " must contain only letters and digits"
+	case "page_size":
+		return err.Field() + " must be between 1 and " + strconv.Itoa(repository.MaxPageSize)
 	default:
 		return err.Field() + " is invalid"
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/repository"
+	"github.com/vietgs03/translate/backend/internal/types"
+)
+
+type testBody struct {
+	Text  string `json:"text" validate:"required,max=20"`
+	Lang  string `json:"lang" validate:"required,len=2"`
+	Count int    `json:"count" validate:"omitempty,min=1"`
+}
+
+type testQuery struct {
+	Page     int   `query:"page" default:"1" validate:"min=1"`
+	PageSize int   `query:"page_size" default:"10" validate:"page_size"`
+	Active   *bool `query:"active"`
+}
+
+type testPath struct {
+	ID uint `params:"id" validate:"required"`
+}
+
+func TestValidate(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Post("/items/:id", Validate[testPath](), Validate[testBody](), func(c *fiber.Ctx) error {
+		body := Payload[testBody](c)
+		return c.SendString(fmt.Sprintf("%d %s %s", Payload[testPath](c).ID, body.Text, body.Lang))
+	})
+	app.Get("/items", Validate[testQuery](), func(c *fiber.Ctx) error {
+		query := Payload[testQuery](c)
+		return c.SendString(fmt.Sprintf("%d %v", query.Page, query.Active != nil && *query.Active))
+	})
+
+	send := func(method, target, body string) (int, string) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		content, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(content)
+	}
+	fieldErrors := func(content string) []errors.FieldError {
+		var problem types.Problem
+		require.NoError(t, json.Unmarshal([]byte(content), &problem))
+		return problem.Errors
+	}
+
+	status, content := send("POST", "/items/7", `{"text":"hello","lang":"en"}`)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, "7 hello en", content)
+
+	// Every field problem is reported under its JSON name
+	status, content = send("POST", "/items/7", `{"text":"","lang":"eng","count":0}`)
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, []errors.FieldError{
+		{Field: "text", Message: "text is required"},
+		{Field: "lang", Message: "lang must be exactly 2 characters long"},
+	}, fieldErrors(content))
+
+	// An empty body is judged by the validate tags, not rejected by the parser
+	status, content = send("POST", "/items/7", "")
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Len(t, fieldErrors(content), 2)
+
+	status, content = send("POST", "/items/7", `{"text":"hello","lang":"en","count":"two"}`)
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, []errors.FieldError{{Field: "count", Message: "count must be an integer"}}, fieldErrors(content))
+
+	status, content = send("POST", "/items/abc", `{"text":"hello","lang":"en"}`)
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, []errors.FieldError{{Field: "id", Message: "id must be a non-negative integer"}}, fieldErrors(content))
+
+	status, content = send("GET", "/items", "")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, "1 false", content)
+
+	status, content = send("GET", "/items?page=3&active=true", "")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, "3 true", content)
+
+	status, content = send("GET", "/items?page=0&active=maybe", "")
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, []errors.FieldError{{Field: "active", Message: "active must be true or false"}}, fieldErrors(content))
+
+	status, content = send("GET", "/items?page=0", "")
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, []errors.FieldError{{Field: "page", Message: "page must be at least 1"}}, fieldErrors(content))
+
+	status, content = send("GET", fmt.Sprintf("/items?page_size=%d", repository.MaxPageSize+1), "")
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, []errors.FieldError{
+		{Field: "page_size", Message: fmt.Sprintf("page_size must be between 1 and %d", repository.MaxPageSize)},
+	}, fieldErrors(content))
+
+	// Concurrent requests each see their own payload
+	var wg sync.WaitGroup
+	for i := 1; i <= 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			status, content := send("POST", fmt.Sprintf("/items/%d", i), fmt.Sprintf(`{"text":"text %d","lang":"en"}`, i))
+			assert.Equal(t, fiber.StatusOK, status)
+			assert.Equal(t, fmt.Sprintf("%d text %d en", i, i), content)
+		}(i)
+	}
+	wg.Wait()
+}
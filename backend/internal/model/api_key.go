@@ -0,0 +1,68 @@
+package model
+
+import "time"
+
+// API key scopes, from least to most privileged
+const (
+	APIKeyScopeReadOnly  = "read-only"
+	APIKeyScopeTranslate = "translate"
+	APIKeyScopeAdmin     = "admin"
+)
+
+// APIKeyScopePermissions is the most each scope may grant. A key holds the
+// permissions of its scope that its owner's role also holds.
+var APIKeyScopePermissions = map[string][]string{
+	APIKeyScopeReadOnly: {PermissionTranslationRead},
+	APIKeyScopeTranslate: {
+		PermissionTranslationRead,
+		PermissionTranslationCreate,
+		PermissionTranslationUpdate,
+	},
+	APIKeyScopeAdmin: {
+		PermissionTranslationRead,
+		PermissionTranslationCreate,
+		PermissionTranslationUpdate,
+		PermissionTranslationDelete,
+		PermissionTranslationApprove,
+		PermissionTranslationManage,
+		PermissionUserManage,
+		PermissionRoleManage,
+		PermissionCacheManage,
+		PermissionAuditRead,
+	},
+}
+
+// APIKeyScopeAllows reports whether the scope may grant the permission. The
+// empty scope of an interactive session restricts nothing.
+func APIKeyScopeAllows(scope, permission string) bool {
+	if scope == "" {
+		return true
+	}
+	for _, granted := range APIKeyScopePermissions[scope] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey lets machine clients authenticate without an interactive login.
+// The key itself is only shown once at creation; Prefix is kept so users
+// can tell their keys apart.
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"not null;index"`
+	Name       string     `json:"name" gorm:"type:varchar(100);not null"`
+	Prefix     string     `json:"prefix" gorm:"type:varchar(16);not null"`
+	KeyHash    string     `json:"-" gorm:"type:char(64);uniqueIndex;not null"`
+	Scope      string     `json:"scope" gorm:"type:varchar(20);not null"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
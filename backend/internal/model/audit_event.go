@@ -0,0 +1,111 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+const (
+	AuditActionLogin                = "auth.login"
+	AuditActionLoginFailed          = "auth.login_failed"
+	AuditActionLogout               = "auth.logout"
+	AuditActionRefreshReuse         = "auth.refresh_token_reuse"
+	AuditActionPasswordReset        = "auth.password_reset"
+	AuditActionUserRegistered       = "user.registered"
+	AuditActionUserUnlocked         = "user.unlocked"
+	AuditActionUserRoleChanged      = "user.role_changed"
+	AuditActionUserOIDCLinked       = "user.oidc_linked"
+	AuditActionUserDeactivated      = "user.deactivated"
+	AuditActionUserReactivated      = "user.reactivated"
+	AuditActionUserPasswordReset    = "user.password_reset"
+	AuditActionUserDeleted          = "user.deleted"
+	AuditActionTranslationCreated   = "translation.created"
+	AuditActionTranslationUpdated   = "translation.updated"
+	AuditActionTranslationStatus    = "translation.status_changed"
+	AuditActionTranslationDeleted   = "translation.deleted"
+	AuditActionAuditExported        = "audit.exported"
+	AuditActionRoleCreated          = "role.created"
+	AuditActionRoleUpdated          = "role.updated"
+	AuditActionRoleDeleted          = "role.deleted"
+	AuditActionPermissionCreated    = "permission.created"
+	AuditActionAssignmentCreated    = "assignment.created"
+	AuditActionAssignmentDeleted    = "assignment.deleted"
+	AuditActionAPIKeyCreated        = "api_key.created"
+	AuditActionAPIKeyRevoked        = "api_key.revoked"
+	AuditActionOrgMemberAdded       = "organization.member_added"
+	AuditActionOrgMemberRemoved     = "organization.member_removed"
+	AuditActionProjectMemberAdded   = "project.member_added"
+	AuditActionProjectMemberRemoved = "project.member_removed"
+)
+
+const (
+	AuditTargetUser         = "user"
+	AuditTargetTranslation  = "translation"
+	AuditTargetRole         = "role"
+	AuditTargetPermission   = "permission"
+	AuditTargetAssignment   = "translator_assignment"
+	AuditTargetAPIKey       = "api_key"
+	AuditTargetOrganization = "organization"
+	AuditTargetProject      = "project"
+)
+
+// AuditEvent records who did what to which object. Events are append-only;
+// the database rejects updates and deletes.
+type AuditEvent struct {
+	ID         uint64    `json:"id" gorm:"primaryKey"`
+	CreatedAt  time.Time `json:"created_at"`
+	ActorID    *uint     `json:"actor_id"`
+	Actor      string    `json:"actor" gorm:"type:varchar(255);not null;default:''"`
+	Action     string    `json:"action" gorm:"type:varchar(100);not null"`
+	TargetType string    `json:"target_type" gorm:"type:varchar(50);not null;default:''"`
+	TargetID   string    `json:"target_id" gorm:"type:varchar(255);not null;default:''"`
+	IP         string    `json:"ip" gorm:"type:varchar(45);not null;default:''"`
+	RequestID  string    `json:"request_id" gorm:"type:varchar(255);not null;default:''"`
+	Before     JSON      `json:"before,omitempty" swaggertype:"object"`
+	After      JSON      `json:"after,omitempty" swaggertype:"object"`
+}
+
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// JSON is a raw JSON document stored in a JSONB column
+type JSON []byte
+
+func (j JSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	return string(j), nil
+}
+
+func (j *JSON) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*j = nil
+	case []byte:
+		*j = append((*j)[:0], v...)
+	case string:
+		*j = JSON(v)
+	default:
+		return fmt.Errorf("cannot scan %T into JSON", src)
+	}
+	return nil
+}
+
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+func (j *JSON) UnmarshalJSON(data []byte) error {
+	*j = append((*j)[:0], data...)
+	return nil
+}
+
+func (JSON) GormDataType() string {
+	return "jsonb"
+}
@@ -0,0 +1,77 @@
+package model
+
+import "time"
+
+// Organization roles. Owners manage members and projects.
+const (
+	OrganizationRoleOwner  = "owner"
+	OrganizationRoleMember = "member"
+)
+
+// Project roles, from least to most privileged
+const (
+	ProjectRoleViewer     = "viewer"
+	ProjectRoleTranslator = "translator"
+	ProjectRoleMaintainer = "maintainer"
+)
+
+var projectRoleRank = map[string]int{
+	ProjectRoleViewer:     1,
+	ProjectRoleTranslator: 2,
+	ProjectRoleMaintainer: 3,
+}
+
+// ProjectRoleAtLeast reports whether role grants everything min does
+func ProjectRoleAtLeast(role, min string) bool {
+	return projectRoleRank[role] > 0 && projectRoleRank[role] >= projectRoleRank[min]
+}
+
+type Organization struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null"`
+	Slug      string    `json:"slug" gorm:"type:varchar(50);uniqueIndex;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+type OrganizationMember struct {
+	OrganizationID uint      `json:"organization_id" gorm:"primaryKey"`
+	UserID         uint      `json:"user_id" gorm:"primaryKey"`
+	Role           string    `json:"role" gorm:"type:varchar(20);not null"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (OrganizationMember) TableName() string {
+	return "organization_members"
+}
+
+// Project owns its own translations. With UseGlobalTM set, lookups that
+// miss in the project fall back to the shared global translation memory.
+type Project struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	OrganizationID uint      `json:"organization_id" gorm:"not null;index"`
+	Name           string    `json:"name" gorm:"type:varchar(100);not null"`
+	Slug           string    `json:"slug" gorm:"type:varchar(50);not null"`
+	UseGlobalTM    bool      `json:"use_global_tm" gorm:"column:use_global_tm;not null"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (Project) TableName() string {
+	return "projects"
+}
+
+type ProjectMember struct {
+	ProjectID uint      `json:"project_id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"primaryKey"`
+	Role      string    `json:"role" gorm:"type:varchar(20);not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (ProjectMember) TableName() string {
+	return "project_members"
+}
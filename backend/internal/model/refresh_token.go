@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// RefreshToken is one link in a rotation chain. Only the SHA-256 hash of
+// the token is stored; every token of a chain shares the FamilyID so a
+// replayed token can revoke the whole chain.
+type RefreshToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"type:char(64);uniqueIndex;not null"`
+	FamilyID  string     `json:"family_id" gorm:"type:varchar(36);index;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -0,0 +1,48 @@
+package model
+
+// Permissions checked by the API. Roles may be granted any name, these are
+// the ones routes require.
+const (
+	PermissionTranslationRead    = "translation:read"
+	PermissionTranslationCreate  = "translation:create"
+	PermissionTranslationUpdate  = "translation:update"
+	PermissionTranslationDelete  = "translation:delete"
+	PermissionTranslationApprove = "translation:approve"
+	// PermissionTranslationManage bypasses translator assignments
+	PermissionTranslationManage = "translation:manage"
+	PermissionUserManage        = "user:manage"
+	PermissionRoleManage        = "role:manage"
+	PermissionCacheManage       = "cache:manage"
+	PermissionAuditRead         = "audit:read"
+)
+
+// Role is a named set of permissions. A role also has every permission of
+// its parent, recursively.
+type Role struct {
+	Name        string  `json:"name" gorm:"type:varchar(50);primaryKey"`
+	Description string  `json:"description" gorm:"type:varchar(255)"`
+	Parent      *string `json:"parent" gorm:"type:varchar(50)"`
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
+
+type Permission struct {
+	Name        string `json:"name" gorm:"type:varchar(100);primaryKey"`
+	Description string `json:"description" gorm:"type:varchar(255)"`
+}
+
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+// RolePermission grants a permission to a role directly
+type RolePermission struct {
+	Role       string `json:"role" gorm:"type:varchar(50);primaryKey"`
+	Permission string `json:"permission" gorm:"type:varchar(100);primaryKey"`
+}
+
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
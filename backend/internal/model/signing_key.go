@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// SigningKey is an asymmetric key for access tokens. The newest key signs
+// once it has been published in the JWKS for a while; older keys keep
+// verifying until ExpiresAt so tokens signed just before a rotation stay
+// valid.
+type SigningKey struct {
+	ID         string    `json:"kid" gorm:"type:varchar(64);primaryKey"`
+	Algorithm  string    `json:"alg" gorm:"type:varchar(16);not null"`
+	PrivateKey string    `json:"-" gorm:"type:text;not null"` // PKCS #8 PEM, sealed with JWT_KEY_ENCRYPTION_KEY when set
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at" gorm:"not null"`
+}
+
+func (SigningKey) TableName() string {
+	return "signing_keys"
+}
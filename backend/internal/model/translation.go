@@ -6,15 +6,25 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	TranslationStatusPending  = "pending"
+	TranslationStatusApproved = "approved"
+	TranslationStatusRejected = "rejected"
+)
+
 type Translation struct {
 	ID              uint           `json:"id" gorm:"primaryKey"`
+	// ProjectID is nil for entries of the global translation memory
+	ProjectID       *uint          `json:"project_id" gorm:"index"`
 	SourceText      string         `json:"source_text" gorm:"type:text;not null"`
 	TranslatedText  string         `json:"translated_text" gorm:"type:text;not null"`
-	SourceLanguage  string         `json:"source_language" gorm:"type:varchar(10);not null"`
-	TargetLanguage  string         `json:"target_language" gorm:"type:varchar(10);not null"`
+	SourceLanguage  string         `json:"source_language" gorm:"type:varchar(35);not null"`
+	TargetLanguage  string         `json:"target_language" gorm:"type:varchar(35);not null"`
 	Context         string         `json:"context" gorm:"type:text"`
 	Category        string         `json:"category" gorm:"type:varchar(50)"`
-	Votes           int           `json:"votes" gorm:"default:0"`
+	Votes           int           `json:"votes" gorm:"not null;default:0"`
+	Status          string         `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	Provider        string         `json:"provider" gorm:"type:varchar(50)"`
 	CreatedBy       string         `json:"created_by" gorm:"type:varchar(255)"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
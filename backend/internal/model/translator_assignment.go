@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// TranslatorAssignment lets a user edit translations of a language pair
+// and/or category. Empty fields match anything; leads may also edit
+// approved translations in their scope.
+type TranslatorAssignment struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	UserID         uint      `json:"user_id" gorm:"not null;index"`
+	SourceLanguage string    `json:"source_language" gorm:"type:varchar(35);not null;default:''"`
+	TargetLanguage string    `json:"target_language" gorm:"type:varchar(35);not null;default:''"`
+	Category       string    `json:"category" gorm:"type:varchar(50);not null;default:''"`
+	Lead           bool      `json:"lead" gorm:"not null;default:false"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (TranslatorAssignment) TableName() string {
+	return "translator_assignments"
+}
+
+// Covers reports whether the assignment applies to the translation
+func (a TranslatorAssignment) Covers(t *Translation) bool {
+	return (a.SourceLanguage == "" || a.SourceLanguage == t.SourceLanguage) &&
+		(a.TargetLanguage == "" || a.TargetLanguage == t.TargetLanguage) &&
+		(a.Category == "" || a.Category == t.Category)
+}
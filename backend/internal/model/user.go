@@ -8,11 +8,15 @@ import (
 
 type User struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
-	Username  string         `json:"username" gorm:"type:varchar(255);uniqueIndex;not null"`
-	Email     string         `json:"email" gorm:"type:varchar(255);uniqueIndex;not null"`
+	Username  string         `json:"username" gorm:"type:varchar(255);uniqueIndex:idx_users_username_active,where:deleted_at IS NULL;not null"`
+	Email     string         `json:"email" gorm:"type:varchar(255);uniqueIndex:idx_users_email_active,where:deleted_at IS NULL;not null"`
 	Password  string         `json:"-" gorm:"type:varchar(255);not null"` // "-" to exclude from JSON
 	Role      string         `json:"role" gorm:"type:varchar(50);not null;default:'user'"`
 	Active    bool           `json:"active" gorm:"default:true"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	// Set for users provisioned through single sign-on
+	OIDCIssuer  string `json:"-" gorm:"column:oidc_issuer;type:varchar(255)"`
+	OIDCSubject string `json:"-" gorm:"column:oidc_subject;type:varchar(255)"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
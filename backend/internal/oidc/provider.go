@@ -0,0 +1,134 @@
+package oidc
+
+import (
+	"context"
+	"fmt"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/vietgs03/translate/backend/internal/config"
+	"golang.org/x/oauth2"
+)
+
+// Identity is what the application learns about a user from a verified
+// ID token.
+type Identity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+	// Role is the mapped role, empty when no mapping matched
+	Role string
+}
+
+// Provider runs the authorization code flow with PKCE against an OpenID
+// Connect identity provider found through discovery.
+type Provider struct {
+	oauth2      oauth2.Config
+	verifier    *gooidc.IDTokenVerifier
+	issuer      string
+	roleClaim   string
+	roleMapping map[string]string
+	defaultRole string
+}
+
+// NewProvider fetches the discovery document of the issuer. The context
+// is also used for background JWKS refreshes, so it should outlive the
+// provider.
+func NewProvider(ctx context.Context, cfg *config.OIDCConfig) (*Provider, error) {
+	provider, err := gooidc.NewProvider(ctx, cfg.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC provider: %v", err)
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{gooidc.ScopeOpenID, "profile", "email"}
+	}
+
+	return &Provider{
+		oauth2: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       scopes,
+		},
+		verifier:    provider.Verifier(&gooidc.Config{ClientID: cfg.ClientID}),
+		issuer:      cfg.IssuerURL,
+		roleClaim:   cfg.RoleClaim,
+		roleMapping: cfg.RoleMapping,
+		defaultRole: cfg.DefaultRole,
+	}, nil
+}
+
+// DefaultRole is the role of newly provisioned users no mapping matched
+func (p *Provider) DefaultRole() string {
+	return p.defaultRole
+}
+
+// AuthCodeURL is where the browser is sent to log in
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) string {
+	return p.oauth2.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+}
+
+// Exchange redeems an authorization code and verifies the returned ID
+// token: signature against the provider's JWKS, issuer, audience, expiry
+// and the nonce of the login it belongs to.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Identity, error) {
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %v", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("token response has no id_token")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify ID token: %v", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, fmt.Errorf("ID token nonce mismatch")
+	}
+
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to decode ID token claims: %v", err)
+	}
+
+	identity := &Identity{
+		Issuer:  idToken.Issuer,
+		Subject: idToken.Subject,
+		Role:    p.mapRole(claims[p.roleClaim]),
+	}
+	identity.Email, _ = claims["email"].(string)
+	identity.EmailVerified, _ = claims["email_verified"].(bool)
+	identity.Username, _ = claims["preferred_username"].(string)
+
+	return identity, nil
+}
+
+// mapRole returns the mapped role of the first matching claim value
+func (p *Provider) mapRole(claim interface{}) string {
+	var values []string
+	switch v := claim.(type) {
+	case string:
+		values = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	for _, value := range values {
+		if role, ok := p.roleMapping[value]; ok {
+			return role
+		}
+	}
+	return ""
+}
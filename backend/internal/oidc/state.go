@@ -0,0 +1,106 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PendingLogin is the per-login secret state kept between redirecting the
+// browser to the provider and handling its callback.
+type PendingLogin struct {
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	// LinkUserID is set when a logged in user links the identity to their
+	// account rather than logging in with it
+	LinkUserID uint `json:"link_user_id,omitempty"`
+}
+
+// StateStore holds pending logins keyed by the OAuth2 state parameter.
+// Take removes the entry so a callback can't be replayed; it returns nil
+// when the state is unknown or expired.
+type StateStore interface {
+	Save(ctx context.Context, state string, pending PendingLogin, ttl time.Duration) error
+	Take(ctx context.Context, state string) (*PendingLogin, error)
+}
+
+type redisStateStore struct {
+	redis *redis.Client
+}
+
+func NewRedisStateStore(redis *redis.Client) StateStore {
+	return &redisStateStore{redis: redis}
+}
+
+func (s *redisStateStore) Save(ctx context.Context, state string, pending PendingLogin, ttl time.Duration) error {
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+	return s.redis.Set(ctx, "auth:oidc:state:"+state, data, ttl).Err()
+}
+
+func (s *redisStateStore) Take(ctx context.Context, state string) (*PendingLogin, error) {
+	data, err := s.redis.GetDel(ctx, "auth:oidc:state:"+state).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load login state: %v", err)
+	}
+
+	var pending PendingLogin
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, fmt.Errorf("malformed login state: %v", err)
+	}
+	return &pending, nil
+}
+
+// memoryStateStore is the single-process fallback used when no Redis
+// server is configured.
+type memoryStateStore struct {
+	mu      sync.Mutex
+	pending map[string]memoryPendingLogin
+}
+
+type memoryPendingLogin struct {
+	PendingLogin
+	expires time.Time
+}
+
+func NewMemoryStateStore() StateStore {
+	return &memoryStateStore{pending: make(map[string]memoryPendingLogin)}
+}
+
+func (s *memoryStateStore) Save(ctx context.Context, state string, pending PendingLogin, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, entry := range s.pending {
+		if now.After(entry.expires) {
+			delete(s.pending, key)
+		}
+	}
+	s.pending[state] = memoryPendingLogin{PendingLogin: pending, expires: now.Add(ttl)}
+	return nil
+}
+
+func (s *memoryStateStore) Take(ctx context.Context, state string) (*PendingLogin, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.pending[state]
+	if !ok {
+		return nil, nil
+	}
+	delete(s.pending, state)
+	if time.Now().After(entry.expires) {
+		return nil, nil
+	}
+	return &entry.PendingLogin, nil
+}
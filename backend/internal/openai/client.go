@@ -2,16 +2,20 @@ package openai
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/sashabaranov/go-openai"
 	"github.com/vietgs03/translate/backend/internal/config"
 	"github.com/redis/go-redis/v9"
+	"github.com/vietgs03/translate/backend/internal/language"
 	"github.com/vietgs03/translate/backend/internal/service/translator"
 )
 
 var _ translator.Translator = (*Client)(nil) // Verify interface implementation
+var _ translator.Prober = (*Client)(nil)
 
 type Client struct {
 	client      *openai.Client
@@ -28,9 +32,18 @@ func NewClient(cfg *config.OpenAIConfig, redis *redis.Client) *Client {
 	}
 }
 
+func (c *Client) Name() string {
+	return "openai"
+}
+
+// Coverage is unrestricted, OpenAI translates between any languages
+func (c *Client) Coverage() language.Coverage {
+	return language.Coverage{}
+}
+
 func (c *Client) Translate(ctx context.Context, text, sourceLang, targetLang string) (string, error) {
 	if err := c.rateLimiter.Allow(ctx); err != nil {
-		return "", fmt.Errorf("rate limit check failed: %v", err)
+		return "", fmt.Errorf("rate limit check failed: %w", err)
 	}
 
 	prompt := fmt.Sprintf(
@@ -55,16 +68,40 @@ func (c *Client) Translate(ctx context.Context, text, sourceLang, targetLang str
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("failed to get translation from OpenAI: %v", err)
+		return "", fmt.Errorf("failed to get translation from OpenAI: %w", classify(err))
 	}
 
 	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no translation received from OpenAI")
+		return "", fmt.Errorf("no translation received from OpenAI: %w", translator.ErrEmptyTranslation)
 	}
 
 	return resp.Choices[0].Message.Content, nil
 }
 
+// Probe looks up the model used for translating, which uses no tokens and
+// is not counted against the translation rate limit
+func (c *Client) Probe(ctx context.Context) error {
+	if _, err := c.client.GetModel(ctx, openai.GPT3Dot5Turbo); err != nil {
+		return fmt.Errorf("failed to reach OpenAI: %w", classify(err))
+	}
+	return nil
+}
+
+// classify marks API errors worth telling apart with the translator errors
+func classify(err error) error {
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	switch {
+	case apiErr.HTTPStatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %w", translator.ErrRateLimited, err)
+	case apiErr.HTTPStatusCode >= http.StatusInternalServerError:
+		return fmt.Errorf("%w: %w", translator.ErrUnavailable, err)
+	}
+	return err
+}
+
 // Add Close method to satisfy Translator interface
 func (c *Client) Close() error {
 	// OpenAI client doesn't need cleanup
@@ -3,16 +3,23 @@ package openai
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/vietgs03/translate/backend/internal/service/translator"
 )
 
+// RateLimiter is a sliding-window limiter shared through Redis. Without a
+// Redis client it falls back to a window kept in process memory.
 type RateLimiter struct {
 	redis     *redis.Client
 	key       string
 	maxCalls  int
 	duration  time.Duration
+
+	mu    sync.Mutex
+	calls []time.Time
 }
 
 func NewRateLimiter(redis *redis.Client, maxCalls int, duration time.Duration) *RateLimiter {
@@ -25,6 +32,10 @@ func NewRateLimiter(redis *redis.Client, maxCalls int, duration time.Duration) *
 }
 
 func (r *RateLimiter) Allow(ctx context.Context) error {
+	if r.redis == nil {
+		return r.allowLocal()
+	}
+
 	pipe := r.redis.Pipeline()
 	now := time.Now().UnixNano()
 	windowStart := now - r.duration.Nanoseconds()
@@ -52,8 +63,32 @@ func (r *RateLimiter) Allow(ctx context.Context) error {
 	}
 
 	if count >= int64(r.maxCalls) {
-		return fmt.Errorf("rate limit exceeded")
+		return translator.ErrThrottled
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
+
+func (r *RateLimiter) allowLocal() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	windowStart := now.Add(-r.duration)
+
+	// Drop calls that fell out of the window
+	kept := r.calls[:0]
+	for _, call := range r.calls {
+		if call.After(windowStart) {
+			kept = append(kept, call)
+		}
+	}
+	r.calls = kept
+
+	if len(r.calls) >= r.maxCalls {
+		return translator.ErrThrottled
+	}
+
+	r.calls = append(r.calls, now)
+	return nil
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryLimiter is the single-process fallback used when no Redis server is
+// configured. Every replica then counts on its own.
+type memoryLimiter struct {
+	mu        sync.Mutex
+	windows   map[string]window
+	lastSweep time.Time
+}
+
+type window struct {
+	count   int
+	expires time.Time
+}
+
+func NewMemoryLimiter() Limiter {
+	return &memoryLimiter{windows: make(map[string]window)}
+}
+
+func (l *memoryLimiter) Allow(ctx context.Context, key string, limit int, length time.Duration) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > length {
+		l.evictExpired(now)
+		l.lastSweep = now
+	}
+
+	w, ok := l.windows[key]
+	if !ok || !now.Before(w.expires) {
+		w = window{expires: now.Add(length)}
+	}
+	w.count++
+	l.windows[key] = w
+
+	return newResult(w.count, limit, w.expires.Sub(now)), nil
+}
+
+func (l *memoryLimiter) evictExpired(now time.Time) {
+	for key, w := range l.windows {
+		if !now.Before(w.expires) {
+			delete(l.windows, key)
+		}
+	}
+}
+
+// newResult turns the count of a window into a Result
+func newResult(count, limit int, reset time.Duration) Result {
+	remaining := limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	return Result{
+		Allowed:   count <= limit,
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     reset,
+	}
+}
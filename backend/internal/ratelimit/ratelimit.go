@@ -0,0 +1,135 @@
+// Package ratelimit counts requests per client in fixed windows shared
+// between replicas.
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vietgs03/translate/backend/internal/config"
+	"go.uber.org/zap"
+)
+
+// Policy is the budget of one group of routes
+type Policy struct {
+	Window time.Duration
+	// Limit applies to principals whose role has no entry in Tiers
+	Limit int
+	Tiers map[string]int
+}
+
+// LimitFor returns the number of requests a role may make per window
+func (p Policy) LimitFor(role string) int {
+	if limit, ok := p.Tiers[role]; ok {
+		return limit
+	}
+	return p.Limit
+}
+
+// Result describes a counted request
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time left until the window starts over
+	Reset time.Duration
+}
+
+// Limiter counts a request against key and reports whether it stays within
+// limit requests per window
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error)
+}
+
+// APIPolicy is the budget of every authenticated route
+func APIPolicy(cfg *config.RateLimitConfig) Policy {
+	return Policy{
+		Window: time.Duration(cfg.Window) * time.Second,
+		Limit:  cfg.Limit,
+		Tiers:  cfg.Tiers,
+	}
+}
+
+// TranslatePolicy is the separate budget of routes that may call a
+// translation provider
+func TranslatePolicy(cfg *config.RateLimitConfig) Policy {
+	return Policy{
+		Window: time.Duration(cfg.Window) * time.Second,
+		Limit:  cfg.TranslateLimit,
+		Tiers:  cfg.TranslateTiers,
+	}
+}
+
+// AuthPolicy is the budget of each client IP on the public auth routes,
+// which run before anyone is authenticated
+func AuthPolicy(cfg *config.RateLimitConfig) Policy {
+	return Policy{
+		Window: time.Duration(cfg.Window) * time.Second,
+		Limit:  cfg.AuthLimit,
+	}
+}
+
+// Allowlist exempts internal services from rate limits, by client address
+// or by principal
+type Allowlist struct {
+	networks []*net.IPNet
+	users    map[uint]bool
+	apiKeys  map[uint]bool
+}
+
+// NewAllowlist parses entries of the form "10.0.0.0/8", "10.1.2.3",
+// "user:<id>" or "key:<id>". Malformed entries are logged and skipped.
+func NewAllowlist(entries []string) *Allowlist {
+	allowlist := &Allowlist{users: make(map[uint]bool), apiKeys: make(map[uint]bool)}
+	for _, entry := range entries {
+		if kind, value, ok := strings.Cut(entry, ":"); ok && (kind == "user" || kind == "key") {
+			id, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				zap.L().Warn("ignoring rate limit allowlist entry", zap.String("entry", entry), zap.Error(err))
+				continue
+			}
+			if kind == "user" {
+				allowlist.users[uint(id)] = true
+			} else {
+				allowlist.apiKeys[uint(id)] = true
+			}
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			zap.L().Warn("ignoring rate limit allowlist entry", zap.String("entry", entry), zap.Error(err))
+			continue
+		}
+		allowlist.networks = append(allowlist.networks, network)
+	}
+	return allowlist
+}
+
+// Allows reports whether requests from ip, or by the given user or API key,
+// are exempt. Zero IDs never match.
+func (a *Allowlist) Allows(ip string, userID, apiKeyID uint) bool {
+	if (apiKeyID != 0 && a.apiKeys[apiKeyID]) || (userID != 0 && a.users[userID]) {
+		return true
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range a.networks {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewMemoryLimiter()
+
+	for i := 1; i <= 3; i++ {
+		result, err := limiter.Allow(ctx, "api:user:1", 3, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3-i, result.Remaining)
+	}
+
+	result, err := limiter.Allow(ctx, "api:user:1", 3, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.True(t, result.Reset > 0 && result.Reset <= time.Minute)
+
+	result, err = limiter.Allow(ctx, "api:user:2", 3, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed, "every principal has its own budget")
+
+	short := NewMemoryLimiter()
+	_, _ = short.Allow(ctx, "k", 1, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	result, err = short.Allow(ctx, "k", 1, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed, "a new window starts over")
+}
+
+func TestPolicyTiers(t *testing.T) {
+	policy := Policy{Limit: 100, Tiers: map[string]int{"admin": 1000}}
+	assert.Equal(t, 1000, policy.LimitFor("admin"))
+	assert.Equal(t, 100, policy.LimitFor("translator"))
+	assert.Equal(t, 100, policy.LimitFor(""))
+}
+
+func TestAllowlist(t *testing.T) {
+	allowlist := NewAllowlist([]string{"10.0.0.0/8", "192.0.2.7", "::1", "key:5", "user:oops", "not-an-ip"})
+
+	assert.True(t, allowlist.Allows("10.1.2.3", 0, 0))
+	assert.True(t, allowlist.Allows("192.0.2.7", 0, 0))
+	assert.True(t, allowlist.Allows("::1", 0, 0))
+	assert.True(t, allowlist.Allows("203.0.113.1", 9, 5))
+	assert.False(t, allowlist.Allows("192.0.2.8", 9, 0))
+	assert.False(t, allowlist.Allows("203.0.113.1", 0, 6))
+}
@@ -0,0 +1,40 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// countScript increments the counter of a window, starting the window on
+// the first request, and returns the count and the milliseconds left
+var countScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
+
+type redisLimiter struct {
+	redis *redis.Client
+}
+
+func NewRedisLimiter(redis *redis.Client) Limiter {
+	return &redisLimiter{redis: redis}
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	values, err := countScript.Run(ctx, l.redis, []string{"ratelimit:" + key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to count request: %v", err)
+	}
+	return newResult(int(values[0]), limit, time.Duration(values[1])*time.Millisecond), nil
+}
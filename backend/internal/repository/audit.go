@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/vietgs03/translate/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// AuditFilter narrows down audit events. Empty fields match anything.
+type AuditFilter struct {
+	ActorID    *uint
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time
+	Page       int
+	PageSize   int
+}
+
+// AuditRepository stores audit events. It deliberately has no way to
+// change or remove them.
+type AuditRepository interface {
+	Create(ctx context.Context, event *model.AuditEvent) error
+	// List returns one page of matching events, newest first, and the total number of matches
+	List(ctx context.Context, filter AuditFilter) ([]model.AuditEvent, int64, error)
+	// ListAfter returns up to limit matching events with an ID above afterID, oldest first
+	ListAfter(ctx context.Context, filter AuditFilter, afterID uint64, limit int) ([]model.AuditEvent, error)
+}
+
+type auditRepo struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepo{db: db}
+}
+
+func (r *auditRepo) Create(ctx context.Context, event *model.AuditEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *auditRepo) List(ctx context.Context, filter AuditFilter) ([]model.AuditEvent, int64, error) {
+	query := r.applyFilter(r.db.WithContext(ctx).Model(&model.AuditEvent{}), filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	var events []model.AuditEvent
+	err := query.Order("id DESC").
+		Offset((page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&events).Error
+	return events, total, err
+}
+
+func (r *auditRepo) ListAfter(ctx context.Context, filter AuditFilter, afterID uint64, limit int) ([]model.AuditEvent, error) {
+	var events []model.AuditEvent
+	err := r.applyFilter(r.db.WithContext(ctx), filter).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+func (r *auditRepo) applyFilter(query *gorm.DB, filter AuditFilter) *gorm.DB {
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	return query
+}
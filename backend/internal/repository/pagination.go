@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/vietgs03/translate/backend/internal/model"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// SortField is a column listings can be ordered by. Every sort is
+// tie-broken on id so keyset pagination stays stable.
+type SortField string
+
+const (
+	SortCreatedAt SortField = "created_at"
+	SortUpdatedAt SortField = "updated_at"
+	SortVotes     SortField = "votes"
+)
+
+func (f SortField) Valid() bool {
+	switch f {
+	case SortCreatedAt, SortUpdatedAt, SortVotes:
+		return true
+	}
+	return false
+}
+
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+func (o SortOrder) Valid() bool {
+	return o == SortAsc || o == SortDesc
+}
+
+// CursorDirection tells the repository which side of a cursor to read.
+type CursorDirection string
+
+const (
+	CursorNext CursorDirection = "next"
+	CursorPrev CursorDirection = "prev"
+)
+
+// Cursor is a keyset position on (sort column, id). It is handed to clients
+// as an opaque string, see EncodeCursor and DecodeCursor.
+type Cursor struct {
+	Sort      SortField       `json:"s"`
+	Order     SortOrder       `json:"o"`
+	Value     string          `json:"v"`
+	ID        uint            `json:"i"`
+	Direction CursorDirection `json:"d"`
+}
+
+// NewCursor builds a cursor pointing at the given translation.
+func NewCursor(sort SortField, order SortOrder, translation *model.Translation, direction CursorDirection) Cursor {
+	cursor := Cursor{Sort: sort, Order: order, ID: translation.ID, Direction: direction}
+	switch sort {
+	case SortVotes:
+		cursor.Value = strconv.Itoa(translation.Votes)
+	case SortUpdatedAt:
+		cursor.Value = translation.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	default:
+		cursor.Value = translation.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return cursor
+}
+
+// key returns the sort column value in the type the database expects.
+func (c *Cursor) key() (interface{}, error) {
+	switch c.Sort {
+	case SortVotes:
+		return strconv.Atoi(c.Value)
+	case SortCreatedAt, SortUpdatedAt:
+		return time.Parse(time.RFC3339Nano, c.Value)
+	}
+	return nil, fmt.Errorf("unknown sort field %q", c.Sort)
+}
+
+// CountMode selects how the total number of matching rows is computed.
+type CountMode string
+
+const (
+	CountNone      CountMode = "none"
+	CountExact     CountMode = "exact"
+	CountEstimated CountMode = "estimated"
+)
+
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	if cursor.ID == 0 || !cursor.Sort.Valid() || !cursor.Order.Valid() {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	if cursor.Direction != CursorNext && cursor.Direction != CursorPrev {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	if _, err := cursor.key(); err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+
+	return &cursor, nil
+}
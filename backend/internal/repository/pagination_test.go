@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vietgs03/translate/backend/internal/model"
+)
+
+func TestCursor(t *testing.T) {
+	translation := &model.Translation{
+		ID:        42,
+		Votes:     7,
+		CreatedAt: time.Date(2024, 5, 1, 10, 30, 0, 123456000, time.UTC),
+		UpdatedAt: time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		tests := []struct {
+			sort SortField
+			want interface{}
+		}{
+			{SortCreatedAt, translation.CreatedAt},
+			{SortUpdatedAt, translation.UpdatedAt},
+			{SortVotes, 7},
+		}
+
+		for _, tt := range tests {
+			cursor := NewCursor(tt.sort, SortDesc, translation, CursorNext)
+
+			decoded, err := DecodeCursor(EncodeCursor(cursor))
+			assert.NoError(t, err)
+			assert.Equal(t, cursor, *decoded)
+
+			key, err := decoded.key()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, key)
+		}
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		values := []string{
+			"",
+			"not-base64!",
+			EncodeCursor(Cursor{}),
+			EncodeCursor(Cursor{Sort: SortVotes, Order: SortAsc, Value: "1", ID: 1, Direction: "up"}),
+			EncodeCursor(Cursor{Sort: "source_text", Order: SortAsc, Value: "1", ID: 1, Direction: CursorNext}),
+			EncodeCursor(Cursor{Sort: SortVotes, Order: SortAsc, Value: "many", ID: 1, Direction: CursorNext}),
+		}
+
+		for _, value := range values {
+			_, err := DecodeCursor(value)
+			assert.Error(t, err, value)
+		}
+	})
+}
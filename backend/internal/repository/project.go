@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/vietgs03/translate/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProjectRepository interface {
+	// CreateOrganization creates the organization with owner as its first owner
+	CreateOrganization(ctx context.Context, org *model.Organization, owner uint) error
+	GetOrganization(ctx context.Context, id uint) (*model.Organization, error)
+	GetOrganizationBySlug(ctx context.Context, slug string) (*model.Organization, error)
+	ListOrganizationsByUser(ctx context.Context, userID uint) ([]model.Organization, error)
+	GetOrganizationMember(ctx context.Context, orgID, userID uint) (*model.OrganizationMember, error)
+	ListOrganizationMembers(ctx context.Context, orgID uint) ([]model.OrganizationMember, error)
+	SaveOrganizationMember(ctx context.Context, member *model.OrganizationMember) error
+	DeleteOrganizationMember(ctx context.Context, orgID, userID uint) error
+
+	CreateProject(ctx context.Context, project *model.Project) error
+	GetProject(ctx context.Context, id uint) (*model.Project, error)
+	GetProjectBySlug(ctx context.Context, orgID uint, slug string) (*model.Project, error)
+	ListProjects(ctx context.Context, orgID uint) ([]model.Project, error)
+	GetProjectMember(ctx context.Context, projectID, userID uint) (*model.ProjectMember, error)
+	ListProjectMembers(ctx context.Context, projectID uint) ([]model.ProjectMember, error)
+	SaveProjectMember(ctx context.Context, member *model.ProjectMember) error
+	DeleteProjectMember(ctx context.Context, projectID, userID uint) error
+}
+
+type projectRepo struct {
+	db *gorm.DB
+}
+
+func NewProjectRepository(db *gorm.DB) ProjectRepository {
+	return &projectRepo{db: db}
+}
+
+func (r *projectRepo) CreateOrganization(ctx context.Context, org *model.Organization, owner uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.OrganizationMember{
+			OrganizationID: org.ID,
+			UserID:         owner,
+			Role:           model.OrganizationRoleOwner,
+		}).Error
+	})
+}
+
+func (r *projectRepo) GetOrganization(ctx context.Context, id uint) (*model.Organization, error) {
+	var org model.Organization
+	if err := r.db.WithContext(ctx).First(&org, id).Error; err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *projectRepo) GetOrganizationBySlug(ctx context.Context, slug string) (*model.Organization, error) {
+	var org model.Organization
+	if err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&org).Error; err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *projectRepo) ListOrganizationsByUser(ctx context.Context, userID uint) ([]model.Organization, error) {
+	var orgs []model.Organization
+	err := r.db.WithContext(ctx).
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ?", userID).
+		Order("organizations.name").
+		Find(&orgs).Error
+	return orgs, err
+}
+
+func (r *projectRepo) GetOrganizationMember(ctx context.Context, orgID, userID uint) (*model.OrganizationMember, error) {
+	var member model.OrganizationMember
+	err := r.db.WithContext(ctx).Where("organization_id = ? AND user_id = ?", orgID, userID).First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *projectRepo) ListOrganizationMembers(ctx context.Context, orgID uint) ([]model.OrganizationMember, error) {
+	var members []model.OrganizationMember
+	err := r.db.WithContext(ctx).Where("organization_id = ?", orgID).Order("user_id").Find(&members).Error
+	return members, err
+}
+
+func (r *projectRepo) SaveOrganizationMember(ctx context.Context, member *model.OrganizationMember) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "organization_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role"}),
+	}).Create(member).Error
+}
+
+func (r *projectRepo) DeleteOrganizationMember(ctx context.Context, orgID, userID uint) error {
+	return r.db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Delete(&model.OrganizationMember{}).Error
+}
+
+func (r *projectRepo) CreateProject(ctx context.Context, project *model.Project) error {
+	return r.db.WithContext(ctx).Create(project).Error
+}
+
+func (r *projectRepo) GetProject(ctx context.Context, id uint) (*model.Project, error) {
+	var project model.Project
+	if err := r.db.WithContext(ctx).First(&project, id).Error; err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (r *projectRepo) GetProjectBySlug(ctx context.Context, orgID uint, slug string) (*model.Project, error) {
+	var project model.Project
+	err := r.db.WithContext(ctx).Where("organization_id = ? AND slug = ?", orgID, slug).First(&project).Error
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (r *projectRepo) ListProjects(ctx context.Context, orgID uint) ([]model.Project, error) {
+	var projects []model.Project
+	err := r.db.WithContext(ctx).Where("organization_id = ?", orgID).Order("name").Find(&projects).Error
+	return projects, err
+}
+
+func (r *projectRepo) GetProjectMember(ctx context.Context, projectID, userID uint) (*model.ProjectMember, error) {
+	var member model.ProjectMember
+	err := r.db.WithContext(ctx).Where("project_id = ? AND user_id = ?", projectID, userID).First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *projectRepo) ListProjectMembers(ctx context.Context, projectID uint) ([]model.ProjectMember, error) {
+	var members []model.ProjectMember
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).Order("user_id").Find(&members).Error
+	return members, err
+}
+
+func (r *projectRepo) SaveProjectMember(ctx context.Context, member *model.ProjectMember) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role"}),
+	}).Create(member).Error
+}
+
+func (r *projectRepo) DeleteProjectMember(ctx context.Context, projectID, userID uint) error {
+	return r.db.WithContext(ctx).
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		Delete(&model.ProjectMember{}).Error
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/vietgs03/translate/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	ListRoles(ctx context.Context) ([]model.Role, error)
+	GetRole(ctx context.Context, name string) (*model.Role, error)
+	CreateRole(ctx context.Context, role *model.Role, permissions []string) error
+	// UpdateRole saves the role and replaces its direct permissions
+	UpdateRole(ctx context.Context, role *model.Role, permissions []string) error
+	DeleteRole(ctx context.Context, name string) error
+	ListPermissions(ctx context.Context) ([]model.Permission, error)
+	CreatePermission(ctx context.Context, permission *model.Permission) error
+	ListRolePermissions(ctx context.Context) ([]model.RolePermission, error)
+}
+
+type roleRepo struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepo{db: db}
+}
+
+func (r *roleRepo) ListRoles(ctx context.Context) ([]model.Role, error) {
+	var roles []model.Role
+	err := r.db.WithContext(ctx).Order("name").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepo) GetRole(ctx context.Context, name string) (*model.Role, error) {
+	var role model.Role
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepo) CreateRole(ctx context.Context, role *model.Role, permissions []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(role).Error; err != nil {
+			return err
+		}
+		return grantPermissions(tx, role.Name, permissions)
+	})
+}
+
+func (r *roleRepo) UpdateRole(ctx context.Context, role *model.Role, permissions []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(role).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role = ?", role.Name).Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return grantPermissions(tx, role.Name, permissions)
+	})
+}
+
+func (r *roleRepo) DeleteRole(ctx context.Context, name string) error {
+	return r.db.WithContext(ctx).Where("name = ?", name).Delete(&model.Role{}).Error
+}
+
+func (r *roleRepo) ListPermissions(ctx context.Context) ([]model.Permission, error) {
+	var permissions []model.Permission
+	err := r.db.WithContext(ctx).Order("name").Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *roleRepo) CreatePermission(ctx context.Context, permission *model.Permission) error {
+	return r.db.WithContext(ctx).Create(permission).Error
+}
+
+func (r *roleRepo) ListRolePermissions(ctx context.Context) ([]model.RolePermission, error) {
+	var grants []model.RolePermission
+	err := r.db.WithContext(ctx).Find(&grants).Error
+	return grants, err
+}
+
+func grantPermissions(tx *gorm.DB, role string, permissions []string) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+	grants := make([]model.RolePermission, len(permissions))
+	for i, permission := range permissions {
+		grants[i] = model.RolePermission{Role: role, Permission: permission}
+	}
+	return tx.Create(&grants).Error
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/vietgs03/translate/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type SigningKeyRepository interface {
+	// List returns unexpired keys, newest first
+	List(ctx context.Context) ([]model.SigningKey, error)
+	// Rotate stores key if due still holds for the unexpired keys. An
+	// advisory lock serializes replicas rotating at the same time.
+	Rotate(ctx context.Context, key *model.SigningKey, due func(stored []model.SigningKey) bool) (bool, error)
+	DeleteExpired(ctx context.Context) error
+}
+
+// signingKeyRotationLock is the advisory lock id held while rotating
+const signingKeyRotationLock = 0x6a776b73 // "jwks"
+
+type signingKeyRepo struct {
+	db *gorm.DB
+}
+
+func NewSigningKeyRepository(db *gorm.DB) SigningKeyRepository {
+	return &signingKeyRepo{db: db}
+}
+
+func (r *signingKeyRepo) List(ctx context.Context) ([]model.SigningKey, error) {
+	return listSigningKeys(r.db.WithContext(ctx))
+}
+
+func (r *signingKeyRepo) Rotate(ctx context.Context, key *model.SigningKey, due func([]model.SigningKey) bool) (bool, error) {
+	rotated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", signingKeyRotationLock).Error; err != nil {
+			return err
+		}
+		keys, err := listSigningKeys(tx)
+		if err != nil {
+			return err
+		}
+		if !due(keys) {
+			return nil
+		}
+		if err := tx.Create(key).Error; err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	return rotated, err
+}
+
+func listSigningKeys(db *gorm.DB) ([]model.SigningKey, error) {
+	var keys []model.SigningKey
+	err := db.Where("expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		Find(&keys).Error
+	return keys, err
+}
+
+func (r *signingKeyRepo) DeleteExpired(ctx context.Context) error {
+	return r.db.WithContext(ctx).Where("expires_at <= ?", time.Now()).Delete(&model.SigningKey{}).Error
+}
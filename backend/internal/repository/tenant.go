@@ -0,0 +1,37 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Tenant selects the project a query runs in. The zero Tenant is the
+// global translation memory, so a query that forgets to set one can never
+// see project data.
+type Tenant struct {
+	ProjectID uint
+}
+
+// GlobalTenant is the shared translation memory outside any project
+var GlobalTenant = Tenant{}
+
+func ProjectTenant(projectID uint) Tenant {
+	return Tenant{ProjectID: projectID}
+}
+
+func (t Tenant) IsGlobal() bool {
+	return t.ProjectID == 0
+}
+
+// projectID is the value stored in translations.project_id
+func (t Tenant) projectID() *uint {
+	if t.IsGlobal() {
+		return nil
+	}
+	id := t.ProjectID
+	return &id
+}
+
+func (t Tenant) scope(db *gorm.DB) *gorm.DB {
+	if t.IsGlobal() {
+		return db.Where("project_id IS NULL")
+	}
+	return db.Where("project_id = ?", t.ProjectID)
+}
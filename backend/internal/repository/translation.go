@@ -2,23 +2,44 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/vietgs03/translate/backend/internal/model"
 )
 
+// TranslationRepository only ever reads and writes the rows of one tenant;
+// List, Count and EstimateCount take it from TranslationFilter.Tenant.
 type TranslationRepository interface {
-	Create(ctx context.Context, translation *model.Translation) error
-	GetByID(ctx context.Context, id uint) (*model.Translation, error)
-	Update(ctx context.Context, translation *model.Translation) error
-	Delete(ctx context.Context, id uint) error
+	Create(ctx context.Context, tenant Tenant, translation *model.Translation) error
+	GetByID(ctx context.Context, tenant Tenant, id uint) (*model.Translation, error)
+	// GetByIDsAcrossTenants ignores tenants. It is meant for maintenance jobs
+	// such as cache warming, never for serving requests.
+	GetByIDsAcrossTenants(ctx context.Context, ids []uint) ([]model.Translation, error)
+	Update(ctx context.Context, tenant Tenant, translation *model.Translation) error
+	Delete(ctx context.Context, tenant Tenant, id uint) error
 	List(ctx context.Context, filter TranslationFilter) ([]model.Translation, error)
+	Count(ctx context.Context, filter TranslationFilter) (int64, error)
+	EstimateCount(ctx context.Context, filter TranslationFilter) (int64, error)
 }
 
 type TranslationFilter struct {
+	Tenant         Tenant
 	SourceText     string
 	SourceLanguage string
 	TargetLanguage string
 	Category       string
+	CreatedBy      string
+	Status         string
+	Provider       string
+	MinVotes       *int
+	CreatedAfter   *time.Time
+	CreatedBefore  *time.Time
+	UpdatedAfter   *time.Time
+	UpdatedBefore  *time.Time
+	SortBy         SortField
+	SortOrder      SortOrder
 	Page          int
 	PageSize      int
-} 
\ No newline at end of file
+	// Cursor positions keyset pagination; it is only used when Page is 0.
+	Cursor        *Cursor
+}
\ No newline at end of file
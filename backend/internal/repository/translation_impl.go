@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/vietgs03/translate/backend/internal/model"
@@ -16,13 +17,14 @@ func NewTranslationRepository(db *gorm.DB) TranslationRepository {
 	return &translationRepo{db: db}
 }
 
-func (r *translationRepo) Create(ctx context.Context, translation *model.Translation) error {
+func (r *translationRepo) Create(ctx context.Context, tenant Tenant, translation *model.Translation) error {
+	translation.ProjectID = tenant.projectID()
 	return r.db.WithContext(ctx).Create(translation).Error
 }
 
-func (r *translationRepo) GetByID(ctx context.Context, id uint) (*model.Translation, error) {
+func (r *translationRepo) GetByID(ctx context.Context, tenant Tenant, id uint) (*model.Translation, error) {
 	var translation model.Translation
-	if err := r.db.WithContext(ctx).First(&translation, id).Error; err != nil {
+	if err := tenant.scope(r.db.WithContext(ctx)).First(&translation, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("translation not found")
 		}
@@ -31,18 +33,136 @@ func (r *translationRepo) GetByID(ctx context.Context, id uint) (*model.Translat
 	return &translation, nil
 }
 
-func (r *translationRepo) Update(ctx context.Context, translation *model.Translation) error {
-	return r.db.WithContext(ctx).Save(translation).Error
+func (r *translationRepo) GetByIDsAcrossTenants(ctx context.Context, ids []uint) ([]model.Translation, error) {
+	var translations []model.Translation
+	if len(ids) == 0 {
+		return translations, nil
+	}
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	return translations, nil
 }
 
-func (r *translationRepo) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&model.Translation{}, id).Error
+func (r *translationRepo) Update(ctx context.Context, tenant Tenant, translation *model.Translation) error {
+	// Rows never move between tenants
+	translation.ProjectID = tenant.projectID()
+	result := tenant.scope(r.db.WithContext(ctx)).Model(translation).Select("*").Omit("id", "created_at").Updates(translation)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("translation not found")
+	}
+	return nil
+}
+
+func (r *translationRepo) Delete(ctx context.Context, tenant Tenant, id uint) error {
+	return tenant.scope(r.db.WithContext(ctx)).Delete(&model.Translation{}, id).Error
 }
 
 func (r *translationRepo) List(ctx context.Context, filter TranslationFilter) ([]model.Translation, error) {
 	var translations []model.Translation
-	query := r.db.WithContext(ctx)
+	query := r.applyFilter(r.db.WithContext(ctx), filter)
+
+	pageSize := filter.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
 
+	sortBy, order := filter.SortBy, filter.SortOrder
+	if !sortBy.Valid() {
+		sortBy = SortCreatedAt
+	}
+	if !order.Valid() {
+		order = SortDesc
+	}
+
+	// Offset pagination, kept for clients that still send ?page=
+	if filter.Page > 0 {
+		offset := (filter.Page - 1) * pageSize
+		query = query.Order(orderClause(sortBy, order)).Offset(offset).Limit(pageSize)
+		if err := query.Find(&translations).Error; err != nil {
+			return nil, err
+		}
+		return translations, nil
+	}
+
+	// Keyset pagination on (sort column, id). Reading backwards flips the
+	// comparison and the order, and the rows are reversed afterwards.
+	reverse := filter.Cursor != nil && filter.Cursor.Direction == CursorPrev
+	scanOrder := order
+	if reverse {
+		scanOrder = SortAsc
+		if order == SortAsc {
+			scanOrder = SortDesc
+		}
+	}
+
+	if filter.Cursor != nil {
+		key, err := filter.Cursor.key()
+		if err != nil {
+			return nil, err
+		}
+		op := "<"
+		if scanOrder == SortAsc {
+			op = ">"
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", sortBy, op), key, filter.Cursor.ID)
+	}
+
+	if err := query.Order(orderClause(sortBy, scanOrder)).Limit(pageSize).Find(&translations).Error; err != nil {
+		return nil, err
+	}
+
+	if reverse {
+		for i, j := 0, len(translations)-1; i < j; i, j = i+1, j-1 {
+			translations[i], translations[j] = translations[j], translations[i]
+		}
+	}
+
+	return translations, nil
+}
+
+func (r *translationRepo) Count(ctx context.Context, filter TranslationFilter) (int64, error) {
+	var total int64
+	query := r.applyFilter(r.db.WithContext(ctx).Model(&model.Translation{}), filter)
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// EstimateCount asks the query planner for its row estimate instead of
+// scanning the table, which keeps large listings cheap.
+func (r *translationRepo) EstimateCount(ctx context.Context, filter TranslationFilter) (int64, error) {
+	stmt := r.applyFilter(r.db.Session(&gorm.Session{DryRun: true}).Model(&model.Translation{}), filter).
+		Select("id").
+		Find(&[]model.Translation{}).
+		Statement
+
+	var plan string
+	if err := r.db.WithContext(ctx).Raw("EXPLAIN (FORMAT JSON) "+stmt.SQL.String(), stmt.Vars...).Row().Scan(&plan); err != nil {
+		return 0, err
+	}
+
+	var explained []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(plan), &explained); err != nil {
+		return 0, fmt.Errorf("failed to parse query plan: %v", err)
+	}
+	if len(explained) == 0 {
+		return 0, fmt.Errorf("empty query plan")
+	}
+
+	return int64(explained[0].Plan.Rows), nil
+}
+
+func (r *translationRepo) applyFilter(query *gorm.DB, filter TranslationFilter) *gorm.DB {
+	query = filter.Tenant.scope(query)
 	if filter.SourceText != "" {
 		query = query.Where("source_text = ?", filter.SourceText)
 	}
@@ -55,16 +175,35 @@ func (r *translationRepo) List(ctx context.Context, filter TranslationFilter) ([
 	if filter.Category != "" {
 		query = query.Where("category = ?", filter.Category)
 	}
-
-	// Add pagination
-	if filter.Page > 0 && filter.PageSize > 0 {
-		offset := (filter.Page - 1) * filter.PageSize
-		query = query.Offset(offset).Limit(filter.PageSize)
+	if filter.CreatedBy != "" {
+		query = query.Where("created_by = ?", filter.CreatedBy)
 	}
-
-	if err := query.Find(&translations).Error; err != nil {
-		return nil, err
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Provider != "" {
+		query = query.Where("provider = ?", filter.Provider)
+	}
+	if filter.MinVotes != nil {
+		query = query.Where("votes >= ?", *filter.MinVotes)
 	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *filter.CreatedBefore)
+	}
+	if filter.UpdatedAfter != nil {
+		query = query.Where("updated_at >= ?", *filter.UpdatedAfter)
+	}
+	if filter.UpdatedBefore != nil {
+		query = query.Where("updated_at < ?", *filter.UpdatedBefore)
+	}
+	return query
+}
 
-	return translations, nil
-} 
\ No newline at end of file
+// orderClause only ever sees validated SortField/SortOrder values, so
+// interpolating them into the SQL is safe.
+func orderClause(sortBy SortField, order SortOrder) string {
+	return fmt.Sprintf("%s %s, id %s", sortBy, order, order)
+}
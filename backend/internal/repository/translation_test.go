@@ -24,7 +24,7 @@ func TestTranslationRepository(t *testing.T) {
 			Category:       "greeting",
 		}
 
-		err := repo.Create(context.Background(), translation)
+		err := repo.Create(context.Background(), GlobalTenant, translation)
 		assert.NoError(t, err)
 		assert.NotZero(t, translation.ID)
 	})
@@ -36,10 +36,10 @@ func TestTranslationRepository(t *testing.T) {
 			SourceLanguage: "en",
 			TargetLanguage: "vi",
 		}
-		err := repo.Create(context.Background(), translation)
+		err := repo.Create(context.Background(), GlobalTenant, translation)
 		assert.NoError(t, err)
 
-		found, err := repo.GetByID(context.Background(), translation.ID)
+		found, err := repo.GetByID(context.Background(), GlobalTenant, translation.ID)
 		assert.NoError(t, err)
 		assert.Equal(t, translation.SourceText, found.SourceText)
 	})
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/vietgs03/translate/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type TranslatorAssignmentRepository interface {
+	Create(ctx context.Context, assignment *model.TranslatorAssignment) error
+	GetByID(ctx context.Context, id uint) (*model.TranslatorAssignment, error)
+	ListByUser(ctx context.Context, userID uint) ([]model.TranslatorAssignment, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+type translatorAssignmentRepo struct {
+	db *gorm.DB
+}
+
+func NewTranslatorAssignmentRepository(db *gorm.DB) TranslatorAssignmentRepository {
+	return &translatorAssignmentRepo{db: db}
+}
+
+func (r *translatorAssignmentRepo) Create(ctx context.Context, assignment *model.TranslatorAssignment) error {
+	return r.db.WithContext(ctx).Create(assignment).Error
+}
+
+func (r *translatorAssignmentRepo) GetByID(ctx context.Context, id uint) (*model.TranslatorAssignment, error) {
+	var assignment model.TranslatorAssignment
+	if err := r.db.WithContext(ctx).First(&assignment, id).Error; err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (r *translatorAssignmentRepo) ListByUser(ctx context.Context, userID uint) ([]model.TranslatorAssignment, error) {
+	var assignments []model.TranslatorAssignment
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *translatorAssignmentRepo) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.TranslatorAssignment{}, id).Error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"strings"
 
 	"github.com/vietgs03/translate/backend/internal/model"
 	"gorm.io/gorm"
@@ -12,10 +13,27 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id uint) (*model.User, error)
 	GetByUsername(ctx context.Context, username string) (*model.User, error)
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
+	GetByOIDCSubject(ctx context.Context, issuer, subject string) (*model.User, error)
 	Update(ctx context.Context, user *model.User) error
+	CountByRole(ctx context.Context, role string) (int64, error)
+	// List returns one page of users matching filter and the total number of matches
+	List(ctx context.Context, filter UserFilter) ([]model.User, int64, error)
 	Delete(ctx context.Context, id uint) error
 }
 
+// UserFilter narrows down a user listing. Query matches a substring of the
+// username or email.
+type UserFilter struct {
+	Query    string
+	Role     string
+	Active   *bool
+	Page     int
+	PageSize int
+}
+
+// likeEscaper makes wildcards in a search term match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type userRepo struct {
 	db *gorm.DB
 }
@@ -53,10 +71,54 @@ func (r *userRepo) GetByEmail(ctx context.Context, email string) (*model.User, e
 	return &user, nil
 }
 
+func (r *userRepo) GetByOIDCSubject(ctx context.Context, issuer, subject string) (*model.User, error) {
+	var user model.User
+	if err := r.db.WithContext(ctx).Where("oidc_issuer = ? AND oidc_subject = ?", issuer, subject).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepo) Update(ctx context.Context, user *model.User) error {
 	return r.db.WithContext(ctx).Save(user).Error
 }
 
+func (r *userRepo) CountByRole(ctx context.Context, role string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.User{}).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+
+func (r *userRepo) List(ctx context.Context, filter UserFilter) ([]model.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.User{})
+	if filter.Query != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Query) + "%"
+		query = query.Where("username ILIKE ? OR email ILIKE ?", pattern, pattern)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Active != nil {
+		query = query.Where("active = ?", *filter.Active)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	var users []model.User
+	err := query.Order("id ASC").
+		Offset((page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&users).Error
+	return users, total, err
+}
+
 func (r *userRepo) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&model.User{}, id).Error
 } 
\ No newline at end of file
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/vietgs03/translate/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type UserTokenRepository interface {
+	Create(ctx context.Context, token *model.UserToken) error
+	GetByHash(ctx context.Context, purpose, hash string) (*model.UserToken, error)
+	// MarkUsed spends an unused token and reports whether this call won
+	MarkUsed(ctx context.Context, id uint) (bool, error)
+	// InvalidateForUser spends every open token of a purpose, so only the
+	// most recently mailed one works
+	InvalidateForUser(ctx context.Context, userID uint, purpose string) error
+}
+
+type userTokenRepo struct {
+	db *gorm.DB
+}
+
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepo{db: db}
+}
+
+func (r *userTokenRepo) Create(ctx context.Context, token *model.UserToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *userTokenRepo) GetByHash(ctx context.Context, purpose, hash string) (*model.UserToken, error) {
+	var token model.UserToken
+	if err := r.db.WithContext(ctx).Where("purpose = ? AND token_hash = ?", purpose, hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *userTokenRepo) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *userTokenRepo) InvalidateForUser(ctx context.Context, userID uint, purpose string) error {
+	return r.db.WithContext(ctx).
+		Model(&model.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/logging"
+	"github.com/vietgs03/translate/backend/internal/model"
+	"github.com/vietgs03/translate/backend/internal/repository"
+	"github.com/vietgs03/translate/backend/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	apiKeyPrefix = "tk_"
+	// lastUsedResolution limits last_used_at writes to one per key per minute
+	lastUsedResolution = time.Minute
+)
+
+type APIKeyService interface {
+	Create(ctx context.Context, principal *types.JWTClaims, input CreateAPIKeyInput) (*CreatedAPIKey, error)
+	List(ctx context.Context, principal *types.JWTClaims) ([]model.APIKey, error)
+	UpdateLabel(ctx context.Context, principal *types.JWTClaims, id uint, input UpdateAPIKeyInput) (*model.APIKey, error)
+	Revoke(ctx context.Context, principal *types.JWTClaims, id uint) error
+	// Authenticate resolves a raw key to the principal it acts as
+	Authenticate(ctx context.Context, key string) (*types.JWTClaims, error)
+}
+
+type apiKeyService struct {
+	repo     repository.APIKeyRepository
+	userRepo repository.UserRepository
+	rbac     RBACService
+	audit    AuditService
+}
+
+func NewAPIKeyService(repo repository.APIKeyRepository, userRepo repository.UserRepository, rbac RBACService, auditService AuditService) APIKeyService {
+	return &apiKeyService{
+		repo:     repo,
+		userRepo: userRepo,
+		rbac:     rbac,
+		audit:    auditService,
+	}
+}
+
+type CreateAPIKeyInput struct {
+	Name          string `json:"name" validate:"required,max=100"`
+	Scope         string `json:"scope" validate:"required,oneof=read-only translate admin"`
+	ExpiresInDays int    `json:"expires_in_days" validate:"omitempty,min=1,max=3650"`
+}
+
+type UpdateAPIKeyInput struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// CreatedAPIKey carries the plain key, which is never retrievable again
+type CreatedAPIKey struct {
+	model.APIKey
+	Key string `json:"key" example:"tk_q3Jd9c0b7x..."`
+}
+
+func (s *apiKeyService) Create(ctx context.Context, principal *types.JWTClaims, input CreateAPIKeyInput) (*CreatedAPIKey, error) {
+	if err := requireInteractive(principal); err != nil {
+		return nil, err
+	}
+	if input.Scope == model.APIKeyScopeAdmin {
+		widens, err := s.widensScope(ctx, principal, model.APIKeyScopeAdmin, model.APIKeyScopeTranslate)
+		if err != nil {
+			return nil, err
+		}
+		if !widens {
+			return nil, errors.NewForbiddenError("your role holds none of the permissions the admin scope adds")
+		}
+	}
+
+	secret, err := generateOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %v", err)
+	}
+	key := apiKeyPrefix + secret
+
+	apiKey := model.APIKey{
+		UserID:  principal.UserID,
+		Name:    input.Name,
+		Prefix:  key[:len(apiKeyPrefix)+8],
+		KeyHash: hashToken(key),
+		Scope:   input.Scope,
+	}
+	if input.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, input.ExpiresInDays)
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	if err := s.repo.Create(ctx, &apiKey); err != nil {
+		return nil, errors.NewDatabaseError("failed to create API key: %v", err)
+	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID:    &principal.UserID,
+		Actor:      principal.Username,
+		Action:     model.AuditActionAPIKeyCreated,
+		TargetType: model.AuditTargetAPIKey,
+		TargetID:   strconv.FormatUint(uint64(apiKey.ID), 10),
+		After:      apiKey,
+	})
+
+	return &CreatedAPIKey{APIKey: apiKey, Key: key}, nil
+}
+
+func (s *apiKeyService) List(ctx context.Context, principal *types.JWTClaims) ([]model.APIKey, error) {
+	keys, err := s.repo.ListByUser(ctx, principal.UserID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("failed to list API keys: %v", err)
+	}
+	return keys, nil
+}
+
+func (s *apiKeyService) UpdateLabel(ctx context.Context, principal *types.JWTClaims, id uint, input UpdateAPIKeyInput) (*model.APIKey, error) {
+	if err := requireInteractive(principal); err != nil {
+		return nil, err
+	}
+
+	apiKey, err := s.getOwned(ctx, principal, id)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey.Name = input.Name
+	if err := s.repo.Update(ctx, apiKey); err != nil {
+		return nil, errors.NewDatabaseError("failed to update API key: %v", err)
+	}
+	return apiKey, nil
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, principal *types.JWTClaims, id uint) error {
+	apiKey, err := s.getOwned(ctx, principal, id)
+	if err != nil {
+		return err
+	}
+	// A key may revoke itself, e.g. from a pipeline that detected a leak
+	if principal.APIKeyID != 0 && principal.APIKeyID != apiKey.ID {
+		return errors.NewForbiddenError("API keys can only revoke themselves")
+	}
+	if apiKey.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	apiKey.RevokedAt = &now
+	if err := s.repo.Update(ctx, apiKey); err != nil {
+		return errors.NewDatabaseError("failed to revoke API key: %v", err)
+	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID:    &principal.UserID,
+		Actor:      principal.Username,
+		Action:     model.AuditActionAPIKeyRevoked,
+		TargetType: model.AuditTargetAPIKey,
+		TargetID:   strconv.FormatUint(uint64(apiKey.ID), 10),
+		After:      apiKey,
+	})
+	return nil
+}
+
+func (s *apiKeyService) Authenticate(ctx context.Context, key string) (*types.JWTClaims, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, errors.NewUnauthorizedError("invalid API key")
+	}
+
+	apiKey, err := s.repo.GetByHash(ctx, hashToken(key))
+	if err != nil {
+		return nil, errors.NewUnauthorizedError("invalid API key")
+	}
+
+	now := time.Now()
+	if apiKey.RevokedAt != nil {
+		return nil, errors.NewUnauthorizedError("API key has been revoked")
+	}
+	if apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt) {
+		return nil, errors.NewUnauthorizedError("API key expired")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, apiKey.UserID)
+	if err != nil || !user.Active {
+		return nil, errors.NewUnauthorizedError("invalid API key")
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > lastUsedResolution {
+		if err := s.repo.TouchLastUsed(ctx, apiKey.ID, now); err != nil {
+			logging.FromContext(ctx).Warn("failed to record use of API key", zap.Uint("api_key_id", apiKey.ID), zap.Error(err))
+		}
+	}
+
+	return &types.JWTClaims{
+		UserID:   user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+		APIKeyID: apiKey.ID,
+		Scope:    apiKey.Scope,
+	}, nil
+}
+
+func (s *apiKeyService) getOwned(ctx context.Context, principal *types.JWTClaims, id uint) (*model.APIKey, error) {
+	apiKey, err := s.repo.GetByID(ctx, id)
+	if err != nil || apiKey.UserID != principal.UserID {
+		return nil, errors.NewNotFoundError("API key not found")
+	}
+	return apiKey, nil
+}
+
+// widensScope reports whether the principal holds any permission that
+// scope grants and narrower does not
+func (s *apiKeyService) widensScope(ctx context.Context, principal *types.JWTClaims, scope, narrower string) (bool, error) {
+	for _, permission := range model.APIKeyScopePermissions[scope] {
+		if model.APIKeyScopeAllows(narrower, permission) {
+			continue
+		}
+		held, err := s.rbac.Allows(ctx, principal, permission)
+		if err != nil || held {
+			return held, err
+		}
+	}
+	return false, nil
+}
+
+// requireInteractive keeps keys from minting or relabelling other keys
+func requireInteractive(principal *types.JWTClaims) error {
+	if principal.APIKeyID != 0 {
+		return errors.NewForbiddenError("API keys cannot manage API keys")
+	}
+	return nil
+}
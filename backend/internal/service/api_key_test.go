@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vietgs03/translate/backend/internal/model"
+	"github.com/vietgs03/translate/backend/internal/repository"
+	"github.com/vietgs03/translate/backend/internal/types"
+)
+
+// memoryAPIKeyRepo is a minimal in-memory APIKeyRepository
+type memoryAPIKeyRepo struct {
+	mu     sync.Mutex
+	nextID uint
+	keys   map[uint]model.APIKey
+}
+
+func newMemoryAPIKeyRepo() *memoryAPIKeyRepo {
+	return &memoryAPIKeyRepo{keys: make(map[uint]model.APIKey)}
+}
+
+func (r *memoryAPIKeyRepo) Create(ctx context.Context, key *model.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	key.ID = r.nextID
+	r.keys[key.ID] = *key
+	return nil
+}
+
+func (r *memoryAPIKeyRepo) GetByID(ctx context.Context, id uint) (*model.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("API key not found")
+	}
+	return &key, nil
+}
+
+func (r *memoryAPIKeyRepo) GetByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range r.keys {
+		if key.KeyHash == hash {
+			return &key, nil
+		}
+	}
+	return nil, fmt.Errorf("API key not found")
+}
+
+func (r *memoryAPIKeyRepo) ListByUser(ctx context.Context, userID uint) ([]model.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var keys []model.APIKey
+	for _, key := range r.keys {
+		if key.UserID == userID {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *memoryAPIKeyRepo) Update(ctx context.Context, key *model.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys[key.ID] = *key
+	return nil
+}
+
+func (r *memoryAPIKeyRepo) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := r.keys[id]
+	key.LastUsedAt = &at
+	r.keys[id] = key
+	return nil
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	ctx := context.Background()
+	users := newMemoryUserRepo()
+	admin := &model.User{Username: "root", Email: "root@example.com", Role: "admin", Active: true}
+	require.NoError(t, users.Create(ctx, admin))
+	trail := NewAuditService(newMemoryAuditRepo())
+	rbac := NewRBACService(newSeededRoleRepo(), users, newMemoryAssignmentRepo(), trail)
+	svc := NewAPIKeyService(newMemoryAPIKeyRepo(), users, rbac, trail)
+	session := &types.JWTClaims{UserID: admin.ID, Username: admin.Username, Role: admin.Role}
+
+	created, err := svc.Create(ctx, session, CreateAPIKeyInput{Name: "ci", Scope: model.APIKeyScopeTranslate})
+	require.NoError(t, err)
+	assert.True(t, len(created.Key) > len(created.Prefix))
+
+	principal, err := svc.Authenticate(ctx, created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, admin.ID, principal.UserID)
+	assert.Equal(t, model.APIKeyScopeTranslate, principal.Scope)
+	for permission, want := range map[string]bool{
+		model.PermissionTranslationUpdate: true,
+		model.PermissionTranslationManage: false,
+		model.PermissionUserManage:        false,
+	} {
+		held, err := rbac.Allows(ctx, principal, permission)
+		require.NoError(t, err)
+		assert.Equal(t, want, held, "translate scope caps the admin role at %s", permission)
+	}
+	assert.Equal(t, created.ID, principal.APIKeyID)
+
+	_, err = svc.Create(ctx, principal, CreateAPIKeyInput{Name: "minted", Scope: model.APIKeyScopeAdmin})
+	assert.Error(t, err, "keys must not mint keys")
+
+	require.NoError(t, svc.Revoke(ctx, session, created.ID))
+	_, err = svc.Authenticate(ctx, created.Key)
+	assert.Error(t, err)
+
+	for _, action := range []string{model.AuditActionAPIKeyCreated, model.AuditActionAPIKeyRevoked} {
+		events, _, err := trail.List(ctx, repository.AuditFilter{Action: action})
+		require.NoError(t, err)
+		require.Len(t, events, 1, action)
+		assert.Equal(t, "root", events[0].Actor)
+		assert.NotContains(t, string(events[0].After), created.Key)
+	}
+}
+
+func TestAdminScopeNeedsAdminPermissions(t *testing.T) {
+	ctx := context.Background()
+	users := newMemoryUserRepo()
+	trail := NewAuditService(newMemoryAuditRepo())
+	rbac := NewRBACService(newSeededRoleRepo(), users, newMemoryAssignmentRepo(), trail)
+	svc := NewAPIKeyService(newMemoryAPIKeyRepo(), users, rbac, trail)
+
+	translator := &types.JWTClaims{UserID: 1, Username: "bob", Role: "translator"}
+	_, err := svc.Create(ctx, translator, CreateAPIKeyInput{Name: "ci", Scope: model.APIKeyScopeAdmin})
+	assert.Error(t, err)
+	_, err = svc.Create(ctx, translator, CreateAPIKeyInput{Name: "ci", Scope: model.APIKeyScopeTranslate})
+	assert.NoError(t, err)
+}
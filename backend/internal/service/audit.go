@@ -0,0 +1,129 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/vietgs03/translate/backend/internal/audit"
+	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/logging"
+	"github.com/vietgs03/translate/backend/internal/model"
+	"github.com/vietgs03/translate/backend/internal/repository"
+	"github.com/vietgs03/translate/backend/internal/types"
+	"go.uber.org/zap"
+)
+
+// auditExportBatch is how many events an export reads per query
+const auditExportBatch = 500
+
+type AuditService interface {
+	// Record stores an event. Failures are logged, never returned: an
+	// action that already happened can't be undone because its trail
+	// couldn't be written.
+	Record(ctx context.Context, entry AuditEntry)
+	List(ctx context.Context, filter repository.AuditFilter) ([]model.AuditEvent, int64, error)
+	// Export writes every matching event as a line of JSON, oldest first
+	Export(ctx context.Context, filter repository.AuditFilter, w io.Writer) error
+}
+
+// AuditEntry describes an action to record. When no actor is given, the
+// authenticated user of the request is used. Before and After are stored
+// as JSON.
+type AuditEntry struct {
+	ActorID    *uint
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     interface{}
+	After      interface{}
+}
+
+type auditService struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+func (s *auditService) Record(ctx context.Context, entry AuditEntry) {
+	request := audit.RequestFromContext(ctx)
+	event := &model.AuditEvent{
+		ActorID:    entry.ActorID,
+		Actor:      entry.Actor,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		IP:         request.IP,
+		RequestID:  request.RequestID,
+	}
+	if event.ActorID == nil && event.Actor == "" {
+		if claims, ok := ctx.Value("user").(*types.JWTClaims); ok {
+			event.ActorID = &claims.UserID
+			event.Actor = claims.Username
+		}
+	}
+
+	logger := logging.FromContext(ctx)
+	var err error
+	if event.Before, err = auditJSON(entry.Before); err != nil {
+		logger.Error("failed to encode audit event", zap.String("action", entry.Action), zap.Error(err))
+	}
+	if event.After, err = auditJSON(entry.After); err != nil {
+		logger.Error("failed to encode audit event", zap.String("action", entry.Action), zap.Error(err))
+	}
+
+	// The trail is written even if the client has gone away
+	if err := s.repo.Create(context.WithoutCancel(ctx), event); err != nil {
+		logger.Error("failed to record audit event",
+			zap.String("action", entry.Action),
+			zap.String("target_type", entry.TargetType),
+			zap.String("target_id", entry.TargetID),
+			zap.Error(err),
+		)
+	}
+}
+
+func auditJSON(value interface{}) (model.JSON, error) {
+	if value == nil {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
+
+func (s *auditService) List(ctx context.Context, filter repository.AuditFilter) ([]model.AuditEvent, int64, error) {
+	events, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, errors.NewDatabaseError("failed to list audit events: %v", err)
+	}
+	return events, total, nil
+}
+
+func (s *auditService) Export(ctx context.Context, filter repository.AuditFilter, w io.Writer) error {
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+
+	var afterID uint64
+	for {
+		events, err := s.repo.ListAfter(ctx, filter, afterID, auditExportBatch)
+		if err != nil {
+			return errors.NewDatabaseError("failed to export audit events: %v", err)
+		}
+		for _, event := range events {
+			if err := encoder.Encode(event); err != nil {
+				return fmt.Errorf("failed to write audit event %d: %v", event.ID, err)
+			}
+		}
+		if err := buffered.Flush(); err != nil {
+			return fmt.Errorf("failed to write audit events: %v", err)
+		}
+		if len(events) < auditExportBatch {
+			return nil
+		}
+		afterID = events[len(events)-1].ID
+	}
+}
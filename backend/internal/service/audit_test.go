@@ -0,0 +1,116 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vietgs03/translate/backend/internal/audit"
+	"github.com/vietgs03/translate/backend/internal/model"
+	"github.com/vietgs03/translate/backend/internal/repository"
+	"github.com/vietgs03/translate/backend/internal/types"
+)
+
+// memoryAuditRepo is a minimal in-memory AuditRepository
+type memoryAuditRepo struct {
+	mu     sync.Mutex
+	events []model.AuditEvent
+}
+
+func newMemoryAuditRepo() *memoryAuditRepo {
+	return &memoryAuditRepo{}
+}
+
+func (r *memoryAuditRepo) Create(ctx context.Context, event *model.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event.ID = uint64(len(r.events) + 1)
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *memoryAuditRepo) matching(filter repository.AuditFilter) []model.AuditEvent {
+	var matches []model.AuditEvent
+	for _, event := range r.events {
+		if (filter.Actor == "" || event.Actor == filter.Actor) &&
+			(filter.Action == "" || event.Action == filter.Action) &&
+			(filter.TargetType == "" || event.TargetType == filter.TargetType) &&
+			(filter.TargetID == "" || event.TargetID == filter.TargetID) {
+			matches = append(matches, event)
+		}
+	}
+	return matches
+}
+
+func (r *memoryAuditRepo) List(ctx context.Context, filter repository.AuditFilter) ([]model.AuditEvent, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	matches := r.matching(filter)
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID > matches[j].ID })
+	return matches, int64(len(matches)), nil
+}
+
+func (r *memoryAuditRepo) ListAfter(ctx context.Context, filter repository.AuditFilter, afterID uint64, limit int) ([]model.AuditEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var page []model.AuditEvent
+	for _, event := range r.matching(filter) {
+		if event.ID > afterID && len(page) < limit {
+			page = append(page, event)
+		}
+	}
+	return page, nil
+}
+
+func TestAuditTrail(t *testing.T) {
+	svc, users := newTestAuthService(t)
+	ctx := audit.ContextWithRequest(context.Background(), audit.Request{IP: "192.0.2.7", RequestID: "req-1"})
+	trail := svc.audit
+
+	_, err := svc.Login(ctx, LoginInput{Username: "alice", Password: "wrong", IP: "192.0.2.7"})
+	require.Error(t, err)
+
+	alice, err := users.GetByUsername(ctx, "alice")
+	require.NoError(t, err)
+	// Admin actions take their actor from the authenticated request
+	adminCtx := context.WithValue(ctx, "user", &types.JWTClaims{UserID: 99, Username: "root"})
+	_, err = svc.UpdateRole(adminCtx, alice.ID, "admin")
+	require.NoError(t, err)
+
+	failed, _, err := trail.List(ctx, repository.AuditFilter{Action: model.AuditActionLoginFailed})
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Empty(t, failed[0].Actor, "the attempted username is not an actor")
+	assert.Nil(t, failed[0].ActorID)
+	assert.Equal(t, "192.0.2.7", failed[0].IP)
+	assert.Equal(t, "req-1", failed[0].RequestID)
+	assert.JSONEq(t, `{"reason":"invalid_credentials","username":"alice"}`, string(failed[0].After))
+
+	changed, _, err := trail.List(ctx, repository.AuditFilter{Action: model.AuditActionUserRoleChanged})
+	require.NoError(t, err)
+	require.Len(t, changed, 1)
+	assert.Equal(t, "root", changed[0].Actor)
+	assert.JSONEq(t, `{"role":"translator"}`, string(changed[0].Before))
+	assert.JSONEq(t, `{"role":"admin"}`, string(changed[0].After))
+
+	var export bytes.Buffer
+	require.NoError(t, trail.Export(ctx, repository.AuditFilter{}, &export))
+	var actions []string
+	scanner := bufio.NewScanner(&export)
+	for scanner.Scan() {
+		var event model.AuditEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		actions = append(actions, event.Action)
+	}
+	assert.Equal(t, []string{
+		model.AuditActionUserRegistered,
+		model.AuditActionLoginFailed,
+		model.AuditActionUserRoleChanged,
+	}, actions)
+}
@@ -2,34 +2,128 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/vietgs03/translate/backend/internal/config"
 	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/lockout"
+	"github.com/vietgs03/translate/backend/internal/logging"
+	"github.com/vietgs03/translate/backend/internal/mail"
 	"github.com/vietgs03/translate/backend/internal/model"
+	"github.com/vietgs03/translate/backend/internal/oidc"
 	"github.com/vietgs03/translate/backend/internal/repository"
+	"github.com/vietgs03/translate/backend/internal/token"
 	"github.com/vietgs03/translate/backend/internal/types"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
 )
 
+// oidcLoginTTL bounds how long a user may take at the identity provider
+const oidcLoginTTL = 10 * time.Minute
+
 type AuthService interface {
 	Register(ctx context.Context, input RegisterInput) (*model.User, error)
-	Login(ctx context.Context, input LoginInput) (string, error)
+	Login(ctx context.Context, input LoginInput) (*types.LoginResponse, error)
+	// OIDCAuthURL starts a single sign-on login and returns the provider URL to redirect to
+	OIDCAuthURL(ctx context.Context) (string, error)
+	// OIDCLinkURL starts linking a provider identity to the principal's
+	// account, which single sign-on then logs into
+	OIDCLinkURL(ctx context.Context, principal *types.JWTClaims) (string, error)
+	// LoginWithOIDC completes a single sign-on login from the provider's callback
+	LoginWithOIDC(ctx context.Context, state, code string) (*types.LoginResponse, error)
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	// ForgotPassword mails a reset token; it succeeds whether or not the email is known
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, input ResetPasswordInput) error
+	// UnlockUser lifts a login lockout of the user
+	UnlockUser(ctx context.Context, userID uint) error
+	Refresh(ctx context.Context, refreshToken string) (*types.LoginResponse, error)
+	Logout(ctx context.Context, claims *types.JWTClaims, refreshToken string) error
 	ValidateToken(token string) (*jwt.Token, error)
 	UpdateRole(ctx context.Context, userID uint, role string) (*model.User, error)
+	// IsActive reports whether the user still exists and is not deactivated
+	IsActive(ctx context.Context, userID uint) (bool, error)
+	ListUsers(ctx context.Context, filter repository.UserFilter) ([]model.User, int64, error)
+	GetUser(ctx context.Context, userID uint) (*model.User, error)
+	// SetUserActive deactivates or reactivates a user; deactivation ends every session
+	SetUserActive(ctx context.Context, actor *types.JWTClaims, userID uint, active bool) (*model.User, error)
+	// AdminResetPassword sets the given password, or mails a reset token when it is empty
+	AdminResetPassword(ctx context.Context, userID uint, input AdminResetPasswordInput) error
+	DeleteUser(ctx context.Context, actor *types.JWTClaims, userID uint) error
 }
 
 type authService struct {
-	userRepo  repository.UserRepository
-	jwtConfig config.JWTConfig
+	userRepo      repository.UserRepository
+	refreshRepo   repository.RefreshTokenRepository
+	userTokenRepo repository.UserTokenRepository
+	keys          *token.KeySet
+	denylist      token.Denylist
+	loginGuard    lockout.Guard
+	mailer        mail.Mailer
+	jwtConfig     config.JWTConfig
+	authConfig    config.AuthConfig
+	oidc          *oidc.Provider // nil when single sign-on is disabled
+	oidcStates    oidc.StateStore
+	audit         AuditService
+
+	activeMu sync.Mutex
+	active   map[uint]activeStatus
+}
+
+// activeCacheTTL bounds how long a deactivation made on another replica
+// takes to apply here. It rarely matters: deactivation also revokes the
+// user's tokens, this only backs that up when the revocation failed.
+const activeCacheTTL = 30 * time.Second
+
+// activeCacheSize caps the users whose status is cached at once
+const activeCacheSize = 10000
+
+type activeStatus struct {
+	active    bool
+	checkedAt time.Time
 }
 
-func NewAuthService(userRepo repository.UserRepository, jwtConfig config.JWTConfig) AuthService {
+func NewAuthService(
+	userRepo repository.UserRepository,
+	refreshRepo repository.RefreshTokenRepository,
+	userTokenRepo repository.UserTokenRepository,
+	keys *token.KeySet,
+	denylist token.Denylist,
+	loginGuard lockout.Guard,
+	mailer mail.Mailer,
+	jwtConfig config.JWTConfig,
+	authConfig config.AuthConfig,
+	oidcProvider *oidc.Provider,
+	oidcStates oidc.StateStore,
+	auditService AuditService,
+) AuthService {
 	return &authService{
-		userRepo:  userRepo,
-		jwtConfig: jwtConfig,
+		userRepo:      userRepo,
+		refreshRepo:   refreshRepo,
+		userTokenRepo: userTokenRepo,
+		keys:          keys,
+		denylist:      denylist,
+		loginGuard:    loginGuard,
+		mailer:        mailer,
+		jwtConfig:     jwtConfig,
+		authConfig:    authConfig,
+		oidc:          oidcProvider,
+		oidcStates:    oidcStates,
+		audit:         auditService,
+		active:        make(map[uint]activeStatus),
 	}
 }
 
@@ -42,17 +136,39 @@ type RegisterInput struct {
 type LoginInput struct {
 	Username string `json:"username" validate:"required"`
 	Password string `json:"password" validate:"required"`
+	IP       string `json:"-"` // client address, set by the handler
+}
+
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type EmailInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+type AdminResetPasswordInput struct {
+	Password string `json:"password" validate:"omitempty,min=6"`
 }
 
 func (s *authService) Register(ctx context.Context, input RegisterInput) (*model.User, error) {
 	// Check if username exists
 	if _, err := s.userRepo.GetByUsername(ctx, input.Username); err == nil {
-		return nil, errors.NewValidationError("username already exists")
+		return nil, errors.NewConflictError("username already exists")
 	}
 
 	// Check if email exists
 	if _, err := s.userRepo.GetByEmail(ctx, input.Email); err == nil {
-		return nil, errors.NewValidationError("email already exists")
+		return nil, errors.NewConflictError("email already exists")
 	}
 
 	// Hash password
@@ -71,43 +187,708 @@ func (s *authService) Register(ctx context.Context, input RegisterInput) (*model
 	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, errors.NewDatabaseError("failed to create user: %v", err)
 	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID:    &user.ID,
+		Actor:      user.Username,
+		Action:     model.AuditActionUserRegistered,
+		TargetType: model.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(user.ID), 10),
+		After:      user,
+	})
+
+	// The account exists either way; the user can ask for another email
+	if err := s.sendVerification(ctx, user); err != nil {
+		logging.FromContext(ctx).Error("failed to send verification email", zap.Uint("user_id", user.ID), zap.Error(err))
+	}
 
 	return user, nil
 }
 
-func (s *authService) Login(ctx context.Context, input LoginInput) (string, error) {
+// Login answers every failure, including lockouts, with the same "invalid
+// credentials" so the response doesn't reveal which usernames exist.
+func (s *authService) Login(ctx context.Context, input LoginInput) (*types.LoginResponse, error) {
+	invalid := errors.NewError(errors.Unauthorized, errors.CodeInvalidCredentials, "invalid credentials")
+
+	// Without the counters logins keep working, just unthrottled
+	state, err := s.loginGuard.Check(ctx, input.Username, input.IP)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to check login failures", zap.Error(err))
+	}
+	if state.Locked() {
+		securityLogger(ctx).Warn("rejected login during lockout",
+			zap.String("username", input.Username),
+			zap.String("ip", input.IP),
+			zap.Bool("user_locked", state.UserLocked),
+			zap.Bool("ip_locked", state.IPLocked),
+		)
+		s.recordLoginAudit(ctx, model.AuditActionLoginFailed, input.Username, nil, "locked_out")
+		return nil, invalid
+	}
+	if state.Delay > 0 {
+		select {
+		case <-time.After(state.Delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
 	user, err := s.userRepo.GetByUsername(ctx, input.Username)
 	if err != nil {
-		return "", errors.NewValidationError("invalid credentials")
+		// Spend the same time as a wrong password for an existing user
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(input.Password))
+		s.recordLoginFailure(ctx, input)
+		return nil, invalid
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
-		return "", errors.NewValidationError("invalid credentials")
+		s.recordLoginFailure(ctx, input)
+		return nil, invalid
+	}
+
+	if err := s.loginGuard.RecordSuccess(ctx, input.Username); err != nil {
+		logging.FromContext(ctx).Error("failed to reset login failures", zap.String("username", input.Username), zap.Error(err))
+	}
+
+	if !user.Active {
+		s.recordLoginAudit(ctx, model.AuditActionLoginFailed, user.Username, &user.ID, "account_disabled")
+		return nil, errors.NewError(errors.Unauthorized, errors.CodeAccountDisabled, "account is disabled")
+	}
+	if s.authConfig.RequireEmailVerification && user.EmailVerifiedAt == nil {
+		s.recordLoginAudit(ctx, model.AuditActionLoginFailed, user.Username, &user.ID, "email_not_verified")
+		return nil, errors.NewError(errors.Unauthorized, errors.CodeEmailNotVerified, "email address is not verified")
+	}
+
+	s.recordLoginAudit(ctx, model.AuditActionLogin, user.Username, &user.ID, "password")
+	// Every login starts a new refresh token family
+	return s.issueTokens(ctx, user, uuid.NewString())
+}
+
+func (s *authService) recordLoginFailure(ctx context.Context, input LoginInput) {
+	s.recordLoginAudit(ctx, model.AuditActionLoginFailed, input.Username, nil, "invalid_credentials")
+
+	state, err := s.loginGuard.RecordFailure(ctx, input.Username, input.IP)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to record login failure", zap.Error(err))
+		return
+	}
+
+	logger := securityLogger(ctx)
+	logger.Warn("failed login",
+		zap.String("username", input.Username),
+		zap.String("ip", input.IP),
+		zap.Int("user_failures", state.UserFailures),
+		zap.Int("ip_failures", state.IPFailures),
+	)
+	if state.UserLocked {
+		logger.Warn("locked out username after repeated failed logins", zap.String("username", input.Username))
+	}
+	if state.IPLocked {
+		logger.Warn("locked out ip after repeated failed logins", zap.String("ip", input.IP))
+	}
+}
+
+// securityLogger is the logger of security relevant events, named so they
+// can be routed apart
+func securityLogger(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx).Named("security")
+}
+
+// recordLoginAudit records a login attempt. Failed attempts have no actor:
+// the username is only what the client typed, so it goes into the details.
+func (s *authService) recordLoginAudit(ctx context.Context, action, username string, userID *uint, detail string) {
+	entry := AuditEntry{
+		Action:     action,
+		TargetType: model.AuditTargetUser,
+	}
+	if userID != nil {
+		entry.TargetID = strconv.FormatUint(uint64(*userID), 10)
+	}
+	if action == model.AuditActionLogin {
+		entry.ActorID, entry.Actor = userID, username
+		entry.After = map[string]string{"method": detail}
+	} else {
+		entry.After = map[string]string{"reason": detail, "username": username}
+	}
+	s.audit.Record(ctx, entry)
+}
+
+func (s *authService) UnlockUser(ctx context.Context, userID uint) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return errors.NewNotFoundError("user not found")
+	}
+
+	if err := s.loginGuard.Unlock(ctx, user.Username); err != nil {
+		return fmt.Errorf("failed to unlock user: %v", err)
+	}
+
+	securityLogger(ctx).Info("login lockout lifted by an admin", zap.String("username", user.Username))
+	s.audit.Record(ctx, AuditEntry{
+		Action:     model.AuditActionUserUnlocked,
+		TargetType: model.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(user.ID), 10),
+	})
+	return nil
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+// dummyPasswordHash is compared against when the username doesn't exist
+func dummyPasswordHash() []byte {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not-a-real-password"), bcrypt.DefaultCost)
+	})
+	return dummyHash
+}
+
+func (s *authService) OIDCAuthURL(ctx context.Context) (string, error) {
+	return s.oidcAuthURL(ctx, 0)
+}
+
+func (s *authService) OIDCLinkURL(ctx context.Context, principal *types.JWTClaims) (string, error) {
+	if principal.APIKeyID != 0 {
+		return "", errors.NewForbiddenError("API keys cannot link single sign-on")
+	}
+	return s.oidcAuthURL(ctx, principal.UserID)
+}
+
+func (s *authService) oidcAuthURL(ctx context.Context, linkUserID uint) (string, error) {
+	if s.oidc == nil {
+		return "", errors.NewValidationError("single sign-on is not configured")
+	}
+
+	state, err := generateOpaqueToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate login state: %v", err)
+	}
+	nonce, err := generateOpaqueToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	pending := oidc.PendingLogin{Nonce: nonce, Verifier: oauth2.GenerateVerifier(), LinkUserID: linkUserID}
+
+	if err := s.oidcStates.Save(ctx, state, pending, oidcLoginTTL); err != nil {
+		return "", fmt.Errorf("failed to store login state: %v", err)
+	}
+
+	return s.oidc.AuthCodeURL(state, pending.Nonce, pending.Verifier), nil
+}
+
+func (s *authService) LoginWithOIDC(ctx context.Context, state, code string) (*types.LoginResponse, error) {
+	if s.oidc == nil {
+		return nil, errors.NewValidationError("single sign-on is not configured")
+	}
+
+	pending, err := s.oidcStates.Take(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	if pending == nil {
+		return nil, errors.NewUnauthorizedError("invalid or expired login state")
+	}
+
+	identity, err := s.oidc.Exchange(ctx, code, pending.Verifier, pending.Nonce)
+	if err != nil {
+		logging.FromContext(ctx).Warn("OIDC login failed", zap.Error(err))
+		return nil, errors.NewUnauthorizedError("single sign-on failed")
+	}
+
+	var user *model.User
+	if pending.LinkUserID != 0 {
+		user, err = s.linkOIDCUser(ctx, pending.LinkUserID, identity)
+	} else {
+		user, err = s.provisionOIDCUser(ctx, identity)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !user.Active {
+		s.recordLoginAudit(ctx, model.AuditActionLoginFailed, user.Username, &user.ID, "account_disabled")
+		return nil, errors.NewError(errors.Unauthorized, errors.CodeAccountDisabled, "account is disabled")
+	}
+
+	s.recordLoginAudit(ctx, model.AuditActionLogin, user.Username, &user.ID, "oidc")
+	return s.issueTokens(ctx, user, uuid.NewString())
+}
+
+// provisionOIDCUser finds the user linked to an identity or creates one on
+// first login. An existing account is never taken over by email; its owner
+// links the identity explicitly. A role mapped from the ID token claims
+// wins over the stored role on every login.
+func (s *authService) provisionOIDCUser(ctx context.Context, identity *oidc.Identity) (*model.User, error) {
+	user, err := s.userRepo.GetByOIDCSubject(ctx, identity.Issuer, identity.Subject)
+	if err == nil {
+		if err := s.applyOIDCRole(ctx, user, identity.Role); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	if identity.Email != "" {
+		if _, err := s.userRepo.GetByEmail(ctx, identity.Email); err == nil {
+			return nil, errors.NewUnauthorizedError("email is already registered, log in and link single sign-on to that account")
+		}
+	}
+
+	username, err := s.availableUsername(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	// SSO users log in through the provider; nobody knows this password
+	unusable, err := generateOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %v", err)
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(unusable), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	role := identity.Role
+	if role == "" {
+		role = s.oidc.DefaultRole()
+	}
+
+	email := identity.Email
+	if email == "" {
+		email = identity.Subject + "@" + strings.TrimPrefix(strings.TrimPrefix(identity.Issuer, "https://"), "http://")
+	}
+
+	user = &model.User{
+		Username:    username,
+		Email:       email,
+		Password:    string(hashedPassword),
+		Role:        role,
+		Active:      true,
+		OIDCIssuer:  identity.Issuer,
+		OIDCSubject: identity.Subject,
+	}
+	if identity.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, errors.NewDatabaseError("failed to create user: %v", err)
+	}
+	return user, nil
+}
+
+// linkOIDCUser links identity to the account that started the link, as
+// long as neither is linked to something else yet
+func (s *authService) linkOIDCUser(ctx context.Context, userID uint, identity *oidc.Identity) (*model.User, error) {
+	if linked, err := s.userRepo.GetByOIDCSubject(ctx, identity.Issuer, identity.Subject); err == nil && linked.ID != userID {
+		return nil, errors.NewConflictError("identity is linked to another account")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+	if user.OIDCSubject != "" && (user.OIDCIssuer != identity.Issuer || user.OIDCSubject != identity.Subject) {
+		return nil, errors.NewConflictError("account is linked to another identity")
+	}
+
+	if user.OIDCSubject == "" {
+		user.OIDCIssuer = identity.Issuer
+		user.OIDCSubject = identity.Subject
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, errors.NewDatabaseError("failed to link user: %v", err)
+		}
+		s.audit.Record(ctx, AuditEntry{
+			ActorID:    &user.ID,
+			Actor:      user.Username,
+			Action:     model.AuditActionUserOIDCLinked,
+			TargetType: model.AuditTargetUser,
+			TargetID:   strconv.FormatUint(uint64(user.ID), 10),
+			After:      map[string]string{"issuer": identity.Issuer, "subject": identity.Subject},
+		})
+	}
+
+	if err := s.applyOIDCRole(ctx, user, identity.Role); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// applyOIDCRole stores the role mapped from the provider's claims. Tokens
+// carrying the old role are revoked, like after a role change by an admin.
+func (s *authService) applyOIDCRole(ctx context.Context, user *model.User, role string) error {
+	if role == "" || role == user.Role {
+		return nil
+	}
+
+	previous := user.Role
+	user.Role = role
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return errors.NewDatabaseError("failed to update user role: %v", err)
+	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID:    &user.ID,
+		Actor:      user.Username,
+		Action:     model.AuditActionUserRoleChanged,
+		TargetType: model.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(user.ID), 10),
+		Before:     map[string]string{"role": previous},
+		After:      map[string]string{"role": role, "source": "oidc"},
+	})
+
+	s.endSessions(ctx, user.ID)
+	return nil
+}
+
+// availableUsername derives a username from the identity, adding a random
+// suffix when it is already taken.
+func (s *authService) availableUsername(ctx context.Context, identity *oidc.Identity) (string, error) {
+	base := identity.Username
+	if base == "" {
+		base, _, _ = strings.Cut(identity.Email, "@")
+	}
+	if len(base) < 3 {
+		base = "user"
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		if _, err := s.userRepo.GetByUsername(ctx, candidate); err != nil {
+			return candidate, nil
+		}
+		suffix, err := generateOpaqueToken()
+		if err != nil {
+			return "", fmt.Errorf("failed to generate username: %v", err)
+		}
+		candidate = base + "-" + strings.ToLower(suffix[:6])
+	}
+	return "", errors.NewValidationError("could not find a free username for %s", base)
+}
+
+func (s *authService) VerifyEmail(ctx context.Context, token string) error {
+	user, err := s.redeemUserToken(ctx, model.UserTokenEmailVerification, token)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return errors.NewDatabaseError("failed to verify email: %v", err)
+		}
+	}
+	return nil
+}
+
+func (s *authService) ResendVerification(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil || user.EmailVerifiedAt != nil {
+		return nil // Don't reveal which addresses are registered
+	}
+
+	logger := logging.FromContext(ctx)
+	go func() {
+		if err := s.sendVerification(context.WithoutCancel(ctx), user); err != nil {
+			logger.Error("failed to send verification email", zap.Uint("user_id", user.ID), zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+func (s *authService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil || !user.Active {
+		return nil // Don't reveal which addresses are registered
+	}
+
+	// Sent in the background so the response time doesn't reveal it either
+	logger := logging.FromContext(ctx)
+	go func() {
+		if err := s.sendPasswordReset(context.WithoutCancel(ctx), user); err != nil {
+			logger.Error("failed to send password reset email", zap.Uint("user_id", user.ID), zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// ResetPassword sets a new password and ends every session of the user,
+// since whoever held the old password may still be logged in.
+func (s *authService) ResetPassword(ctx context.Context, input ResetPasswordInput) error {
+	user, err := s.redeemUserToken(ctx, model.UserTokenPasswordReset, input.Token)
+	if err != nil {
+		return err
+	}
+	// The link may have been sent before the account was disabled
+	if !user.Active {
+		return errors.NewError(errors.Unauthorized, errors.CodeAccountDisabled, "account is disabled")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	user.Password = string(hashedPassword)
+	// The reset link reached the inbox, which proves the address
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return errors.NewDatabaseError("failed to update password: %v", err)
+	}
+
+	s.endSessions(ctx, user.ID)
+	s.audit.Record(ctx, AuditEntry{
+		ActorID:    &user.ID,
+		Actor:      user.Username,
+		Action:     model.AuditActionPasswordReset,
+		TargetType: model.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(user.ID), 10),
+	})
+	return nil
+}
+
+// endSessions revokes the refresh tokens of a user and rejects the access
+// tokens issued so far
+func (s *authService) endSessions(ctx context.Context, userID uint) {
+	if err := s.refreshRepo.RevokeAllForUser(ctx, userID); err != nil {
+		logging.FromContext(ctx).Error("failed to revoke refresh tokens", zap.Uint("user_id", userID), zap.Error(err))
+	}
+	if err := s.denylist.RevokeUser(ctx, userID); err != nil {
+		logging.FromContext(ctx).Error("failed to revoke access tokens", zap.Uint("user_id", userID), zap.Error(err))
+	}
+}
+
+func (s *authService) sendVerification(ctx context.Context, user *model.User) error {
+	ttl := time.Duration(s.authConfig.VerificationTTL) * time.Hour
+	token, err := s.createUserToken(ctx, user.ID, model.UserTokenEmailVerification, ttl)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease verify your email address by opening this link:\n\n%s/email/verify?token=%s\n\nThe link expires in %d hours.\n",
+			user.Username, s.authConfig.LinkBaseURL, token, s.authConfig.VerificationTTL),
+	})
+}
+
+func (s *authService) sendPasswordReset(ctx context.Context, user *model.User) error {
+	ttl := time.Duration(s.authConfig.PasswordResetTTL) * time.Minute
+	token, err := s.createUserToken(ctx, user.ID, model.UserTokenPasswordReset, ttl)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to reset the password of your account. If it was you, use this token to choose a new one:\n\n%s\n\nIt expires in %d minutes. If it wasn't you, you can ignore this email.\n",
+			user.Username, token, s.authConfig.PasswordResetTTL),
+	})
+}
+
+// createUserToken issues a new token for purpose, replacing older ones
+func (s *authService) createUserToken(ctx context.Context, userID uint, purpose string, ttl time.Duration) (string, error) {
+	if err := s.userTokenRepo.InvalidateForUser(ctx, userID, purpose); err != nil {
+		return "", errors.NewDatabaseError("failed to invalidate tokens: %v", err)
+	}
+
+	token, err := generateOpaqueToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %v", err)
+	}
+
+	if err := s.userTokenRepo.Create(ctx, &model.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		return "", errors.NewDatabaseError("failed to store token: %v", err)
+	}
+	return token, nil
+}
+
+// redeemUserToken spends a token and returns its user
+func (s *authService) redeemUserToken(ctx context.Context, purpose, token string) (*model.User, error) {
+	stored, err := s.userTokenRepo.GetByHash(ctx, purpose, hashToken(token))
+	if err != nil || stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return nil, errors.NewError(errors.ValidationErr, errors.CodeInvalidToken, "invalid or expired token")
+	}
+
+	won, err := s.userTokenRepo.MarkUsed(ctx, stored.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("failed to redeem token: %v", err)
+	}
+	if !won {
+		return nil, errors.NewError(errors.ValidationErr, errors.CodeInvalidToken, "invalid or expired token")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, errors.NewError(errors.ValidationErr, errors.CodeInvalidToken, "invalid or expired token")
+	}
+	return user, nil
+}
+
+// Refresh rotates a refresh token: the presented token is spent and a new
+// pair is issued in the same family. Presenting a spent token again means
+// it leaked, so the whole family is revoked.
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*types.LoginResponse, error) {
+	stored, err := s.refreshRepo.GetByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return nil, errors.NewError(errors.Unauthorized, errors.CodeInvalidToken, "invalid refresh token")
+	}
+
+	if stored.RevokedAt != nil {
+		return nil, errors.NewError(errors.Unauthorized, errors.CodeInvalidToken, "invalid refresh token")
+	}
+	if stored.UsedAt != nil {
+		s.revokeReusedFamily(ctx, stored)
+		return nil, errors.NewError(errors.Unauthorized, errors.CodeInvalidToken, "invalid refresh token")
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, errors.NewError(errors.Unauthorized, errors.CodeTokenExpired, "refresh token expired")
+	}
+
+	// Lost a race against a concurrent use of the same token
+	won, err := s.refreshRepo.MarkUsed(ctx, stored.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("failed to rotate refresh token: %v", err)
+	}
+	if !won {
+		s.revokeReusedFamily(ctx, stored)
+		return nil, errors.NewError(errors.Unauthorized, errors.CodeInvalidToken, "invalid refresh token")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, errors.NewError(errors.Unauthorized, errors.CodeInvalidToken, "invalid refresh token")
+	}
+	if !user.Active {
+		return nil, errors.NewError(errors.Unauthorized, errors.CodeAccountDisabled, "account is disabled")
+	}
+
+	return s.issueTokens(ctx, user, stored.FamilyID)
+}
+
+// Logout denylists the current access token and, when given, revokes the
+// refresh token family it belongs to.
+func (s *authService) Logout(ctx context.Context, claims *types.JWTClaims, refreshToken string) error {
+	// A key is no session; it must not end the sessions of its owner
+	if claims.APIKeyID != 0 {
+		return errors.NewForbiddenError("API keys cannot log out, revoke the key instead")
+	}
+
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		if err := s.denylist.Revoke(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+			return fmt.Errorf("failed to revoke access token: %v", err)
+		}
+	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID:    &claims.UserID,
+		Actor:      claims.Username,
+		Action:     model.AuditActionLogout,
+		TargetType: model.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(claims.UserID), 10),
+	})
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := s.refreshRepo.GetByHash(ctx, hashToken(refreshToken))
+	if err != nil || stored.UserID != claims.UserID {
+		// Nothing of this user's to revoke; don't tell the caller which
+		return nil
+	}
+
+	if err := s.refreshRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		return errors.NewDatabaseError("failed to revoke refresh token: %v", err)
+	}
+	return nil
+}
+
+func (s *authService) issueTokens(ctx context.Context, user *model.User, familyID string) (*types.LoginResponse, error) {
+	now := time.Now()
+	accessTTL := time.Duration(s.jwtConfig.AccessExpiresIn) * time.Minute
+
+	// A revocation after this read leaves the token revoked, never one
+	// before it valid
+	version, err := s.denylist.TokenVersion(ctx, user.ID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Generate JWT token
 	claims := &types.JWTClaims{
-		UserID:   user.ID,
-		Username: user.Username,
-		Role:     user.Role,
+		UserID:       user.ID,
+		Username:     user.Username,
+		Role:         user.Role,
+		TokenVersion: version,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(s.jwtConfig.ExpiresIn) * time.Hour)),
+			ID:        uuid.NewString(),
+			Issuer:    s.jwtConfig.Issuer,
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			Audience:  jwt.ClaimStrings{s.jwtConfig.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTTL)),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(s.jwtConfig.SecretKey))
+	signedToken, err := s.keys.Sign(claims)
 	if err != nil {
-		return "", fmt.Errorf("failed to sign token: %v", err)
+		return nil, fmt.Errorf("failed to sign token: %v", err)
 	}
 
-	return signedToken, nil
+	refreshToken, err := generateOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %v", err)
+	}
+
+	if err := s.refreshRepo.Create(ctx, &model.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(refreshToken),
+		FamilyID:  familyID,
+		ExpiresAt: now.Add(time.Duration(s.jwtConfig.RefreshExpiresIn) * time.Hour),
+	}); err != nil {
+		return nil, errors.NewDatabaseError("failed to store refresh token: %v", err)
+	}
+
+	return &types.LoginResponse{
+		Token:        signedToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(accessTTL.Seconds()),
+	}, nil
 }
 
-func (s *authService) ValidateToken(tokenString string) (*jwt.Token, error) {
-	return jwt.ParseWithClaims(tokenString, &types.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.jwtConfig.SecretKey), nil
+func (s *authService) revokeReusedFamily(ctx context.Context, stored *model.RefreshToken) {
+	securityLogger(ctx).Warn("refresh token reuse detected, revoking family",
+		zap.Uint("user_id", stored.UserID),
+		zap.String("family_id", stored.FamilyID),
+	)
+	s.audit.Record(ctx, AuditEntry{
+		Action:     model.AuditActionRefreshReuse,
+		TargetType: model.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(stored.UserID), 10),
+		After:      map[string]string{"family_id": stored.FamilyID},
 	})
+	if err := s.refreshRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		logging.FromContext(ctx).Error("failed to revoke refresh token family", zap.String("family_id", stored.FamilyID), zap.Error(err))
+	}
+}
+
+func (s *authService) ValidateToken(tokenString string) (*jwt.Token, error) {
+	return s.keys.Parse(tokenString, &types.JWTClaims{})
 }
 
 func (s *authService) UpdateRole(ctx context.Context, userID uint, role string) (*model.User, error) {
@@ -118,10 +899,205 @@ func (s *authService) UpdateRole(ctx context.Context, userID uint, role string)
 	}
 
 	// Update role
+	previous := user.Role
 	user.Role = role
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, errors.NewDatabaseError("failed to update user role: %v", err)
 	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     model.AuditActionUserRoleChanged,
+		TargetType: model.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(user.ID), 10),
+		Before:     map[string]string{"role": previous},
+		After:      map[string]string{"role": role},
+	})
+
+	// Access tokens carry the old role; reject them so the change applies
+	// now. The next refresh issues a token with the new role.
+	if err := s.denylist.RevokeUser(ctx, user.ID); err != nil {
+		logging.FromContext(ctx).Error("failed to revoke access tokens", zap.Uint("user_id", user.ID), zap.Error(err))
+	}
+
+	return user, nil
+}
+
+// IsActive is checked on every authenticated request, so answers are
+// cached for activeCacheTTL
+func (s *authService) IsActive(ctx context.Context, userID uint) (bool, error) {
+	s.activeMu.Lock()
+	status, ok := s.active[userID]
+	s.activeMu.Unlock()
+	if ok && time.Since(status.checkedAt) < activeCacheTTL {
+		return status.active, nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, errors.NewDatabaseError("failed to get user: %v", err)
+	}
+	active := err == nil && user.Active
+
+	s.activeMu.Lock()
+	defer s.activeMu.Unlock()
+	if len(s.active) >= activeCacheSize {
+		for id, status := range s.active {
+			if time.Since(status.checkedAt) >= activeCacheTTL {
+				delete(s.active, id)
+			}
+		}
+		if len(s.active) >= activeCacheSize {
+			s.active = make(map[uint]activeStatus)
+		}
+	}
+	s.active[userID] = activeStatus{active: active, checkedAt: time.Now()}
+	return active, nil
+}
+
+// forgetActive makes the next IsActive on this replica read the database
+func (s *authService) forgetActive(userID uint) {
+	s.activeMu.Lock()
+	delete(s.active, userID)
+	s.activeMu.Unlock()
+}
+
+func (s *authService) ListUsers(ctx context.Context, filter repository.UserFilter) ([]model.User, int64, error) {
+	users, total, err := s.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, errors.NewDatabaseError("failed to list users: %v", err)
+	}
+	return users, total, nil
+}
+
+func (s *authService) GetUser(ctx context.Context, userID uint) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+	return user, nil
+}
+
+func (s *authService) SetUserActive(ctx context.Context, actor *types.JWTClaims, userID uint, active bool) (*model.User, error) {
+	if !active && actor.UserID == userID {
+		return nil, errors.NewValidationError("you cannot deactivate your own account")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+	if user.Active == active {
+		return user, nil
+	}
+
+	user.Active = active
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, errors.NewDatabaseError("failed to update user: %v", err)
+	}
+	s.forgetActive(user.ID)
 
+	action := model.AuditActionUserReactivated
+	if !active {
+		s.endSessions(ctx, user.ID)
+		action = model.AuditActionUserDeactivated
+	}
+	securityLogger(ctx).Info("user activation changed",
+		zap.String("username", user.Username),
+		zap.Bool("active", active),
+		zap.String("actor", actor.Username),
+	)
+	s.audit.Record(ctx, AuditEntry{
+		ActorID:    &actor.UserID,
+		Actor:      actor.Username,
+		Action:     action,
+		TargetType: model.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(user.ID), 10),
+		Before:     map[string]bool{"active": !active},
+		After:      map[string]bool{"active": active},
+	})
 	return user, nil
+}
+
+// AdminResetPassword ends every session when it sets the password itself.
+// A mailed token leaves the current password working until it is redeemed.
+func (s *authService) AdminResetPassword(ctx context.Context, userID uint, input AdminResetPasswordInput) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return errors.NewNotFoundError("user not found")
+	}
+
+	if input.Password == "" {
+		if err := s.sendPasswordReset(ctx, user); err != nil {
+			return fmt.Errorf("failed to send password reset email: %v", err)
+		}
+		s.recordAdminPasswordReset(ctx, user, "email")
+		return nil
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	user.Password = string(hashedPassword)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return errors.NewDatabaseError("failed to update password: %v", err)
+	}
+
+	s.endSessions(ctx, user.ID)
+	securityLogger(ctx).Info("password reset by an admin", zap.String("username", user.Username))
+	s.recordAdminPasswordReset(ctx, user, "set")
+	return nil
+}
+
+func (s *authService) recordAdminPasswordReset(ctx context.Context, user *model.User, method string) {
+	s.audit.Record(ctx, AuditEntry{
+		Action:     model.AuditActionUserPasswordReset,
+		TargetType: model.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(user.ID), 10),
+		After:      map[string]string{"method": method},
+	})
+}
+
+func (s *authService) DeleteUser(ctx context.Context, actor *types.JWTClaims, userID uint) error {
+	if actor.UserID == userID {
+		return errors.NewValidationError("you cannot delete your own account")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return errors.NewNotFoundError("user not found")
+	}
+
+	if err := s.userRepo.Delete(ctx, user.ID); err != nil {
+		return errors.NewDatabaseError("failed to delete user: %v", err)
+	}
+	s.forgetActive(user.ID)
+
+	s.endSessions(ctx, user.ID)
+	securityLogger(ctx).Info("user deleted", zap.String("username", user.Username), zap.String("actor", actor.Username))
+	s.audit.Record(ctx, AuditEntry{
+		ActorID:    &actor.UserID,
+		Actor:      actor.Username,
+		Action:     model.AuditActionUserDeleted,
+		TargetType: model.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(user.ID), 10),
+		Before:     user,
+	})
+	return nil
+}
+
+// generateOpaqueToken returns 256 bits of randomness, URL-safe encoded
+func generateOpaqueToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken is used for high-entropy tokens only, where a fast hash is
+// enough; passwords keep using bcrypt.
+func hashToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
 } 
\ No newline at end of file
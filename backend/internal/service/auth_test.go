@@ -0,0 +1,686 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vietgs03/translate/backend/internal/config"
+	"github.com/vietgs03/translate/backend/internal/lockout"
+	"github.com/vietgs03/translate/backend/internal/mail"
+	"github.com/vietgs03/translate/backend/internal/model"
+	"github.com/vietgs03/translate/backend/internal/oidc"
+	"github.com/vietgs03/translate/backend/internal/oidc/oidctest"
+	"github.com/vietgs03/translate/backend/internal/repository"
+	"github.com/vietgs03/translate/backend/internal/token"
+	"github.com/vietgs03/translate/backend/internal/types"
+	"gorm.io/gorm"
+)
+
+// memoryUserRepo is a minimal in-memory UserRepository
+type memoryUserRepo struct {
+	mu     sync.Mutex
+	nextID uint
+	users  map[uint]model.User
+}
+
+func newMemoryUserRepo() *memoryUserRepo {
+	return &memoryUserRepo{users: make(map[uint]model.User)}
+}
+
+func (r *memoryUserRepo) Create(ctx context.Context, user *model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	user.ID = r.nextID
+	user.Active = true // column default, as GORM applies it for the zero value
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *memoryUserRepo) GetByID(ctx context.Context, id uint) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &user, nil
+}
+
+func (r *memoryUserRepo) find(match func(model.User) bool) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if match(user) {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryUserRepo) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	return r.find(func(u model.User) bool { return u.Username == username })
+}
+
+func (r *memoryUserRepo) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	return r.find(func(u model.User) bool { return u.Email == email })
+}
+
+func (r *memoryUserRepo) GetByOIDCSubject(ctx context.Context, issuer, subject string) (*model.User, error) {
+	return r.find(func(u model.User) bool { return u.OIDCIssuer == issuer && u.OIDCSubject == subject })
+}
+
+func (r *memoryUserRepo) Update(ctx context.Context, user *model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *memoryUserRepo) CountByRole(ctx context.Context, role string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, user := range r.users {
+		if user.Role == role {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryUserRepo) List(ctx context.Context, filter repository.UserFilter) ([]model.User, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var matches []model.User
+	for _, user := range r.users {
+		if filter.Query != "" && !strings.Contains(user.Username, filter.Query) && !strings.Contains(user.Email, filter.Query) {
+			continue
+		}
+		if filter.Role != "" && user.Role != filter.Role {
+			continue
+		}
+		if filter.Active != nil && user.Active != *filter.Active {
+			continue
+		}
+		matches = append(matches, user)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+
+	total := int64(len(matches))
+	start := (filter.Page - 1) * filter.PageSize
+	if start >= len(matches) {
+		return nil, total, nil
+	}
+	end := start + filter.PageSize
+	if end > len(matches) {
+		end = len(matches)
+	}
+	return matches[start:end], total, nil
+}
+
+func (r *memoryUserRepo) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.users, id)
+	return nil
+}
+
+// memoryRefreshTokenRepo is a minimal in-memory RefreshTokenRepository
+type memoryRefreshTokenRepo struct {
+	mu     sync.Mutex
+	nextID uint
+	tokens map[uint]*model.RefreshToken
+}
+
+func newMemoryRefreshTokenRepo() *memoryRefreshTokenRepo {
+	return &memoryRefreshTokenRepo{tokens: make(map[uint]*model.RefreshToken)}
+}
+
+func (r *memoryRefreshTokenRepo) Create(ctx context.Context, t *model.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	t.ID = r.nextID
+	stored := *t
+	r.tokens[t.ID] = &stored
+	return nil
+}
+
+func (r *memoryRefreshTokenRepo) GetByHash(ctx context.Context, hash string) (*model.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.TokenHash == hash {
+			found := *t
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("refresh token not found")
+}
+
+func (r *memoryRefreshTokenRepo) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tokens[id]
+	if !ok || t.UsedAt != nil || t.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	t.UsedAt = &now
+	return true, nil
+}
+
+func (r *memoryRefreshTokenRepo) RevokeFamily(ctx context.Context, familyID string) error {
+	return r.revoke(func(t *model.RefreshToken) bool { return t.FamilyID == familyID })
+}
+
+func (r *memoryRefreshTokenRepo) RevokeAllForUser(ctx context.Context, userID uint) error {
+	return r.revoke(func(t *model.RefreshToken) bool { return t.UserID == userID })
+}
+
+func (r *memoryRefreshTokenRepo) revoke(match func(*model.RefreshToken) bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, t := range r.tokens {
+		if match(t) && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// memoryUserTokenRepo is a minimal in-memory UserTokenRepository
+type memoryUserTokenRepo struct {
+	mu     sync.Mutex
+	nextID uint
+	tokens map[uint]*model.UserToken
+}
+
+func newMemoryUserTokenRepo() *memoryUserTokenRepo {
+	return &memoryUserTokenRepo{tokens: make(map[uint]*model.UserToken)}
+}
+
+func (r *memoryUserTokenRepo) Create(ctx context.Context, t *model.UserToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	t.ID = r.nextID
+	stored := *t
+	r.tokens[t.ID] = &stored
+	return nil
+}
+
+func (r *memoryUserTokenRepo) GetByHash(ctx context.Context, purpose, hash string) (*model.UserToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.Purpose == purpose && t.TokenHash == hash {
+			found := *t
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("token not found")
+}
+
+func (r *memoryUserTokenRepo) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tokens[id]
+	if !ok || t.UsedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	t.UsedAt = &now
+	return true, nil
+}
+
+func (r *memoryUserTokenRepo) InvalidateForUser(ctx context.Context, userID uint, purpose string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.Purpose == purpose && t.UsedAt == nil {
+			t.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+// recordingMailer keeps sent messages instead of delivering them
+type recordingMailer struct {
+	mu   sync.Mutex
+	sent []mail.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// lastToken extracts the token from the last message sent
+func (m *recordingMailer) lastToken(t *testing.T) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	require.NotEmpty(t, m.sent)
+	body := m.sent[len(m.sent)-1].Body
+	match := mailedToken.FindStringSubmatch(body)
+	require.Len(t, match, 2, body)
+	return match[1]
+}
+
+var mailedToken = regexp.MustCompile(`(?m)(?:token=|^)([A-Za-z0-9_-]{43})$`)
+
+// testLoginPolicy locks after three failures without slowing tests down
+var testLoginPolicy = lockout.Policy{
+	UserThreshold: 3,
+	IPThreshold:   100,
+	Window:        time.Minute,
+	Duration:      time.Minute,
+}
+
+func testJWTConfig() config.JWTConfig {
+	return config.JWTConfig{
+		Algorithm:        token.AlgorithmEdDSA,
+		Issuer:           "test-issuer",
+		Audience:         "test-audience",
+		AccessExpiresIn:  15,
+		RefreshExpiresIn: 24,
+		KeyRotation:      24,
+	}
+}
+
+func newTestKeySet(t *testing.T) *token.KeySet {
+	jwtConfig := testJWTConfig()
+	keys, err := token.NewKeySet(context.Background(), token.NewMemoryKeyStore(), &jwtConfig)
+	require.NoError(t, err)
+	return keys
+}
+
+func newTestAuthService(t *testing.T) (*authService, *memoryUserRepo) {
+	svc, users, _ := newTestAuthServiceWithMail(t, config.AuthConfig{})
+	return svc, users
+}
+
+func newTestAuthServiceWithMail(t *testing.T, authConfig config.AuthConfig) (*authService, *memoryUserRepo, *recordingMailer) {
+	users := newMemoryUserRepo()
+	mailer := &recordingMailer{}
+	authConfig.VerificationTTL = 1
+	authConfig.PasswordResetTTL = 10
+	svc := NewAuthService(users, newMemoryRefreshTokenRepo(), newMemoryUserTokenRepo(), newTestKeySet(t),
+		token.NewMemoryDenylist(), lockout.NewMemoryGuard(testLoginPolicy), mailer, testJWTConfig(), authConfig, nil, nil,
+		NewAuditService(newMemoryAuditRepo())).(*authService)
+
+	_, err := svc.Register(context.Background(), RegisterInput{Username: "alice", Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	return svc, users, mailer
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("RotatesOnUse", func(t *testing.T) {
+		svc, _ := newTestAuthService(t)
+		login, err := svc.Login(ctx, LoginInput{Username: "alice", Password: "password123"})
+		require.NoError(t, err)
+		assert.Equal(t, 15*60, login.ExpiresIn)
+
+		refreshed, err := svc.Refresh(ctx, login.RefreshToken)
+		require.NoError(t, err)
+		assert.NotEqual(t, login.RefreshToken, refreshed.RefreshToken)
+
+		_, err = svc.Refresh(ctx, refreshed.RefreshToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ReuseRevokesFamily", func(t *testing.T) {
+		svc, _ := newTestAuthService(t)
+		login, err := svc.Login(ctx, LoginInput{Username: "alice", Password: "password123"})
+		require.NoError(t, err)
+
+		refreshed, err := svc.Refresh(ctx, login.RefreshToken)
+		require.NoError(t, err)
+
+		// Replaying the spent token must fail and take the live one with it
+		_, err = svc.Refresh(ctx, login.RefreshToken)
+		assert.Error(t, err)
+		_, err = svc.Refresh(ctx, refreshed.RefreshToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("LogoutRevokesFamily", func(t *testing.T) {
+		svc, _ := newTestAuthService(t)
+		login, err := svc.Login(ctx, LoginInput{Username: "alice", Password: "password123"})
+		require.NoError(t, err)
+
+		parsed, err := svc.ValidateToken(login.Token)
+		require.NoError(t, err)
+		claims := parsed.Claims.(*types.JWTClaims)
+
+		require.NoError(t, svc.Logout(ctx, claims, login.RefreshToken))
+
+		revoked, err := svc.denylist.IsRevoked(ctx, claims.ID)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+
+		_, err = svc.Refresh(ctx, login.RefreshToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("LogoutRejectsAPIKeys", func(t *testing.T) {
+		svc, _ := newTestAuthService(t)
+		login, err := svc.Login(ctx, LoginInput{Username: "alice", Password: "password123"})
+		require.NoError(t, err)
+
+		parsed, err := svc.ValidateToken(login.Token)
+		require.NoError(t, err)
+		keyPrincipal := *parsed.Claims.(*types.JWTClaims)
+		keyPrincipal.ID = ""
+		keyPrincipal.APIKeyID = 7
+
+		assert.Error(t, svc.Logout(ctx, &keyPrincipal, login.RefreshToken))
+
+		// The owner's session survives
+		_, err = svc.Refresh(ctx, login.RefreshToken)
+		assert.NoError(t, err)
+	})
+}
+
+func TestRoleChangeRevokesAccessTokens(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestAuthService(t)
+	claimsOf := func(token string) *types.JWTClaims {
+		parsed, err := svc.ValidateToken(token)
+		require.NoError(t, err)
+		return parsed.Claims.(*types.JWTClaims)
+	}
+	revoked := func(claims *types.JWTClaims) bool {
+		version, err := svc.denylist.TokenVersion(ctx, claims.UserID)
+		require.NoError(t, err)
+		return claims.TokenVersion < version
+	}
+
+	login, err := svc.Login(ctx, LoginInput{Username: "alice", Password: "password123"})
+	require.NoError(t, err)
+	before := claimsOf(login.Token)
+	assert.False(t, revoked(before))
+
+	_, err = svc.UpdateRole(ctx, before.UserID, "reader")
+	require.NoError(t, err)
+	assert.True(t, revoked(before))
+
+	// A token issued right after, within the same second, stays valid
+	refreshed, err := svc.Refresh(ctx, login.RefreshToken)
+	require.NoError(t, err)
+	after := claimsOf(refreshed.Token)
+	assert.False(t, revoked(after))
+	assert.Equal(t, "reader", after.Role)
+	assert.Zero(t, after.IssuedAt.Nanosecond(), "issue times keep whole seconds")
+}
+
+func TestLoginWithOIDC(t *testing.T) {
+	ctx := context.Background()
+	idp, err := oidctest.NewServer("translate", "secret")
+	require.NoError(t, err)
+	defer idp.Close()
+
+	provider, err := oidc.NewProvider(ctx, &config.OIDCConfig{
+		IssuerURL:    idp.URL,
+		ClientID:     "translate",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:8080/api/v1/auth/oidc/callback",
+		RoleClaim:    "groups",
+		RoleMapping:  map[string]string{"translate-admins": "admin"},
+		DefaultRole:  "translator",
+	})
+	require.NoError(t, err)
+
+	users := newMemoryUserRepo()
+	svc := NewAuthService(users, newMemoryRefreshTokenRepo(), newMemoryUserTokenRepo(), newTestKeySet(t),
+		token.NewMemoryDenylist(), lockout.NewMemoryGuard(testLoginPolicy), &recordingMailer{}, testJWTConfig(), config.AuthConfig{}, provider, oidc.NewMemoryStateStore(),
+		NewAuditService(newMemoryAuditRepo()))
+
+	// follow takes the browser through the provider and returns the callback query
+	follow := func(authURL string) url.Values {
+		client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}}
+		resp, err := client.Get(authURL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+
+		callback, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+		return callback.Query()
+	}
+	login := func() url.Values {
+		authURL, err := svc.OIDCAuthURL(ctx)
+		require.NoError(t, err)
+		return follow(authURL)
+	}
+
+	var first *types.LoginResponse
+
+	t.Run("ProvisionsUser", func(t *testing.T) {
+		idp.SetClaims(map[string]interface{}{"preferred_username": "sso-alice", "email": "alice@corp.example", "email_verified": true})
+		callback := login()
+
+		tokens, err := svc.LoginWithOIDC(ctx, callback.Get("state"), callback.Get("code"))
+		require.NoError(t, err)
+		assert.NotEmpty(t, tokens.Token)
+		first = tokens
+
+		user, err := users.GetByUsername(ctx, "sso-alice")
+		require.NoError(t, err)
+		assert.Equal(t, "translator", user.Role)
+		assert.Equal(t, idp.URL, user.OIDCIssuer)
+	})
+
+	t.Run("MapsRoleOnLogin", func(t *testing.T) {
+		idp.SetClaims(map[string]interface{}{"preferred_username": "sso-alice", "groups": []interface{}{"staff", "translate-admins"}})
+		callback := login()
+
+		_, err := svc.LoginWithOIDC(ctx, callback.Get("state"), callback.Get("code"))
+		require.NoError(t, err)
+
+		user, err := users.GetByUsername(ctx, "sso-alice")
+		require.NoError(t, err)
+		assert.Equal(t, "admin", user.Role)
+		assert.Len(t, users.users, 1)
+
+		// Sessions holding the old role end with the change
+		require.NotNil(t, first)
+		_, err = svc.Refresh(ctx, first.RefreshToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("LinksExistingAccountOnlyExplicitly", func(t *testing.T) {
+		_, err := svc.Register(ctx, RegisterInput{Username: "carol", Email: "carol@corp.example", Password: "password123"})
+		require.NoError(t, err)
+		idp.SetClaims(map[string]interface{}{"sub": "user-2", "preferred_username": "carol", "email": "carol@corp.example", "email_verified": true})
+
+		// A verified email alone doesn't take over the account
+		callback := login()
+		_, err = svc.LoginWithOIDC(ctx, callback.Get("state"), callback.Get("code"))
+		assert.Error(t, err)
+
+		session, err := svc.Login(ctx, LoginInput{Username: "carol", Password: "password123"})
+		require.NoError(t, err)
+		parsed, err := svc.ValidateToken(session.Token)
+		require.NoError(t, err)
+		claims := parsed.Claims.(*types.JWTClaims)
+
+		keyPrincipal := *claims
+		keyPrincipal.APIKeyID = 3
+		_, err = svc.OIDCLinkURL(ctx, &keyPrincipal)
+		assert.Error(t, err, "API keys can't link")
+
+		authURL, err := svc.OIDCLinkURL(ctx, claims)
+		require.NoError(t, err)
+		callback = follow(authURL)
+		_, err = svc.LoginWithOIDC(ctx, callback.Get("state"), callback.Get("code"))
+		require.NoError(t, err)
+
+		// From now on single sign-on logs into the linked account
+		callback = login()
+		_, err = svc.LoginWithOIDC(ctx, callback.Get("state"), callback.Get("code"))
+		require.NoError(t, err)
+		carol, err := users.GetByUsername(ctx, "carol")
+		require.NoError(t, err)
+		assert.Equal(t, idp.URL, carol.OIDCIssuer)
+		assert.Len(t, users.users, 2)
+	})
+
+	t.Run("RejectsReplayedState", func(t *testing.T) {
+		callback := login()
+		_, err := svc.LoginWithOIDC(ctx, callback.Get("state"), callback.Get("code"))
+		require.NoError(t, err)
+
+		_, err = svc.LoginWithOIDC(ctx, callback.Get("state"), callback.Get("code"))
+		assert.Error(t, err)
+	})
+}
+
+func TestEmailVerification(t *testing.T) {
+	ctx := context.Background()
+	svc, _, mailer := newTestAuthServiceWithMail(t, config.AuthConfig{RequireEmailVerification: true})
+	login := LoginInput{Username: "alice", Password: "password123"}
+
+	_, err := svc.Login(ctx, login)
+	assert.Error(t, err, "unverified users can't log in")
+
+	verification := mailer.lastToken(t)
+	require.NoError(t, svc.VerifyEmail(ctx, verification))
+	assert.Error(t, svc.VerifyEmail(ctx, verification), "tokens are single-use")
+
+	_, err = svc.Login(ctx, login)
+	assert.NoError(t, err)
+}
+
+func TestPasswordReset(t *testing.T) {
+	ctx := context.Background()
+	svc, _, mailer := newTestAuthServiceWithMail(t, config.AuthConfig{})
+
+	session, err := svc.Login(ctx, LoginInput{Username: "alice", Password: "password123"})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.ForgotPassword(ctx, "alice@example.com"))
+	require.NoError(t, svc.ForgotPassword(ctx, "nobody@example.com"), "unknown addresses look the same")
+	require.Eventually(t, func() bool {
+		mailer.mu.Lock()
+		defer mailer.mu.Unlock()
+		return len(mailer.sent) == 2 // verification + reset
+	}, time.Second, 10*time.Millisecond)
+
+	reset := mailer.lastToken(t)
+	require.NoError(t, svc.ResetPassword(ctx, ResetPasswordInput{Token: reset, Password: "new-password"}))
+	assert.Error(t, svc.ResetPassword(ctx, ResetPasswordInput{Token: reset, Password: "other-password"}))
+
+	_, err = svc.Login(ctx, LoginInput{Username: "alice", Password: "password123"})
+	assert.Error(t, err)
+	_, err = svc.Login(ctx, LoginInput{Username: "alice", Password: "new-password"})
+	assert.NoError(t, err)
+
+	_, err = svc.Refresh(ctx, session.RefreshToken)
+	assert.Error(t, err, "a reset ends existing sessions")
+
+	// A link sent before the account was disabled no longer works
+	require.NoError(t, svc.ForgotPassword(ctx, "alice@example.com"))
+	require.Eventually(t, func() bool {
+		mailer.mu.Lock()
+		defer mailer.mu.Unlock()
+		return len(mailer.sent) == 3
+	}, time.Second, 10*time.Millisecond)
+	alice, err := svc.userRepo.GetByUsername(ctx, "alice")
+	require.NoError(t, err)
+	alice.Active = false
+	require.NoError(t, svc.userRepo.Update(ctx, alice))
+	assert.Error(t, svc.ResetPassword(ctx, ResetPasswordInput{Token: mailer.lastToken(t), Password: "third-password"}))
+}
+
+func TestLoginLockout(t *testing.T) {
+	ctx := context.Background()
+	svc, users := newTestAuthService(t)
+	wrong := LoginInput{Username: "alice", Password: "wrong", IP: "192.0.2.1"}
+	right := LoginInput{Username: "alice", Password: "password123", IP: "192.0.2.1"}
+
+	var failures []error
+	for i := 0; i < 3; i++ {
+		_, err := svc.Login(ctx, wrong)
+		failures = append(failures, err)
+	}
+	_, lockedErr := svc.Login(ctx, right)
+	_, unknownErr := svc.Login(ctx, LoginInput{Username: "nobody", Password: "wrong", IP: "192.0.2.1"})
+
+	// Wrong password, lockout and unknown user look the same
+	for _, err := range append(failures, lockedErr, unknownErr) {
+		require.Error(t, err)
+		assert.Equal(t, "invalid credentials", err.Error())
+	}
+
+	alice, err := users.GetByUsername(ctx, "alice")
+	require.NoError(t, err)
+	require.NoError(t, svc.UnlockUser(ctx, alice.ID))
+
+	_, err = svc.Login(ctx, right)
+	assert.NoError(t, err)
+}
+
+func TestUserDeactivation(t *testing.T) {
+	ctx := context.Background()
+	svc, users := newTestAuthService(t)
+	admin := &types.JWTClaims{UserID: 99, Username: "root"}
+
+	alice, err := users.GetByUsername(ctx, "alice")
+	require.NoError(t, err)
+	session, err := svc.Login(ctx, LoginInput{Username: "alice", Password: "password123"})
+	require.NoError(t, err)
+
+	_, err = svc.SetUserActive(ctx, &types.JWTClaims{UserID: alice.ID}, alice.ID, false)
+	assert.Error(t, err, "users cannot deactivate themselves")
+
+	active, err := svc.IsActive(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.True(t, active)
+
+	_, err = svc.SetUserActive(ctx, admin, alice.ID, false)
+	require.NoError(t, err)
+
+	active, err = svc.IsActive(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.False(t, active)
+	_, err = svc.Login(ctx, LoginInput{Username: "alice", Password: "password123"})
+	assert.EqualError(t, err, "account is disabled")
+	_, err = svc.Refresh(ctx, session.RefreshToken)
+	assert.Error(t, err, "deactivation ends existing sessions")
+
+	inactive := false
+	listed, total, err := svc.ListUsers(ctx, repository.UserFilter{Active: &inactive, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "alice", listed[0].Username)
+
+	_, err = svc.SetUserActive(ctx, admin, alice.ID, true)
+	require.NoError(t, err)
+	_, err = svc.Login(ctx, LoginInput{Username: "alice", Password: "password123"})
+	assert.NoError(t, err)
+
+	require.NoError(t, svc.DeleteUser(ctx, admin, alice.ID))
+	active, err = svc.IsActive(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.False(t, active, "deleted users are not active")
+}
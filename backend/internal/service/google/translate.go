@@ -2,15 +2,21 @@ package google
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/google/generative-ai-go/genai"
+	"github.com/googleapis/gax-go/v2/apierror"
 	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"github.com/vietgs03/translate/backend/internal/language"
 	"github.com/vietgs03/translate/backend/internal/service/translator"
 )
 
 var _ translator.Translator = (*TranslateService)(nil) // Verify interface implementation
+var _ translator.Prober = (*TranslateService)(nil)
 
 type TranslateService struct {
 	client *genai.Client
@@ -28,6 +34,15 @@ func NewTranslateService(apiKey string) (*TranslateService, error) {
 	}, nil
 }
 
+func (s *TranslateService) Name() string {
+	return "gemini"
+}
+
+// Coverage is unrestricted, Gemini translates between any languages
+func (s *TranslateService) Coverage() language.Coverage {
+	return language.Coverage{}
+}
+
 func (s *TranslateService) Translate(ctx context.Context, text, sourceLang, targetLang string) (string, error) {
 	model := s.client.GenerativeModel("gemini-pro")
 
@@ -40,11 +55,11 @@ func (s *TranslateService) Translate(ctx context.Context, text, sourceLang, targ
 
 	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
 	if err != nil {
-		return "", fmt.Errorf("failed to generate translation: %v", err)
+		return "", fmt.Errorf("failed to generate translation: %w", classify(err))
 	}
 
 	if len(resp.Candidates) == 0 {
-		return "", fmt.Errorf("no translation generated")
+		return "", fmt.Errorf("no translation generated: %w", translator.ErrEmptyTranslation)
 	}
 
 	// Get the response text
@@ -57,12 +72,41 @@ func (s *TranslateService) Translate(ctx context.Context, text, sourceLang, targ
 
 	translation = strings.TrimSpace(translation)
 	if translation == "" {
-		return "", fmt.Errorf("empty translation received")
+		return "", fmt.Errorf("empty translation received: %w", translator.ErrEmptyTranslation)
 	}
 
 	return translation, nil
 }
 
+// Probe reads the metadata of the model used for translating, which
+// generates nothing
+func (s *TranslateService) Probe(ctx context.Context) error {
+	if _, err := s.client.GenerativeModel("gemini-pro").Info(ctx); err != nil {
+		return fmt.Errorf("failed to reach Gemini: %w", classify(err))
+	}
+	return nil
+}
+
+// classify marks API errors worth telling apart with the translator errors.
+// Gemini answers over gRPC or HTTP, so both kinds of status are checked.
+func classify(err error) error {
+	var apiErr *apierror.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	code := codes.Unknown
+	if status := apiErr.GRPCStatus(); status != nil {
+		code = status.Code()
+	}
+	switch {
+	case code == codes.ResourceExhausted || apiErr.HTTPCode() == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %w", translator.ErrRateLimited, err)
+	case code == codes.Unavailable || apiErr.HTTPCode() >= http.StatusInternalServerError:
+		return fmt.Errorf("%w: %w", translator.ErrUnavailable, err)
+	}
+	return err
+}
+
 func (s *TranslateService) Close() error {
 	return s.client.Close()
 } 
\ No newline at end of file
@@ -0,0 +1,417 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/model"
+	"github.com/vietgs03/translate/backend/internal/repository"
+	"github.com/vietgs03/translate/backend/internal/types"
+)
+
+// Scope is the project a request works in. The zero Scope is the global
+// translation memory, where only the caller's global permissions apply.
+type Scope struct {
+	Project *model.Project
+	// Role is the caller's effective role in Project
+	Role string
+}
+
+// GlobalScope is the shared translation memory outside any project
+var GlobalScope = Scope{}
+
+func (s Scope) Tenant() repository.Tenant {
+	if s.Project == nil {
+		return repository.GlobalTenant
+	}
+	return repository.ProjectTenant(s.Project.ID)
+}
+
+func (s Scope) ProjectID() uint {
+	return s.Tenant().ProjectID
+}
+
+// fallsBackToGlobal reports whether lookups that miss in the project may be
+// answered from the global translation memory
+func (s Scope) fallsBackToGlobal() bool {
+	return s.Project != nil && s.Project.UseGlobalTM
+}
+
+type ProjectService interface {
+	CreateOrganization(ctx context.Context, actor *types.JWTClaims, input CreateOrganizationInput) (*model.Organization, error)
+	ListOrganizations(ctx context.Context, actor *types.JWTClaims) ([]model.Organization, error)
+	ListOrganizationMembers(ctx context.Context, actor *types.JWTClaims, orgID uint) ([]model.OrganizationMember, error)
+	AddOrganizationMember(ctx context.Context, actor *types.JWTClaims, orgID uint, input OrganizationMemberInput) (*model.OrganizationMember, error)
+	RemoveOrganizationMember(ctx context.Context, actor *types.JWTClaims, orgID, userID uint) error
+
+	CreateProject(ctx context.Context, actor *types.JWTClaims, orgID uint, input CreateProjectInput) (*model.Project, error)
+	ListProjects(ctx context.Context, actor *types.JWTClaims, orgID uint) ([]model.Project, error)
+	// ResolveProject returns the project with the caller's effective role in
+	// it. Projects the caller cannot see are reported as not found.
+	ResolveProject(ctx context.Context, actor *types.JWTClaims, projectID uint) (*model.Project, string, error)
+	ListProjectMembers(ctx context.Context, actor *types.JWTClaims, projectID uint) ([]model.ProjectMember, error)
+	AddProjectMember(ctx context.Context, actor *types.JWTClaims, projectID uint, input ProjectMemberInput) (*model.ProjectMember, error)
+	RemoveProjectMember(ctx context.Context, actor *types.JWTClaims, projectID, userID uint) error
+}
+
+type CreateOrganizationInput struct {
+	Name string `json:"name" validate:"required,max=100"`
+	Slug string `json:"slug" validate:"required,max=50,alphanum"`
+}
+
+type OrganizationMemberInput struct {
+	UserID uint   `json:"user_id" validate:"required"`
+	Role   string `json:"role" validate:"required,oneof=owner member"`
+}
+
+type CreateProjectInput struct {
+	Name string `json:"name" validate:"required,max=100"`
+	Slug string `json:"slug" validate:"required,max=50,alphanum"`
+	// UseGlobalTM defaults to true
+	UseGlobalTM *bool `json:"use_global_tm"`
+}
+
+type ProjectMemberInput struct {
+	UserID uint   `json:"user_id" validate:"required"`
+	Role   string `json:"role" validate:"required,oneof=viewer translator maintainer"`
+}
+
+type projectService struct {
+	repo     repository.ProjectRepository
+	userRepo repository.UserRepository
+	rbac     RBACService
+	audit    AuditService
+}
+
+func NewProjectService(repo repository.ProjectRepository, userRepo repository.UserRepository, rbac RBACService, auditService AuditService) ProjectService {
+	return &projectService{
+		repo:     repo,
+		userRepo: userRepo,
+		rbac:     rbac,
+		audit:    auditService,
+	}
+}
+
+func (s *projectService) CreateOrganization(ctx context.Context, actor *types.JWTClaims, input CreateOrganizationInput) (*model.Organization, error) {
+	if err := s.requireFullCeiling(ctx, actor); err != nil {
+		return nil, err
+	}
+	slug := strings.ToLower(input.Slug)
+	if _, err := s.repo.GetOrganizationBySlug(ctx, slug); err == nil {
+		return nil, errors.NewConflictError("organization %q already exists", slug)
+	}
+
+	org := model.Organization{Name: input.Name, Slug: slug}
+	if err := s.repo.CreateOrganization(ctx, &org, actor.UserID); err != nil {
+		return nil, errors.NewDatabaseError("failed to create organization: %v", err)
+	}
+	return &org, nil
+}
+
+func (s *projectService) ListOrganizations(ctx context.Context, actor *types.JWTClaims) ([]model.Organization, error) {
+	orgs, err := s.repo.ListOrganizationsByUser(ctx, actor.UserID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("failed to list organizations: %v", err)
+	}
+	return orgs, nil
+}
+
+func (s *projectService) ListOrganizationMembers(ctx context.Context, actor *types.JWTClaims, orgID uint) ([]model.OrganizationMember, error) {
+	if _, err := s.organizationRole(ctx, actor, orgID); err != nil {
+		return nil, err
+	}
+	members, err := s.repo.ListOrganizationMembers(ctx, orgID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("failed to list organization members: %v", err)
+	}
+	return members, nil
+}
+
+func (s *projectService) AddOrganizationMember(ctx context.Context, actor *types.JWTClaims, orgID uint, input OrganizationMemberInput) (*model.OrganizationMember, error) {
+	if err := s.requireOwner(ctx, actor, orgID); err != nil {
+		return nil, err
+	}
+	if _, err := s.userRepo.GetByID(ctx, input.UserID); err != nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+	if input.Role != model.OrganizationRoleOwner {
+		if err := s.keepAnOwner(ctx, orgID, input.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	member := model.OrganizationMember{OrganizationID: orgID, UserID: input.UserID, Role: input.Role}
+	if err := s.repo.SaveOrganizationMember(ctx, &member); err != nil {
+		return nil, errors.NewDatabaseError("failed to save organization member: %v", err)
+	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID:    &actor.UserID,
+		Actor:      actor.Username,
+		Action:     model.AuditActionOrgMemberAdded,
+		TargetType: model.AuditTargetOrganization,
+		TargetID:   strconv.FormatUint(uint64(orgID), 10),
+		After:      member,
+	})
+	return &member, nil
+}
+
+func (s *projectService) RemoveOrganizationMember(ctx context.Context, actor *types.JWTClaims, orgID, userID uint) error {
+	if err := s.requireOwner(ctx, actor, orgID); err != nil {
+		return err
+	}
+	member, err := s.repo.GetOrganizationMember(ctx, orgID, userID)
+	if err != nil {
+		return errors.NewNotFoundError("organization member not found")
+	}
+	if err := s.keepAnOwner(ctx, orgID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteOrganizationMember(ctx, orgID, userID); err != nil {
+		return errors.NewDatabaseError("failed to remove organization member: %v", err)
+	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID:    &actor.UserID,
+		Actor:      actor.Username,
+		Action:     model.AuditActionOrgMemberRemoved,
+		TargetType: model.AuditTargetOrganization,
+		TargetID:   strconv.FormatUint(uint64(orgID), 10),
+		Before:     member,
+	})
+	return nil
+}
+
+func (s *projectService) CreateProject(ctx context.Context, actor *types.JWTClaims, orgID uint, input CreateProjectInput) (*model.Project, error) {
+	if err := s.requireOwner(ctx, actor, orgID); err != nil {
+		return nil, err
+	}
+	slug := strings.ToLower(input.Slug)
+	if _, err := s.repo.GetProjectBySlug(ctx, orgID, slug); err == nil {
+		return nil, errors.NewConflictError("project %q already exists", slug)
+	}
+
+	project := model.Project{OrganizationID: orgID, Name: input.Name, Slug: slug, UseGlobalTM: true}
+	if input.UseGlobalTM != nil {
+		project.UseGlobalTM = *input.UseGlobalTM
+	}
+	if err := s.repo.CreateProject(ctx, &project); err != nil {
+		return nil, errors.NewDatabaseError("failed to create project: %v", err)
+	}
+	return &project, nil
+}
+
+func (s *projectService) ListProjects(ctx context.Context, actor *types.JWTClaims, orgID uint) ([]model.Project, error) {
+	if _, err := s.organizationRole(ctx, actor, orgID); err != nil {
+		return nil, err
+	}
+	projects, err := s.repo.ListProjects(ctx, orgID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("failed to list projects: %v", err)
+	}
+	return projects, nil
+}
+
+func (s *projectService) ResolveProject(ctx context.Context, actor *types.JWTClaims, projectID uint) (*model.Project, string, error) {
+	project, err := s.repo.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, "", errors.NewNotFoundError("project not found")
+	}
+
+	// The effective role is the strongest of the project membership, the
+	// organization membership and platform-wide translation management
+	role := ""
+	if member, err := s.repo.GetProjectMember(ctx, project.ID, actor.UserID); err == nil {
+		role = member.Role
+	}
+	if member, err := s.repo.GetOrganizationMember(ctx, project.OrganizationID, actor.UserID); err == nil {
+		inherited := model.ProjectRoleViewer
+		if member.Role == model.OrganizationRoleOwner {
+			inherited = model.ProjectRoleMaintainer
+		}
+		role = strongerProjectRole(role, inherited)
+	}
+	if role != model.ProjectRoleMaintainer {
+		admin, err := s.rbac.Allows(ctx, actor, model.PermissionTranslationManage)
+		if err != nil {
+			return nil, "", err
+		}
+		if admin {
+			role = model.ProjectRoleMaintainer
+		}
+	}
+
+	if role == "" {
+		return nil, "", errors.NewNotFoundError("project not found")
+	}
+
+	ceiling, err := s.projectRoleCeiling(ctx, actor)
+	if err != nil {
+		return nil, "", err
+	}
+	return project, weakerProjectRole(role, ceiling), nil
+}
+
+// projectRoleCeiling is the strongest project role the caller may act
+// with: callers who can't create translations, read-only keys included,
+// only view, and keys whose scope can't approve don't maintain.
+func (s *projectService) projectRoleCeiling(ctx context.Context, actor *types.JWTClaims) (string, error) {
+	create, err := s.rbac.Allows(ctx, actor, model.PermissionTranslationCreate)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case !create:
+		return model.ProjectRoleViewer, nil
+	case !model.APIKeyScopeAllows(actor.Scope, model.PermissionTranslationApprove):
+		return model.ProjectRoleTranslator, nil
+	}
+	return model.ProjectRoleMaintainer, nil
+}
+
+func (s *projectService) ListProjectMembers(ctx context.Context, actor *types.JWTClaims, projectID uint) ([]model.ProjectMember, error) {
+	if _, _, err := s.ResolveProject(ctx, actor, projectID); err != nil {
+		return nil, err
+	}
+	members, err := s.repo.ListProjectMembers(ctx, projectID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("failed to list project members: %v", err)
+	}
+	return members, nil
+}
+
+func (s *projectService) AddProjectMember(ctx context.Context, actor *types.JWTClaims, projectID uint, input ProjectMemberInput) (*model.ProjectMember, error) {
+	if err := s.requireMaintainer(ctx, actor, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := s.userRepo.GetByID(ctx, input.UserID); err != nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	member := model.ProjectMember{ProjectID: projectID, UserID: input.UserID, Role: input.Role}
+	if err := s.repo.SaveProjectMember(ctx, &member); err != nil {
+		return nil, errors.NewDatabaseError("failed to save project member: %v", err)
+	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID:    &actor.UserID,
+		Actor:      actor.Username,
+		Action:     model.AuditActionProjectMemberAdded,
+		TargetType: model.AuditTargetProject,
+		TargetID:   strconv.FormatUint(uint64(projectID), 10),
+		After:      member,
+	})
+	return &member, nil
+}
+
+func (s *projectService) RemoveProjectMember(ctx context.Context, actor *types.JWTClaims, projectID, userID uint) error {
+	if err := s.requireMaintainer(ctx, actor, projectID); err != nil {
+		return err
+	}
+	member, err := s.repo.GetProjectMember(ctx, projectID, userID)
+	if err != nil {
+		return errors.NewNotFoundError("project member not found")
+	}
+
+	if err := s.repo.DeleteProjectMember(ctx, projectID, userID); err != nil {
+		return errors.NewDatabaseError("failed to remove project member: %v", err)
+	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID:    &actor.UserID,
+		Actor:      actor.Username,
+		Action:     model.AuditActionProjectMemberRemoved,
+		TargetType: model.AuditTargetProject,
+		TargetID:   strconv.FormatUint(uint64(projectID), 10),
+		Before:     member,
+	})
+	return nil
+}
+
+// organizationRole returns the caller's role in the organization. Platform
+// admins act as owners; organizations the caller is not part of are
+// reported as not found.
+func (s *projectService) organizationRole(ctx context.Context, actor *types.JWTClaims, orgID uint) (string, error) {
+	if _, err := s.repo.GetOrganization(ctx, orgID); err != nil {
+		return "", errors.NewNotFoundError("organization not found")
+	}
+	if member, err := s.repo.GetOrganizationMember(ctx, orgID, actor.UserID); err == nil {
+		return member.Role, nil
+	}
+
+	admin, err := s.rbac.Allows(ctx, actor, model.PermissionTranslationManage)
+	if err != nil {
+		return "", err
+	}
+	if admin {
+		return model.OrganizationRoleOwner, nil
+	}
+	return "", errors.NewNotFoundError("organization not found")
+}
+
+func (s *projectService) requireOwner(ctx context.Context, actor *types.JWTClaims, orgID uint) error {
+	role, err := s.organizationRole(ctx, actor, orgID)
+	if err != nil {
+		return err
+	}
+	if role != model.OrganizationRoleOwner {
+		return errors.NewForbiddenError("only organization owners can do this")
+	}
+	return s.requireFullCeiling(ctx, actor)
+}
+
+// requireFullCeiling keeps scoped API keys and read-only roles from
+// managing organizations, which makes them maintainers of every project
+func (s *projectService) requireFullCeiling(ctx context.Context, actor *types.JWTClaims) error {
+	ceiling, err := s.projectRoleCeiling(ctx, actor)
+	if err != nil {
+		return err
+	}
+	if ceiling != model.ProjectRoleMaintainer {
+		return errors.NewForbiddenError("this API key or role cannot manage organizations")
+	}
+	return nil
+}
+
+func (s *projectService) requireMaintainer(ctx context.Context, actor *types.JWTClaims, projectID uint) error {
+	_, role, err := s.ResolveProject(ctx, actor, projectID)
+	if err != nil {
+		return err
+	}
+	if !model.ProjectRoleAtLeast(role, model.ProjectRoleMaintainer) {
+		return errors.NewForbiddenError("only project maintainers can do this")
+	}
+	return nil
+}
+
+// keepAnOwner refuses to demote or remove the last owner of an organization
+func (s *projectService) keepAnOwner(ctx context.Context, orgID, userID uint) error {
+	members, err := s.repo.ListOrganizationMembers(ctx, orgID)
+	if err != nil {
+		return errors.NewDatabaseError("failed to list organization members: %v", err)
+	}
+	for _, member := range members {
+		if member.Role == model.OrganizationRoleOwner && member.UserID != userID {
+			return nil
+		}
+	}
+	for _, member := range members {
+		if member.UserID == userID && member.Role == model.OrganizationRoleOwner {
+			return errors.NewValidationError("an organization needs at least one owner")
+		}
+	}
+	return nil
+}
+
+func strongerProjectRole(a, b string) string {
+	if model.ProjectRoleAtLeast(a, b) {
+		return a
+	}
+	return b
+}
+
+func weakerProjectRole(a, b string) string {
+	if model.ProjectRoleAtLeast(a, b) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,487 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/language"
+	"github.com/vietgs03/translate/backend/internal/model"
+	"github.com/vietgs03/translate/backend/internal/repository"
+	"github.com/vietgs03/translate/backend/internal/types"
+)
+
+// permissionCacheTTL bounds how long another replica's role changes take to
+// apply here. Changes made through this instance apply immediately.
+const permissionCacheTTL = 30 * time.Second
+
+type RBACService interface {
+	// HasPermission reports whether the role holds the permission, directly
+	// or through one of its ancestors
+	HasPermission(ctx context.Context, role, permission string) (bool, error)
+	// Allows reports whether the principal holds the permission: its role
+	// must grant it and, for API keys, the key's scope must include it
+	Allows(ctx context.Context, principal *types.JWTClaims, permission string) (bool, error)
+	RoleExists(ctx context.Context, role string) (bool, error)
+	ListRoles(ctx context.Context) ([]RoleDetails, error)
+	CreateRole(ctx context.Context, input CreateRoleInput) (*RoleDetails, error)
+	UpdateRole(ctx context.Context, name string, input UpdateRoleInput) (*RoleDetails, error)
+	DeleteRole(ctx context.Context, name string) error
+	ListPermissions(ctx context.Context) ([]model.Permission, error)
+	CreatePermission(ctx context.Context, input CreatePermissionInput) (*model.Permission, error)
+	ListAssignments(ctx context.Context, userID uint) ([]model.TranslatorAssignment, error)
+	CreateAssignment(ctx context.Context, userID uint, input CreateAssignmentInput) (*model.TranslatorAssignment, error)
+	DeleteAssignment(ctx context.Context, id uint) error
+}
+
+type CreateRoleInput struct {
+	Name        string   `json:"name" validate:"required,max=50"`
+	Description string   `json:"description" validate:"max=255"`
+	Parent      *string  `json:"parent"`
+	Permissions []string `json:"permissions"`
+}
+
+type UpdateRoleInput struct {
+	Description string   `json:"description" validate:"max=255"`
+	Parent      *string  `json:"parent"`
+	Permissions []string `json:"permissions"`
+}
+
+type CreatePermissionInput struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=255"`
+}
+
+// CreateAssignmentInput scopes a translator to a language pair, a category
+// or both
+type CreateAssignmentInput struct {
+	SourceLanguage string `json:"source_language" validate:"omitempty,max=35"`
+	TargetLanguage string `json:"target_language" validate:"omitempty,max=35"`
+	Category       string `json:"category" validate:"omitempty,max=50"`
+	Lead           bool   `json:"lead"`
+}
+
+// RoleDetails is a role with its direct and inherited permissions
+type RoleDetails struct {
+	model.Role
+	Permissions          []string `json:"permissions"`
+	EffectivePermissions []string `json:"effective_permissions"`
+}
+
+type rbacService struct {
+	repo           repository.RoleRepository
+	userRepo       repository.UserRepository
+	assignmentRepo repository.TranslatorAssignmentRepository
+	audit          AuditService
+
+	mu       sync.RWMutex
+	snapshot *roleSnapshot
+}
+
+func NewRBACService(
+	repo repository.RoleRepository,
+	userRepo repository.UserRepository,
+	assignmentRepo repository.TranslatorAssignmentRepository,
+	auditService AuditService,
+) RBACService {
+	return &rbacService{
+		repo:           repo,
+		userRepo:       userRepo,
+		assignmentRepo: assignmentRepo,
+		audit:          auditService,
+	}
+}
+
+// roleSnapshot is the role graph as loaded from the database
+type roleSnapshot struct {
+	loadedAt  time.Time
+	roles     map[string]model.Role
+	direct    map[string][]string
+	effective map[string]map[string]bool
+}
+
+func (s *rbacService) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	snapshot, err := s.load(ctx)
+	if err != nil {
+		return false, err
+	}
+	return snapshot.effective[role][permission], nil
+}
+
+func (s *rbacService) Allows(ctx context.Context, principal *types.JWTClaims, permission string) (bool, error) {
+	if !model.APIKeyScopeAllows(principal.Scope, permission) {
+		return false, nil
+	}
+	return s.HasPermission(ctx, principal.Role, permission)
+}
+
+func (s *rbacService) RoleExists(ctx context.Context, role string) (bool, error) {
+	snapshot, err := s.load(ctx)
+	if err != nil {
+		return false, err
+	}
+	_, ok := snapshot.roles[role]
+	return ok, nil
+}
+
+func (s *rbacService) ListRoles(ctx context.Context) ([]RoleDetails, error) {
+	roles, err := s.repo.ListRoles(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("failed to list roles: %v", err)
+	}
+	snapshot, err := s.reload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]RoleDetails, len(roles))
+	for i, role := range roles {
+		details[i] = snapshot.details(role)
+	}
+	return details, nil
+}
+
+func (s *rbacService) CreateRole(ctx context.Context, input CreateRoleInput) (*RoleDetails, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.NewValidationError("role name is required")
+	}
+	snapshot, err := s.reload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := snapshot.roles[name]; ok {
+		return nil, errors.NewConflictError("role %q already exists", name)
+	}
+
+	role := model.Role{Name: name, Description: input.Description, Parent: input.Parent}
+	permissions, err := s.checkRole(ctx, snapshot, role, input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateRole(ctx, &role, permissions); err != nil {
+		return nil, errors.NewDatabaseError("failed to create role: %v", err)
+	}
+
+	details, err := s.detailsAfterChange(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     model.AuditActionRoleCreated,
+		TargetType: model.AuditTargetRole,
+		TargetID:   role.Name,
+		After:      details,
+	})
+	return details, nil
+}
+
+func (s *rbacService) UpdateRole(ctx context.Context, name string, input UpdateRoleInput) (*RoleDetails, error) {
+	snapshot, err := s.reload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	role, ok := snapshot.roles[name]
+	if !ok {
+		return nil, errors.NewNotFoundError("role not found")
+	}
+	before := snapshot.details(role)
+
+	role.Description = input.Description
+	role.Parent = input.Parent
+	permissions, err := s.checkRole(ctx, snapshot, role, input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateRole(ctx, &role, permissions); err != nil {
+		return nil, errors.NewDatabaseError("failed to update role: %v", err)
+	}
+
+	details, err := s.detailsAfterChange(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     model.AuditActionRoleUpdated,
+		TargetType: model.AuditTargetRole,
+		TargetID:   role.Name,
+		Before:     before,
+		After:      details,
+	})
+	return details, nil
+}
+
+func (s *rbacService) DeleteRole(ctx context.Context, name string) error {
+	snapshot, err := s.reload(ctx)
+	if err != nil {
+		return err
+	}
+	deleted, ok := snapshot.roles[name]
+	if !ok {
+		return errors.NewNotFoundError("role not found")
+	}
+	for _, role := range snapshot.roles {
+		if role.Parent != nil && *role.Parent == name {
+			return errors.NewConflictError("role %q inherits from %q", role.Name, name)
+		}
+	}
+	holders, err := s.userRepo.CountByRole(ctx, name)
+	if err != nil {
+		return errors.NewDatabaseError("failed to count role holders: %v", err)
+	}
+	if holders > 0 {
+		return errors.NewConflictError("%d users still have role %q", holders, name)
+	}
+
+	if err := s.repo.DeleteRole(ctx, name); err != nil {
+		return errors.NewDatabaseError("failed to delete role: %v", err)
+	}
+	s.invalidate()
+	s.audit.Record(ctx, AuditEntry{
+		Action:     model.AuditActionRoleDeleted,
+		TargetType: model.AuditTargetRole,
+		TargetID:   name,
+		Before:     snapshot.details(deleted),
+	})
+	return nil
+}
+
+func (s *rbacService) ListPermissions(ctx context.Context) ([]model.Permission, error) {
+	permissions, err := s.repo.ListPermissions(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("failed to list permissions: %v", err)
+	}
+	return permissions, nil
+}
+
+func (s *rbacService) CreatePermission(ctx context.Context, input CreatePermissionInput) (*model.Permission, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.NewValidationError("permission name is required")
+	}
+	existing, err := s.repo.ListPermissions(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("failed to list permissions: %v", err)
+	}
+	for _, permission := range existing {
+		if permission.Name == name {
+			return nil, errors.NewConflictError("permission %q already exists", name)
+		}
+	}
+
+	permission := model.Permission{Name: name, Description: input.Description}
+	if err := s.repo.CreatePermission(ctx, &permission); err != nil {
+		return nil, errors.NewDatabaseError("failed to create permission: %v", err)
+	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     model.AuditActionPermissionCreated,
+		TargetType: model.AuditTargetPermission,
+		TargetID:   permission.Name,
+		After:      permission,
+	})
+	return &permission, nil
+}
+
+func (s *rbacService) ListAssignments(ctx context.Context, userID uint) ([]model.TranslatorAssignment, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+	assignments, err := s.assignmentRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("failed to list assignments: %v", err)
+	}
+	return assignments, nil
+}
+
+func (s *rbacService) CreateAssignment(ctx context.Context, userID uint, input CreateAssignmentInput) (*model.TranslatorAssignment, error) {
+	if input.SourceLanguage == "" && input.TargetLanguage == "" && input.Category == "" {
+		return nil, errors.NewValidationError("an assignment needs a language or a category")
+	}
+	// Translations store canonical tags, so assignments must too to match them
+	for _, tag := range []*string{&input.SourceLanguage, &input.TargetLanguage} {
+		if *tag == "" {
+			continue
+		}
+		canonical, err := language.Canonicalize(*tag)
+		if err != nil {
+			return nil, errors.NewValidationError("%v", err)
+		}
+		*tag = canonical
+	}
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	assignment := model.TranslatorAssignment{
+		UserID:         userID,
+		SourceLanguage: input.SourceLanguage,
+		TargetLanguage: input.TargetLanguage,
+		Category:       input.Category,
+		Lead:           input.Lead,
+	}
+	if err := s.assignmentRepo.Create(ctx, &assignment); err != nil {
+		return nil, errors.NewDatabaseError("failed to create assignment: %v", err)
+	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     model.AuditActionAssignmentCreated,
+		TargetType: model.AuditTargetAssignment,
+		TargetID:   strconv.FormatUint(uint64(assignment.ID), 10),
+		After:      assignment,
+	})
+	return &assignment, nil
+}
+
+func (s *rbacService) DeleteAssignment(ctx context.Context, id uint) error {
+	assignment, err := s.assignmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return errors.NewNotFoundError("assignment not found")
+	}
+	if err := s.assignmentRepo.Delete(ctx, id); err != nil {
+		return errors.NewDatabaseError("failed to delete assignment: %v", err)
+	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     model.AuditActionAssignmentDeleted,
+		TargetType: model.AuditTargetAssignment,
+		TargetID:   strconv.FormatUint(uint64(id), 10),
+		Before:     assignment,
+	})
+	return nil
+}
+
+// checkRole validates the parent and permissions of a role about to be saved
+// and returns the permissions deduplicated
+func (s *rbacService) checkRole(ctx context.Context, snapshot *roleSnapshot, role model.Role, permissions []string) ([]string, error) {
+	if role.Parent != nil {
+		parent := *role.Parent
+		if _, ok := snapshot.roles[parent]; !ok {
+			return nil, errors.NewValidationError("parent role %q does not exist", parent)
+		}
+		// Walk up from the new parent; reaching the role itself means the
+		// change would create a cycle.
+		visited := make(map[string]bool)
+		for ancestor := &parent; ancestor != nil && !visited[*ancestor]; ancestor = snapshot.roles[*ancestor].Parent {
+			visited[*ancestor] = true
+			if *ancestor == role.Name {
+				return nil, errors.NewValidationError("role %q cannot inherit from itself", role.Name)
+			}
+		}
+	}
+
+	known, err := s.repo.ListPermissions(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("failed to list permissions: %v", err)
+	}
+	exists := make(map[string]bool, len(known))
+	for _, permission := range known {
+		exists[permission.Name] = true
+	}
+
+	seen := make(map[string]bool, len(permissions))
+	unique := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if !exists[permission] {
+			return nil, errors.NewValidationError("permission %q does not exist", permission)
+		}
+		if !seen[permission] {
+			seen[permission] = true
+			unique = append(unique, permission)
+		}
+	}
+	return unique, nil
+}
+
+func (s *rbacService) detailsAfterChange(ctx context.Context, role model.Role) (*RoleDetails, error) {
+	s.invalidate()
+	snapshot, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	details := snapshot.details(role)
+	return &details, nil
+}
+
+// load returns the cached role graph, reloading it once it is stale
+func (s *rbacService) load(ctx context.Context) (*roleSnapshot, error) {
+	s.mu.RLock()
+	snapshot := s.snapshot
+	s.mu.RUnlock()
+	if snapshot != nil && time.Since(snapshot.loadedAt) < permissionCacheTTL {
+		return snapshot, nil
+	}
+	return s.reload(ctx)
+}
+
+func (s *rbacService) reload(ctx context.Context) (*roleSnapshot, error) {
+	roles, err := s.repo.ListRoles(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("failed to load roles: %v", err)
+	}
+	grants, err := s.repo.ListRolePermissions(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("failed to load role permissions: %v", err)
+	}
+
+	snapshot := buildRoleSnapshot(roles, grants)
+	s.mu.Lock()
+	s.snapshot = snapshot
+	s.mu.Unlock()
+	return snapshot, nil
+}
+
+func (s *rbacService) invalidate() {
+	s.mu.Lock()
+	s.snapshot = nil
+	s.mu.Unlock()
+}
+
+func buildRoleSnapshot(roles []model.Role, grants []model.RolePermission) *roleSnapshot {
+	snapshot := &roleSnapshot{
+		loadedAt:  time.Now(),
+		roles:     make(map[string]model.Role, len(roles)),
+		direct:    make(map[string][]string, len(roles)),
+		effective: make(map[string]map[string]bool, len(roles)),
+	}
+	for _, role := range roles {
+		snapshot.roles[role.Name] = role
+	}
+	for _, grant := range grants {
+		snapshot.direct[grant.Role] = append(snapshot.direct[grant.Role], grant.Permission)
+	}
+
+	for name := range snapshot.roles {
+		effective := make(map[string]bool)
+		// visited guards against cycles written to the database by hand
+		visited := make(map[string]bool)
+		for current := name; current != "" && !visited[current]; {
+			visited[current] = true
+			for _, permission := range snapshot.direct[current] {
+				effective[permission] = true
+			}
+			parent := snapshot.roles[current].Parent
+			if parent == nil {
+				break
+			}
+			current = *parent
+		}
+		snapshot.effective[name] = effective
+	}
+	return snapshot
+}
+
+func (r *roleSnapshot) details(role model.Role) RoleDetails {
+	details := RoleDetails{
+		Role:                 role,
+		Permissions:          append([]string{}, r.direct[role.Name]...),
+		EffectivePermissions: make([]string, 0, len(r.effective[role.Name])),
+	}
+	for permission := range r.effective[role.Name] {
+		details.EffectivePermissions = append(details.EffectivePermissions, permission)
+	}
+	sort.Strings(details.Permissions)
+	sort.Strings(details.EffectivePermissions)
+	return details
+}
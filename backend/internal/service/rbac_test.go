@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vietgs03/translate/backend/internal/model"
+	"github.com/vietgs03/translate/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// memoryRoleRepo is a minimal in-memory RoleRepository
+type memoryRoleRepo struct {
+	roles       map[string]model.Role
+	permissions map[string]model.Permission
+	grants      map[string][]string
+}
+
+// newSeededRoleRepo holds the roles and permissions of the RBAC migration
+func newSeededRoleRepo() *memoryRoleRepo {
+	r := &memoryRoleRepo{
+		roles:       make(map[string]model.Role),
+		permissions: make(map[string]model.Permission),
+		grants:      make(map[string][]string),
+	}
+	for _, name := range []string{
+		model.PermissionTranslationRead, model.PermissionTranslationCreate,
+		model.PermissionTranslationUpdate, model.PermissionTranslationDelete,
+		model.PermissionTranslationApprove, model.PermissionTranslationManage, model.PermissionUserManage,
+		model.PermissionRoleManage, model.PermissionCacheManage,
+	} {
+		r.permissions[name] = model.Permission{Name: name}
+	}
+	parent := func(name string) *string { return &name }
+	r.roles["reader"] = model.Role{Name: "reader"}
+	r.roles["user"] = model.Role{Name: "user", Parent: parent("reader")}
+	r.roles["translator"] = model.Role{Name: "translator", Parent: parent("user")}
+	r.roles["admin"] = model.Role{Name: "admin", Parent: parent("translator")}
+	r.grants["reader"] = []string{model.PermissionTranslationRead}
+	r.grants["user"] = []string{model.PermissionTranslationCreate}
+	r.grants["translator"] = []string{model.PermissionTranslationUpdate}
+	r.grants["admin"] = []string{
+		model.PermissionTranslationDelete, model.PermissionTranslationApprove,
+		model.PermissionTranslationManage, model.PermissionUserManage, model.PermissionRoleManage, model.PermissionCacheManage,
+	}
+	return r
+}
+
+func (r *memoryRoleRepo) ListRoles(ctx context.Context) ([]model.Role, error) {
+	roles := make([]model.Role, 0, len(r.roles))
+	for _, role := range r.roles {
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+func (r *memoryRoleRepo) GetRole(ctx context.Context, name string) (*model.Role, error) {
+	role, ok := r.roles[name]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &role, nil
+}
+
+func (r *memoryRoleRepo) CreateRole(ctx context.Context, role *model.Role, permissions []string) error {
+	r.roles[role.Name] = *role
+	r.grants[role.Name] = permissions
+	return nil
+}
+
+func (r *memoryRoleRepo) UpdateRole(ctx context.Context, role *model.Role, permissions []string) error {
+	return r.CreateRole(ctx, role, permissions)
+}
+
+func (r *memoryRoleRepo) DeleteRole(ctx context.Context, name string) error {
+	delete(r.roles, name)
+	delete(r.grants, name)
+	return nil
+}
+
+func (r *memoryRoleRepo) ListPermissions(ctx context.Context) ([]model.Permission, error) {
+	permissions := make([]model.Permission, 0, len(r.permissions))
+	for _, permission := range r.permissions {
+		permissions = append(permissions, permission)
+	}
+	return permissions, nil
+}
+
+func (r *memoryRoleRepo) CreatePermission(ctx context.Context, permission *model.Permission) error {
+	r.permissions[permission.Name] = *permission
+	return nil
+}
+
+func (r *memoryRoleRepo) ListRolePermissions(ctx context.Context) ([]model.RolePermission, error) {
+	var grants []model.RolePermission
+	for role, permissions := range r.grants {
+		for _, permission := range permissions {
+			grants = append(grants, model.RolePermission{Role: role, Permission: permission})
+		}
+	}
+	return grants, nil
+}
+
+// memoryAssignmentRepo is a minimal in-memory TranslatorAssignmentRepository
+type memoryAssignmentRepo struct {
+	nextID      uint
+	assignments map[uint]model.TranslatorAssignment
+}
+
+func newMemoryAssignmentRepo() *memoryAssignmentRepo {
+	return &memoryAssignmentRepo{assignments: make(map[uint]model.TranslatorAssignment)}
+}
+
+func (r *memoryAssignmentRepo) Create(ctx context.Context, assignment *model.TranslatorAssignment) error {
+	r.nextID++
+	assignment.ID = r.nextID
+	r.assignments[assignment.ID] = *assignment
+	return nil
+}
+
+func (r *memoryAssignmentRepo) GetByID(ctx context.Context, id uint) (*model.TranslatorAssignment, error) {
+	assignment, ok := r.assignments[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &assignment, nil
+}
+
+func (r *memoryAssignmentRepo) ListByUser(ctx context.Context, userID uint) ([]model.TranslatorAssignment, error) {
+	var assignments []model.TranslatorAssignment
+	for _, assignment := range r.assignments {
+		if assignment.UserID == userID {
+			assignments = append(assignments, assignment)
+		}
+	}
+	return assignments, nil
+}
+
+func (r *memoryAssignmentRepo) Delete(ctx context.Context, id uint) error {
+	delete(r.assignments, id)
+	return nil
+}
+
+func TestRBACInheritance(t *testing.T) {
+	ctx := context.Background()
+	rbac := NewRBACService(newSeededRoleRepo(), newMemoryUserRepo(), newMemoryAssignmentRepo(), NewAuditService(newMemoryAuditRepo()))
+
+	cases := []struct {
+		role       string
+		permission string
+		allowed    bool
+	}{
+		{"reader", model.PermissionTranslationRead, true},
+		{"reader", model.PermissionTranslationCreate, false},
+		{"translator", model.PermissionTranslationRead, true},
+		{"translator", model.PermissionTranslationUpdate, true},
+		{"translator", model.PermissionTranslationApprove, false},
+		{"admin", model.PermissionTranslationRead, true},
+		{"admin", model.PermissionRoleManage, true},
+		{"unknown", model.PermissionTranslationRead, false},
+	}
+	for _, tc := range cases {
+		allowed, err := rbac.HasPermission(ctx, tc.role, tc.permission)
+		require.NoError(t, err)
+		assert.Equal(t, tc.allowed, allowed, "%s → %s", tc.role, tc.permission)
+	}
+}
+
+func TestRBACRoleManagement(t *testing.T) {
+	ctx := context.Background()
+	users := newMemoryUserRepo()
+	trail := NewAuditService(newMemoryAuditRepo())
+	rbac := NewRBACService(newSeededRoleRepo(), users, newMemoryAssignmentRepo(), trail)
+
+	translator := "translator"
+	reviewer, err := rbac.CreateRole(ctx, CreateRoleInput{
+		Name:        "reviewer",
+		Parent:      &translator,
+		Permissions: []string{model.PermissionTranslationApprove},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, reviewer.EffectivePermissions, model.PermissionTranslationUpdate)
+
+	// New grants apply without waiting for the cache to expire
+	allowed, err := rbac.HasPermission(ctx, "reviewer", model.PermissionTranslationApprove)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	_, err = rbac.CreateRole(ctx, CreateRoleInput{Name: "ghost", Permissions: []string{"ghost:haunt"}})
+	assert.Error(t, err, "unknown permissions are rejected")
+
+	reviewerName := "reviewer"
+	_, err = rbac.UpdateRole(ctx, "translator", UpdateRoleInput{Parent: &reviewerName})
+	assert.Error(t, err, "inheritance cycles are rejected")
+
+	require.NoError(t, users.Create(ctx, &model.User{Username: "rita", Role: "reviewer"}))
+	assert.Error(t, rbac.DeleteRole(ctx, "reviewer"), "roles in use cannot be deleted")
+	assert.Error(t, rbac.DeleteRole(ctx, "user"), "inherited roles cannot be deleted")
+
+	created, _, err := trail.List(ctx, repository.AuditFilter{Action: model.AuditActionRoleCreated})
+	require.NoError(t, err)
+	require.Len(t, created, 1, "rejected changes are not recorded")
+	assert.Equal(t, "reviewer", created[0].TargetID)
+}
@@ -5,20 +5,40 @@ import (
 
 	"github.com/vietgs03/translate/backend/internal/model"
 	"github.com/vietgs03/translate/backend/internal/repository"
+	"github.com/vietgs03/translate/backend/internal/types"
 )
 
+// TranslationService works on the translations of one Scope at a time
 type TranslationService interface {
-	CreateTranslation(ctx context.Context, input CreateTranslationInput) (*model.Translation, error)
-	GetTranslation(ctx context.Context, id uint) (*model.Translation, error)
-	UpdateTranslation(ctx context.Context, id uint, input UpdateTranslationInput) (*model.Translation, error)
-	DeleteTranslation(ctx context.Context, id uint) error
-	ListTranslations(ctx context.Context, filter repository.TranslationFilter) ([]model.Translation, error)
+	CreateTranslation(ctx context.Context, scope Scope, input CreateTranslationInput) (*model.Translation, error)
+	GetTranslation(ctx context.Context, scope Scope, id uint) (*model.Translation, error)
+	// UpdateTranslation edits a translation on behalf of actor, who must be
+	// its author, assigned to its language pair or category, or allowed to
+	// manage all translations
+	UpdateTranslation(ctx context.Context, actor *types.JWTClaims, scope Scope, id uint, input UpdateTranslationInput) (*model.Translation, error)
+	// ReviewTranslation sets the review status of a translation
+	ReviewTranslation(ctx context.Context, scope Scope, id uint, input ReviewTranslationInput) (*model.Translation, error)
+	DeleteTranslation(ctx context.Context, scope Scope, id uint) error
+	ListTranslations(ctx context.Context, scope Scope, filter repository.TranslationFilter, count repository.CountMode) (*TranslationPage, error)
+}
+
+// TranslationPage is one page of a listing. NextCursor and PrevCursor are
+// only set in keyset mode, Total only when a count was requested.
+type TranslationPage struct {
+	Items          []model.Translation
+	Total          *int64
+	TotalEstimated bool
+	HasNext        bool
+	HasPrev        bool
+	NextCursor     string
+	PrevCursor     string
 }
 
 type CreateTranslationInput struct {
 	SourceText     string `json:"source_text" validate:"required,min=1,max=1000"`
-	SourceLanguage string `json:"source_language" validate:"required,len=2"`
-	TargetLanguage string `json:"target_language" validate:"required,len=2"`
+	// BCP 47 tags such as "en", "pt-BR" or "zh-Hant"
+	SourceLanguage string `json:"source_language" validate:"required,max=35" example:"en"`
+	TargetLanguage string `json:"target_language" validate:"required,max=35" example:"zh-Hant"`
 	Context        string `json:"context" validate:"omitempty,max=500"`
 	Category       string `json:"category" validate:"omitempty,max=50"`
 	CreatedBy      string `json:"-"`
@@ -28,4 +48,8 @@ type UpdateTranslationInput struct {
 	TranslatedText string `json:"translated_text" validate:"required,min=1,max=1000"`
 	Context        string `json:"context" validate:"omitempty,max=500"`
 	Category       string `json:"category" validate:"omitempty,max=50"`
-} 
\ No newline at end of file
+}
+
+type ReviewTranslationInput struct {
+	Status string `json:"status" validate:"required,oneof=pending approved rejected"`
+}
@@ -3,53 +3,194 @@ package service
 import (
 	"context"
 	"fmt"
-	"log"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/language"
+	"github.com/vietgs03/translate/backend/internal/logging"
+	"github.com/vietgs03/translate/backend/internal/metrics"
 	"github.com/vietgs03/translate/backend/internal/model"
 	"github.com/vietgs03/translate/backend/internal/repository"
 	"github.com/vietgs03/translate/backend/internal/cache"
 	"github.com/vietgs03/translate/backend/internal/service/translator"
+	"github.com/vietgs03/translate/backend/internal/tracing"
+	"github.com/vietgs03/translate/backend/internal/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 )
 
+var tracer = otel.Tracer("github.com/vietgs03/translate/backend/internal/service")
+
+// resolveTimeout bounds a coalesced lookup, which no caller can cancel
+const resolveTimeout = time.Minute
+
 type translationService struct {
-	repo       repository.TranslationRepository
-	cache      *cache.TranslationCache
-	translator translator.Translator
+	repo           repository.TranslationRepository
+	assignmentRepo repository.TranslatorAssignmentRepository
+	rbac           RBACService
+	cache          cache.TranslationCache
+	translator     translator.Translator
+	audit          AuditService
+	languages      *language.Registry
+	flight         singleflight.Group
 }
 
 func NewTranslationService(
 	repo repository.TranslationRepository,
-	cache *cache.TranslationCache,
+	assignmentRepo repository.TranslatorAssignmentRepository,
+	rbac RBACService,
+	cache cache.TranslationCache,
 	translator translator.Translator,
+	auditService AuditService,
+	languages *language.Registry,
 ) TranslationService {
 	return &translationService{
-		repo:       repo,
-		cache:      cache,
-		translator: translator,
+		repo:           repo,
+		assignmentRepo: assignmentRepo,
+		rbac:           rbac,
+		cache:          cache,
+		translator:     translator,
+		audit:          auditService,
+		languages:      languages,
 	}
 }
 
-func (s *translationService) CreateTranslation(ctx context.Context, input CreateTranslationInput) (*model.Translation, error) {
+func (s *translationService) CreateTranslation(ctx context.Context, scope Scope, input CreateTranslationInput) (_ *model.Translation, err error) {
+	ctx, span := tracer.Start(ctx, "translationService.CreateTranslation", trace.WithAttributes(
+		attribute.Int("translation.project_id", int(scope.ProjectID())),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.checkLanguages(&input); err != nil {
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.String("translation.source_language", input.SourceLanguage),
+		attribute.String("translation.target_language", input.TargetLanguage),
+	)
+
 	// Check cache first
-	if cached, err := s.cache.Get(ctx, input.SourceText, input.SourceLanguage, input.TargetLanguage); err == nil && cached != nil {
+	if cached, err := s.cache.Get(ctx, scope.ProjectID(), input.SourceText, input.SourceLanguage, input.TargetLanguage); err == nil && cached != nil {
+		lookup(ctx, metrics.LookupCacheHit)
 		return cached, nil
 	}
 
+	// Coalesce concurrent misses for the same text so only one of them
+	// reaches the database and the provider. The entry is stored as the
+	// caller whose function runs made it, and the others share it, just as
+	// they would have found it in the cache a moment later.
+	key := strings.Join([]string{
+		strconv.FormatUint(uint64(scope.ProjectID()), 10),
+		strings.ToLower(input.SourceLanguage),
+		strings.ToLower(input.TargetLanguage),
+		cache.NormalizeText(input.SourceText),
+	}, "\x00")
+	// Only the caller whose function runs records where the lookup was
+	// answered; the ones that joined it count as coalesced
+	resolved := false
+	results := s.flight.DoChan(key, func() (interface{}, error) {
+		resolved = true
+		// The shared call outlives the caller that started it, so that
+		// caller going away doesn't fail the others
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), resolveTimeout)
+		defer cancel()
+		return s.resolveTranslation(ctx, scope, input)
+	})
+
+	var result singleflight.Result
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if !resolved {
+		lookup(ctx, metrics.LookupCoalesced)
+	}
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	// Every caller gets its own copy of the shared result
+	translation := *result.Val.(*model.Translation)
+	return &translation, nil
+}
+
+// checkLanguages canonicalizes the language tags of input and rejects
+// languages and pairs outside the registry, before the cache or a provider
+// sees them
+func (s *translationService) checkLanguages(input *CreateTranslationInput) error {
+	var fields []errors.FieldError
+	for _, f := range []struct {
+		name string
+		tag  *string
+	}{
+		{"source_language", &input.SourceLanguage},
+		{"target_language", &input.TargetLanguage},
+	} {
+		canonical, err := language.Canonicalize(*f.tag)
+		if err != nil {
+			fields = append(fields, errors.FieldError{Field: f.name, Message: fmt.Sprintf("%s: %v", f.name, err)})
+			continue
+		}
+		if !s.languages.Supported(canonical) {
+			fields = append(fields, errors.FieldError{Field: f.name, Message: fmt.Sprintf("%s %s is not a supported language", f.name, canonical)})
+			continue
+		}
+		*f.tag = canonical
+	}
+	if len(fields) > 0 {
+		return errors.NewFieldValidationError(fields...)
+	}
+
+	if input.SourceLanguage == input.TargetLanguage {
+		return errors.NewFieldValidationError(errors.FieldError{
+			Field:   "target_language",
+			Message: "target_language must differ from source_language",
+		})
+	}
+	if !s.languages.SupportsPair(input.SourceLanguage, input.TargetLanguage) {
+		return errors.NewError(errors.ValidationErr, errors.CodeUnsupportedLanguagePair,
+			"translation from %s to %s is not supported", input.SourceLanguage, input.TargetLanguage)
+	}
+	return nil
+}
+
+// resolveTranslation looks the text up in the scope's translation memory,
+// then in the global one if the project allows it, and finally asks the
+// translator, persisting and caching whatever it finds. New translations
+// are always stored in the requesting scope; a global match only seeds the
+// project's own entry, so a project never hands out global rows.
+func (s *translationService) resolveTranslation(ctx context.Context, scope Scope, input CreateTranslationInput) (*model.Translation, error) {
 	// Try to find existing translation in database
-	existing, err := s.findExistingTranslation(ctx, input)
+	existing, err := s.findExistingTranslation(ctx, scope.Tenant(), input)
 	if err == nil {
+		lookup(ctx, metrics.LookupDatabaseHit)
 		// Cache the found translation
 		if err := s.cache.Set(ctx, existing); err != nil {
-			log.Printf("Failed to cache translation: %v", err)
+			logging.FromContext(ctx).Warn("failed to cache translation", zap.Error(err))
 		}
 		return existing, nil
 	}
 
-	// Get translation from translator service
-	translatedText, err := s.translator.Translate(ctx, input.SourceText, input.SourceLanguage, input.TargetLanguage)
-	if err != nil {
-		return nil, fmt.Errorf("failed to translate text: %v", err)
+	var translatedText string
+	provider := s.translator.Name()
+	if scope.fallsBackToGlobal() {
+		if global, err := s.findGlobalTranslation(ctx, input); err == nil {
+			translatedText, provider = global.TranslatedText, global.Provider
+		}
+	}
+
+	if translatedText == "" {
+		// Get translation from translator service
+		lookup(ctx, metrics.LookupProviderCall)
+		if translatedText, err = s.translate(ctx, input); err != nil {
+			return nil, fmt.Errorf("failed to translate text: %v", err)
+		}
 	}
 
 	translation := &model.Translation{
@@ -60,33 +201,69 @@ func (s *translationService) CreateTranslation(ctx context.Context, input Create
 		Context:        input.Context,
 		Category:       input.Category,
 		CreatedBy:      input.CreatedBy,
+		Status:         model.TranslationStatusPending,
+		Provider:       provider,
 	}
 
-	if err := s.repo.Create(ctx, translation); err != nil {
+	if err := s.repo.Create(ctx, scope.Tenant(), translation); err != nil {
 		return nil, errors.NewDatabaseError("failed to save translation: %v", err)
 	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     model.AuditActionTranslationCreated,
+		TargetType: model.AuditTargetTranslation,
+		TargetID:   strconv.FormatUint(uint64(translation.ID), 10),
+		After:      translation,
+	})
 
 	// Cache the new translation
 	if err := s.cache.Set(ctx, translation); err != nil {
-		log.Printf("Failed to cache translation: %v", err)
+		logging.FromContext(ctx).Warn("failed to cache translation", zap.Error(err))
 	}
 
 	return translation, nil
 }
 
-func (s *translationService) GetTranslation(ctx context.Context, id uint) (*model.Translation, error) {
-	translation, err := s.repo.GetByID(ctx, id)
+// translate asks the provider, recording the call in metrics and the trace
+func (s *translationService) translate(ctx context.Context, input CreateTranslationInput) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "Translator.Translate", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("translation.provider", s.translator.Name()),
+		attribute.String("translation.source_language", input.SourceLanguage),
+		attribute.String("translation.target_language", input.TargetLanguage),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	start := time.Now()
+	translatedText, err := s.translator.Translate(ctx, input.SourceText, input.SourceLanguage, input.TargetLanguage)
+	metrics.ObserveProviderCall(s.translator.Name(), time.Since(start), err)
+	if err != nil {
+		span.SetAttributes(attribute.String("error.type", translator.ErrorKind(err)))
+	}
+	return translatedText, err
+}
+
+// lookup records where a translation lookup was answered from
+func lookup(ctx context.Context, outcome string) {
+	metrics.TranslationLookup(outcome)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("translation.lookup", outcome))
+}
+
+func (s *translationService) GetTranslation(ctx context.Context, scope Scope, id uint) (*model.Translation, error) {
+	translation, err := s.repo.GetByID(ctx, scope.Tenant(), id)
 	if err != nil {
 		return nil, errors.NewNotFoundError("translation not found")
 	}
 	return translation, nil
 }
 
-func (s *translationService) UpdateTranslation(ctx context.Context, id uint, input UpdateTranslationInput) (*model.Translation, error) {
-	translation, err := s.repo.GetByID(ctx, id)
+func (s *translationService) UpdateTranslation(ctx context.Context, actor *types.JWTClaims, scope Scope, id uint, input UpdateTranslationInput) (*model.Translation, error) {
+	translation, err := s.repo.GetByID(ctx, scope.Tenant(), id)
 	if err != nil {
 		return nil, errors.NewNotFoundError("translation not found")
 	}
+	if err := s.authorizeEdit(ctx, actor, scope, translation); err != nil {
+		return nil, err
+	}
+	before := *translation
 
 	if input.TranslatedText != "" {
 		translation.TranslatedText = input.TranslatedText
@@ -98,30 +275,239 @@ func (s *translationService) UpdateTranslation(ctx context.Context, id uint, inp
 		translation.Category = input.Category
 	}
 
-	if err := s.repo.Update(ctx, translation); err != nil {
+	if err := s.repo.Update(ctx, scope.Tenant(), translation); err != nil {
 		return nil, errors.NewDatabaseError("failed to update translation: %v", err)
 	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     model.AuditActionTranslationUpdated,
+		TargetType: model.AuditTargetTranslation,
+		TargetID:   strconv.FormatUint(uint64(translation.ID), 10),
+		Before:     before,
+		After:      translation,
+	})
+
+	// Refresh the cached copy so the edit is served immediately
+	if err := s.cache.Set(ctx, translation); err != nil {
+		logging.FromContext(ctx).Warn("failed to refresh cached translation", zap.Error(err))
+		s.invalidate(ctx, translation)
+	}
+
+	return translation, nil
+}
+
+func (s *translationService) ReviewTranslation(ctx context.Context, scope Scope, id uint, input ReviewTranslationInput) (*model.Translation, error) {
+	translation, err := s.repo.GetByID(ctx, scope.Tenant(), id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("translation not found")
+	}
+
+	previous := translation.Status
+	translation.Status = input.Status
+	if err := s.repo.Update(ctx, scope.Tenant(), translation); err != nil {
+		return nil, errors.NewDatabaseError("failed to update translation status: %v", err)
+	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     model.AuditActionTranslationStatus,
+		TargetType: model.AuditTargetTranslation,
+		TargetID:   strconv.FormatUint(uint64(translation.ID), 10),
+		Before:     map[string]string{"status": previous},
+		After:      map[string]string{"status": translation.Status},
+	})
+
+	if err := s.cache.Set(ctx, translation); err != nil {
+		logging.FromContext(ctx).Warn("failed to refresh cached translation", zap.Error(err))
+		s.invalidate(ctx, translation)
+	}
 
 	return translation, nil
 }
 
-func (s *translationService) DeleteTranslation(ctx context.Context, id uint) error {
-	if err := s.repo.Delete(ctx, id); err != nil {
+func (s *translationService) DeleteTranslation(ctx context.Context, scope Scope, id uint) error {
+	translation, err := s.repo.GetByID(ctx, scope.Tenant(), id)
+	if err != nil {
+		return errors.NewNotFoundError("translation not found")
+	}
+
+	if err := s.repo.Delete(ctx, scope.Tenant(), id); err != nil {
 		return errors.NewDatabaseError("failed to delete translation: %v", err)
 	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     model.AuditActionTranslationDeleted,
+		TargetType: model.AuditTargetTranslation,
+		TargetID:   strconv.FormatUint(uint64(translation.ID), 10),
+		Before:     translation,
+	})
+
+	s.invalidate(ctx, translation)
 	return nil
 }
 
-func (s *translationService) ListTranslations(ctx context.Context, filter repository.TranslationFilter) ([]model.Translation, error) {
-	translations, err := s.repo.List(ctx, filter)
+// authorizeEdit decides whether actor may edit the translation in its
+// current state:
+//   - translation:manage allows any edit
+//   - approved translations only by leads assigned to them
+//   - pending translations by their author, while they may still create
+//     translations (translation:create)
+//   - otherwise by translators (translation:update) assigned to them
+//
+// Inside a project the project role takes the place of assignments and
+// authorship: maintainers edit anything, translators everything not yet
+// approved, viewers nothing.
+func (s *translationService) authorizeEdit(ctx context.Context, actor *types.JWTClaims, scope Scope, translation *model.Translation) error {
+	if actor == nil {
+		return errors.NewUnauthorizedError("user not authenticated")
+	}
+
+	manage, err := s.rbac.Allows(ctx, actor, model.PermissionTranslationManage)
+	if err != nil {
+		return err
+	}
+	if manage {
+		return nil
+	}
+
+	approved := translation.Status == model.TranslationStatusApproved
+	if scope.Project != nil {
+		if model.ProjectRoleAtLeast(scope.Role, model.ProjectRoleMaintainer) ||
+			(!approved && model.ProjectRoleAtLeast(scope.Role, model.ProjectRoleTranslator)) {
+			return nil
+		}
+		if approved {
+			return errors.NewForbiddenError("only project maintainers can edit approved translations")
+		}
+		return errors.NewForbiddenError("only project translators can edit this translation")
+	}
+
+	// A read-only key or a demoted author no longer holds translation:create
+	if translation.Status == model.TranslationStatusPending && translation.CreatedBy == actor.Username {
+		create, err := s.rbac.Allows(ctx, actor, model.PermissionTranslationCreate)
+		if err != nil {
+			return err
+		}
+		if create {
+			return nil
+		}
+	}
+
+	update, err := s.rbac.Allows(ctx, actor, model.PermissionTranslationUpdate)
+	if err != nil {
+		return err
+	}
+	if update {
+		assignments, err := s.assignmentRepo.ListByUser(ctx, actor.UserID)
+		if err != nil {
+			return errors.NewDatabaseError("failed to load assignments: %v", err)
+		}
+		for _, assignment := range assignments {
+			if assignment.Covers(translation) && (assignment.Lead || !approved) {
+				return nil
+			}
+		}
+	}
+
+	if approved {
+		return errors.NewForbiddenError("only admins or leads of this language pair can edit approved translations")
+	}
+	return errors.NewForbiddenError("not assigned to the language pair or category of this translation")
+}
+
+// invalidate drops the cache entry for a translation. Failures are only
+// logged; the entry still expires with the cache TTL.
+func (s *translationService) invalidate(ctx context.Context, translation *model.Translation) {
+	if err := s.cache.Delete(ctx, projectOf(translation), translation.SourceText, translation.SourceLanguage, translation.TargetLanguage); err != nil {
+		logging.FromContext(ctx).Warn("failed to invalidate cached translation", zap.Uint("translation_id", translation.ID), zap.Error(err))
+	}
+}
+
+func (s *translationService) ListTranslations(ctx context.Context, scope Scope, filter repository.TranslationFilter, count repository.CountMode) (*TranslationPage, error) {
+	filter.Tenant = scope.Tenant()
+	if filter.PageSize <= 0 {
+		filter.PageSize = repository.DefaultPageSize
+	}
+	if filter.PageSize > repository.MaxPageSize {
+		filter.PageSize = repository.MaxPageSize
+	}
+	if !filter.SortBy.Valid() {
+		filter.SortBy = repository.SortCreatedAt
+	}
+	if !filter.SortOrder.Valid() {
+		filter.SortOrder = repository.SortDesc
+	}
+	pageSize := filter.PageSize
+
+	// In keyset mode fetch one extra row to find out whether another page exists
+	query := filter
+	if filter.Page == 0 {
+		query.PageSize = pageSize + 1
+	}
+
+	translations, err := s.repo.List(ctx, query)
 	if err != nil {
 		return nil, errors.NewDatabaseError("failed to list translations: %v", err)
 	}
-	return translations, nil
+
+	page := &TranslationPage{Items: translations}
+
+	if filter.Page > 0 {
+		page.HasPrev = filter.Page > 1
+		page.HasNext = len(translations) == pageSize
+	} else {
+		backward := filter.Cursor != nil && filter.Cursor.Direction == repository.CursorPrev
+		more := len(translations) > pageSize
+		if more {
+			if backward {
+				// Rows come back in display order, so the extra one is first
+				translations = translations[1:]
+			} else {
+				translations = translations[:pageSize]
+			}
+		}
+		page.Items = translations
+
+		if backward {
+			page.HasNext = true
+			page.HasPrev = more
+		} else {
+			page.HasNext = more
+			page.HasPrev = filter.Cursor != nil
+		}
+
+		if len(translations) > 0 {
+			first, last := translations[0], translations[len(translations)-1]
+			if page.HasNext {
+				page.NextCursor = repository.EncodeCursor(repository.NewCursor(filter.SortBy, filter.SortOrder, &last, repository.CursorNext))
+			}
+			if page.HasPrev {
+				page.PrevCursor = repository.EncodeCursor(repository.NewCursor(filter.SortBy, filter.SortOrder, &first, repository.CursorPrev))
+			}
+		}
+	}
+
+	switch count {
+	case repository.CountExact:
+		total, err := s.repo.Count(ctx, filter)
+		if err != nil {
+			return nil, errors.NewDatabaseError("failed to count translations: %v", err)
+		}
+		page.Total = &total
+		if filter.Page > 0 {
+			page.HasNext = int64(filter.Page*pageSize) < total
+		}
+	case repository.CountEstimated:
+		total, err := s.repo.EstimateCount(ctx, filter)
+		if err != nil {
+			return nil, errors.NewDatabaseError("failed to estimate translation count: %v", err)
+		}
+		page.Total = &total
+		page.TotalEstimated = true
+	}
+
+	return page, nil
 }
 
-func (s *translationService) findExistingTranslation(ctx context.Context, input CreateTranslationInput) (*model.Translation, error) {
+func (s *translationService) findExistingTranslation(ctx context.Context, tenant repository.Tenant, input CreateTranslationInput) (*model.Translation, error) {
 	translations, err := s.repo.List(ctx, repository.TranslationFilter{
+		Tenant:         tenant,
 		SourceText:     input.SourceText,
 		SourceLanguage: input.SourceLanguage,
 		TargetLanguage: input.TargetLanguage,
@@ -132,4 +518,31 @@ func (s *translationService) findExistingTranslation(ctx context.Context, input
 		return nil, fmt.Errorf("no existing translation found")
 	}
 	return &translations[0], nil
-} 
\ No newline at end of file
+}
+
+// findGlobalTranslation answers a project lookup from the global
+// translation memory, cache first
+func (s *translationService) findGlobalTranslation(ctx context.Context, input CreateTranslationInput) (*model.Translation, error) {
+	if cached, err := s.cache.Get(ctx, 0, input.SourceText, input.SourceLanguage, input.TargetLanguage); err == nil && cached != nil {
+		lookup(ctx, metrics.LookupCacheHit)
+		return cached, nil
+	}
+
+	global, err := s.findExistingTranslation(ctx, repository.GlobalTenant, input)
+	if err != nil {
+		return nil, err
+	}
+	lookup(ctx, metrics.LookupDatabaseHit)
+	if err := s.cache.Set(ctx, global); err != nil {
+		logging.FromContext(ctx).Warn("failed to cache translation", zap.Error(err))
+	}
+	return global, nil
+}
+
+// projectOf is the cache partition of a translation
+func projectOf(translation *model.Translation) uint {
+	if translation.ProjectID == nil {
+		return 0
+	}
+	return *translation.ProjectID
+}
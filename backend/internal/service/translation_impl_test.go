@@ -0,0 +1,441 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vietgs03/translate/backend/internal/cache"
+	"github.com/vietgs03/translate/backend/internal/config"
+	"github.com/vietgs03/translate/backend/internal/errors"
+	"github.com/vietgs03/translate/backend/internal/language"
+	"github.com/vietgs03/translate/backend/internal/metrics"
+	"github.com/vietgs03/translate/backend/internal/model"
+	"github.com/vietgs03/translate/backend/internal/repository"
+	"github.com/vietgs03/translate/backend/internal/types"
+)
+
+// memoryTranslationRepo is a minimal in-memory TranslationRepository
+type memoryTranslationRepo struct {
+	mu     sync.Mutex
+	nextID uint
+	rows   map[uint]model.Translation
+}
+
+func newMemoryTranslationRepo() *memoryTranslationRepo {
+	return &memoryTranslationRepo{rows: make(map[uint]model.Translation)}
+}
+
+func (r *memoryTranslationRepo) Create(ctx context.Context, tenant repository.Tenant, translation *model.Translation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	translation.ID = r.nextID
+	translation.ProjectID = nil
+	if !tenant.IsGlobal() {
+		projectID := tenant.ProjectID
+		translation.ProjectID = &projectID
+	}
+	translation.CreatedAt = time.Now()
+	translation.UpdatedAt = translation.CreatedAt
+	r.rows[translation.ID] = *translation
+	return nil
+}
+
+// owned reports whether the row belongs to the tenant, as the SQL scope does
+func owned(tenant repository.Tenant, translation model.Translation) bool {
+	return projectOf(&translation) == tenant.ProjectID
+}
+
+func (r *memoryTranslationRepo) GetByID(ctx context.Context, tenant repository.Tenant, id uint) (*model.Translation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	translation, ok := r.rows[id]
+	if !ok || !owned(tenant, translation) {
+		return nil, fmt.Errorf("translation not found")
+	}
+	return &translation, nil
+}
+
+func (r *memoryTranslationRepo) GetByIDsAcrossTenants(ctx context.Context, ids []uint) ([]model.Translation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var translations []model.Translation
+	for _, id := range ids {
+		if translation, ok := r.rows[id]; ok {
+			translations = append(translations, translation)
+		}
+	}
+	return translations, nil
+}
+
+func (r *memoryTranslationRepo) Update(ctx context.Context, tenant repository.Tenant, translation *model.Translation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.rows[translation.ID]; !ok || !owned(tenant, existing) {
+		return fmt.Errorf("translation not found")
+	}
+	r.rows[translation.ID] = *translation
+	return nil
+}
+
+func (r *memoryTranslationRepo) Delete(ctx context.Context, tenant repository.Tenant, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.rows[id]; ok && owned(tenant, existing) {
+		delete(r.rows, id)
+	}
+	return nil
+}
+
+func (r *memoryTranslationRepo) List(ctx context.Context, filter repository.TranslationFilter) ([]model.Translation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var translations []model.Translation
+	for _, translation := range r.rows {
+		if !owned(filter.Tenant, translation) {
+			continue
+		}
+		if filter.SourceText != "" && translation.SourceText != filter.SourceText {
+			continue
+		}
+		if filter.SourceLanguage != "" && translation.SourceLanguage != filter.SourceLanguage {
+			continue
+		}
+		if filter.TargetLanguage != "" && translation.TargetLanguage != filter.TargetLanguage {
+			continue
+		}
+		translations = append(translations, translation)
+	}
+	return translations, nil
+}
+
+func (r *memoryTranslationRepo) Count(ctx context.Context, filter repository.TranslationFilter) (int64, error) {
+	translations, err := r.List(ctx, filter)
+	return int64(len(translations)), err
+}
+
+func (r *memoryTranslationRepo) EstimateCount(ctx context.Context, filter repository.TranslationFilter) (int64, error) {
+	return r.Count(ctx, filter)
+}
+
+// slowTranslator counts calls, signals started on the first one and
+// blocks until released
+type slowTranslator struct {
+	calls   int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (t *slowTranslator) Name() string {
+	return "fake"
+}
+
+func (t *slowTranslator) Coverage() language.Coverage {
+	return language.Coverage{Languages: []string{"en", "vi", "pt", "ja"}}
+}
+
+func (t *slowTranslator) Translate(ctx context.Context, text, sourceLang, targetLang string) (string, error) {
+	if atomic.AddInt32(&t.calls, 1) == 1 {
+		close(t.started)
+	}
+	<-t.release
+	return "translated: " + text, nil
+}
+
+func (t *slowTranslator) Close() error {
+	return nil
+}
+
+func newTestTranslationService() (*translationService, *memoryTranslationRepo, *slowTranslator) {
+	repo := newMemoryTranslationRepo()
+	assignments := newMemoryAssignmentRepo()
+	rbac := NewRBACService(newSeededRoleRepo(), newMemoryUserRepo(), assignments, NewAuditService(newMemoryAuditRepo()))
+	translator := &slowTranslator{started: make(chan struct{}), release: make(chan struct{})}
+	translationCache := cache.NewMemoryCache(&config.CacheConfig{Namespace: "test", TTL: 1, MemorySize: 100})
+	languages, err := language.NewRegistry([]string{"en", "vi", "pt", "zh-Hant"}, nil)
+	if err == nil {
+		languages, err = languages.Restrict(translator.Coverage())
+	}
+	if err != nil {
+		panic(err)
+	}
+	svc := NewTranslationService(repo, assignments, rbac, translationCache, translator,
+		NewAuditService(newMemoryAuditRepo()), languages).(*translationService)
+	return svc, repo, translator
+}
+
+func TestCreateTranslationCoalescesMisses(t *testing.T) {
+	svc, repo, translator := newTestTranslationService()
+	input := CreateTranslationInput{SourceText: "race condition", SourceLanguage: "en", TargetLanguage: "vi"}
+
+	const callers = 10
+	coalesced := lookupCount(t, metrics.LookupCoalesced)
+	var wg sync.WaitGroup
+	results := make([]*model.Translation, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			translation, err := svc.CreateTranslation(context.Background(), GlobalScope, input)
+			assert.NoError(t, err)
+			results[i] = translation
+		}(i)
+	}
+
+	// Give every caller time to join the in-flight call before releasing it
+	time.Sleep(50 * time.Millisecond)
+	close(translator.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&translator.calls))
+	assert.Len(t, repo.rows, 1)
+	assert.Equal(t, float64(callers-1), lookupCount(t, metrics.LookupCoalesced)-coalesced,
+		"the callers that joined the shared call are counted too")
+	for _, translation := range results {
+		require.NotNil(t, translation)
+		assert.Equal(t, "translated: race condition", translation.TranslatedText)
+	}
+}
+
+// lookupCount reads the translation lookups counted with outcome
+func lookupCount(t *testing.T, outcome string) float64 {
+	families, err := metrics.Registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "translate_translation_lookups_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "outcome" && label.GetValue() == outcome {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestCreateTranslationCoalescingAcrossCallers(t *testing.T) {
+	svc, repo, translator := newTestTranslationService()
+	input := CreateTranslationInput{SourceText: "race condition", SourceLanguage: "en", TargetLanguage: "vi", CreatedBy: "alice"}
+
+	// The caller starting the shared call gives up on it once the provider
+	// is working on it
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := svc.CreateTranslation(ctx, GlobalScope, input)
+		first <- err
+	}()
+	<-translator.started
+
+	// Other authors, categories and contexts still share the call, or find
+	// its result in the cache if they arrive after it
+	var wg sync.WaitGroup
+	results := make([]*model.Translation, 2)
+	for i, other := range []CreateTranslationInput{
+		{SourceText: "race  condition ", SourceLanguage: "en", TargetLanguage: "vi", CreatedBy: "bob"},
+		{SourceText: input.SourceText, SourceLanguage: "en", TargetLanguage: "vi", CreatedBy: "carol", Category: "docs", Context: "threads"},
+	} {
+		wg.Add(1)
+		go func(i int, input CreateTranslationInput) {
+			defer wg.Done()
+			translation, err := svc.CreateTranslation(context.Background(), GlobalScope, input)
+			assert.NoError(t, err)
+			results[i] = translation
+		}(i, other)
+	}
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+
+	close(translator.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&translator.calls))
+	assert.Len(t, repo.rows, 1)
+	for _, translation := range results {
+		require.NotNil(t, translation)
+		assert.Equal(t, "alice", translation.CreatedBy, "the entry is attributed to the caller whose call ran")
+		assert.Equal(t, "translated: race condition", translation.TranslatedText)
+	}
+}
+
+func TestCreateTranslationChecksLanguages(t *testing.T) {
+	svc, repo, translator := newTestTranslationService()
+	close(translator.release)
+	ctx := context.Background()
+
+	// Tags are stored in canonical form and cover their narrower forms
+	created, err := svc.CreateTranslation(ctx, GlobalScope, CreateTranslationInput{SourceText: "branch", SourceLanguage: "EN", TargetLanguage: "pt-br"})
+	require.NoError(t, err)
+	assert.Equal(t, "en", created.SourceLanguage)
+	assert.Equal(t, "pt-BR", created.TargetLanguage)
+
+	rejected := []struct {
+		source, target string
+		field          string
+	}{
+		{"zz", "vi", "source_language"},
+		{"en", "not a tag", "target_language"},
+		// Configured, but the provider doesn't declare it
+		{"en", "zh-Hant", "target_language"},
+		{"en", "en-US", ""},
+		{"en", "en", "target_language"},
+	}
+	for _, tc := range rejected {
+		_, err := svc.CreateTranslation(ctx, GlobalScope, CreateTranslationInput{SourceText: "merge", SourceLanguage: tc.source, TargetLanguage: tc.target})
+		if tc.field == "" {
+			assert.NoError(t, err, "%s to %s", tc.source, tc.target)
+			continue
+		}
+		var appErr *errors.AppError
+		require.True(t, errors.As(err, &appErr), "%s to %s", tc.source, tc.target)
+		require.Len(t, appErr.Fields, 1)
+		assert.Equal(t, tc.field, appErr.Fields[0].Field)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&translator.calls))
+	assert.Len(t, repo.rows, 2)
+
+	// Pairs outside the configured directions never reach the provider
+	svc.languages, err = language.NewRegistry([]string{"en", "vi"}, []string{"en:vi"})
+	require.NoError(t, err)
+	_, err = svc.CreateTranslation(ctx, GlobalScope, CreateTranslationInput{SourceText: "rebase", SourceLanguage: "vi", TargetLanguage: "en"})
+	assert.True(t, errors.Is(err, &errors.AppError{Type: errors.ValidationErr, Code: errors.CodeUnsupportedLanguagePair}))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&translator.calls))
+}
+
+func TestMutationsKeepCacheCoherent(t *testing.T) {
+	svc, _, translator := newTestTranslationService()
+	close(translator.release)
+	ctx := context.Background()
+	input := CreateTranslationInput{SourceText: "deadlock", SourceLanguage: "en", TargetLanguage: "vi"}
+
+	created, err := svc.CreateTranslation(ctx, GlobalScope, input)
+	require.NoError(t, err)
+
+	_, err = svc.UpdateTranslation(ctx, &types.JWTClaims{Role: "admin"}, GlobalScope, created.ID, UpdateTranslationInput{TranslatedText: "khóa chết"})
+	require.NoError(t, err)
+
+	cached, err := svc.cache.Get(ctx, 0, "deadlock", "en", "vi")
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	assert.Equal(t, "khóa chết", cached.TranslatedText)
+
+	require.NoError(t, svc.DeleteTranslation(ctx, GlobalScope, created.ID))
+
+	cached, err = svc.cache.Get(ctx, 0, "deadlock", "en", "vi")
+	assert.NoError(t, err)
+	assert.Nil(t, cached)
+}
+
+func TestUpdateTranslationEditRights(t *testing.T) {
+	svc, repo, _ := newTestTranslationService()
+	ctx := context.Background()
+	assignments := svc.assignmentRepo.(*memoryAssignmentRepo)
+
+	pending := &model.Translation{SourceText: "a", SourceLanguage: "en", TargetLanguage: "vi", Category: "ui",
+		Status: model.TranslationStatusPending, CreatedBy: "alice"}
+	approved := &model.Translation{SourceText: "b", SourceLanguage: "en", TargetLanguage: "vi", Category: "ui",
+		Status: model.TranslationStatusApproved, CreatedBy: "alice"}
+	require.NoError(t, repo.Create(ctx, repository.GlobalTenant, pending))
+	require.NoError(t, repo.Create(ctx, repository.GlobalTenant, approved))
+
+	author := &types.JWTClaims{UserID: 1, Username: "alice", Role: "user"}
+	translator := &types.JWTClaims{UserID: 2, Username: "bob", Role: "translator"}
+	lead := &types.JWTClaims{UserID: 3, Username: "carol", Role: "translator"}
+	require.NoError(t, assignments.Create(ctx, &model.TranslatorAssignment{UserID: 2, Category: "ui"}))
+	require.NoError(t, assignments.Create(ctx, &model.TranslatorAssignment{UserID: 3, SourceLanguage: "en", TargetLanguage: "vi", Lead: true}))
+
+	edit := UpdateTranslationInput{TranslatedText: "edited"}
+	cases := []struct {
+		name        string
+		actor       *types.JWTClaims
+		translation *model.Translation
+		allowed     bool
+	}{
+		{"author edits own pending entry", author, pending, true},
+		{"read-only key of the author is rejected", &types.JWTClaims{UserID: 1, Username: "alice", Role: "user", APIKeyID: 7, Scope: model.APIKeyScopeReadOnly}, pending, false},
+		{"author cannot edit approved entry", author, approved, false},
+		{"assigned translator edits pending entry", translator, pending, true},
+		{"assigned translator cannot edit approved entry", translator, approved, false},
+		{"pair lead edits approved entry", lead, approved, true},
+		{"unassigned translator is rejected", &types.JWTClaims{UserID: 4, Username: "dan", Role: "translator"}, pending, false},
+		{"admin edits anything", &types.JWTClaims{UserID: 5, Role: "admin"}, approved, true},
+		{"translate key of an admin cannot edit approved entry", &types.JWTClaims{UserID: 5, Role: "admin", APIKeyID: 8, Scope: model.APIKeyScopeTranslate}, approved, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.UpdateTranslation(ctx, tc.actor, GlobalScope, tc.translation.ID, edit)
+			if tc.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	t.Run("project viewer cannot edit own pending entry", func(t *testing.T) {
+		project := &model.Project{ID: 1}
+		own := &model.Translation{SourceText: "c", SourceLanguage: "en", TargetLanguage: "vi",
+			Status: model.TranslationStatusPending, CreatedBy: "alice"}
+		require.NoError(t, repo.Create(ctx, repository.ProjectTenant(project.ID), own))
+
+		_, err := svc.UpdateTranslation(ctx, author, Scope{Project: project, Role: model.ProjectRoleViewer}, own.ID, edit)
+		assert.Error(t, err)
+		_, err = svc.UpdateTranslation(ctx, author, Scope{Project: project, Role: model.ProjectRoleTranslator}, own.ID, edit)
+		assert.NoError(t, err)
+	})
+}
+
+func TestProjectTenantIsolation(t *testing.T) {
+	svc, _, translator := newTestTranslationService()
+	close(translator.release)
+	ctx := context.Background()
+	input := CreateTranslationInput{SourceText: "commit", SourceLanguage: "en", TargetLanguage: "vi"}
+
+	shared := Scope{Project: &model.Project{ID: 1, UseGlobalTM: true}, Role: model.ProjectRoleTranslator}
+	isolated := Scope{Project: &model.Project{ID: 2}, Role: model.ProjectRoleTranslator}
+
+	global, err := svc.CreateTranslation(ctx, GlobalScope, input)
+	require.NoError(t, err)
+	assert.Nil(t, global.ProjectID)
+
+	// A project using the global TM is answered from it, into its own entry
+	fromGlobal, err := svc.CreateTranslation(ctx, shared, input)
+	require.NoError(t, err)
+	assert.NotEqual(t, global.ID, fromGlobal.ID)
+	require.NotNil(t, fromGlobal.ProjectID)
+	assert.Equal(t, uint(1), *fromGlobal.ProjectID)
+	assert.Equal(t, global.TranslatedText, fromGlobal.TranslatedText)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&translator.calls))
+
+	// A project without it gets its own entry
+	own, err := svc.CreateTranslation(ctx, isolated, input)
+	require.NoError(t, err)
+	require.NotNil(t, own.ProjectID)
+	assert.Equal(t, uint(2), *own.ProjectID)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&translator.calls))
+
+	_, err = svc.GetTranslation(ctx, shared, own.ID)
+	assert.Error(t, err, "other projects' translations are invisible")
+	_, err = svc.GetTranslation(ctx, isolated, global.ID)
+	assert.Error(t, err, "global translations are not part of a project")
+	assert.Error(t, svc.DeleteTranslation(ctx, shared, own.ID))
+
+	listings := []struct {
+		scope Scope
+		rows  int
+	}{{GlobalScope, 1}, {shared, 1}, {isolated, 1}}
+	for _, listing := range listings {
+		page, err := svc.ListTranslations(ctx, listing.scope, repository.TranslationFilter{Page: 1}, repository.CountNone)
+		require.NoError(t, err)
+		assert.Len(t, page.Items, listing.rows)
+	}
+}
@@ -1,8 +1,70 @@
 package translator
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vietgs03/translate/backend/internal/language"
+)
 
 type Translator interface {
+	// Name identifies the provider, it is stored on every translation it produces
+	Name() string
+	// Coverage declares the languages and pairs the provider can translate;
+	// the configured languages are narrowed down to it
+	Coverage() language.Coverage
+	// Translate receives canonical BCP 47 tags
 	Translate(ctx context.Context, text, sourceLang, targetLang string) (string, error)
 	Close() error
-} 
\ No newline at end of file
+}
+
+// Prober is implemented by providers that can be checked without paying
+// for a translation
+type Prober interface {
+	// Probe fails when the provider can't currently be reached
+	Probe(ctx context.Context) error
+}
+
+// Errors providers wrap, so failures can be told apart without parsing
+// provider messages
+var (
+	ErrRateLimited      = errors.New("provider rate limit reached")
+	ErrUnavailable      = errors.New("provider unavailable")
+	ErrEmptyTranslation = errors.New("provider returned no translation")
+
+	// ErrThrottled is returned without calling the provider when our own
+	// budget of calls is spent; it wraps ErrRateLimited
+	ErrThrottled = fmt.Errorf("%w: local call budget spent", ErrRateLimited)
+)
+
+// Kinds of Translate failures, a fixed set so they can label metrics
+const (
+	ErrorTimeout     = "timeout"
+	ErrorCanceled    = "canceled"
+	ErrorRateLimited = "rate_limited"
+	ErrorThrottled   = "throttled"
+	ErrorUnavailable = "unavailable"
+	ErrorEmpty       = "empty"
+	ErrorOther       = "other"
+)
+
+// ErrorKind classifies an error returned by Translate
+func ErrorKind(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorCanceled
+	case errors.Is(err, ErrThrottled):
+		return ErrorThrottled
+	case errors.Is(err, ErrRateLimited):
+		return ErrorRateLimited
+	case errors.Is(err, ErrUnavailable):
+		return ErrorUnavailable
+	case errors.Is(err, ErrEmptyTranslation):
+		return ErrorEmpty
+	default:
+		return ErrorOther
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/vietgs03/translate/backend/internal/config"
+	"github.com/vietgs03/translate/backend/internal/model"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
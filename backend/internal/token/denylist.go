@@ -0,0 +1,125 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Denylist records revoked access tokens until they would have expired
+// anyway. Individual tokens are revoked by jti. Access tokens also carry
+// the token version of their user at issue; RevokeUser moves it on, which
+// invalidates every token of the user issued up to now, e.g. after a role
+// change, without comparing issue times.
+type Denylist interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	RevokeUser(ctx context.Context, userID uint) error
+	// TokenVersion is the version new tokens of the user are issued with;
+	// tokens with a lower one are revoked. Users never revoked are at 0.
+	TokenVersion(ctx context.Context, userID uint) (int64, error)
+}
+
+type redisDenylist struct {
+	redis *redis.Client
+}
+
+func NewRedisDenylist(redis *redis.Client) Denylist {
+	return &redisDenylist{redis: redis}
+}
+
+func (d *redisDenylist) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil // Already expired, nothing to deny
+	}
+	return d.redis.Set(ctx, "auth:denylist:jti:"+jti, 1, ttl).Err()
+}
+
+func (d *redisDenylist) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := d.redis.Exists(ctx, "auth:denylist:jti:"+jti).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check token denylist: %v", err)
+	}
+	return n > 0, nil
+}
+
+// The version keys never expire: one going back to 0 would make tokens
+// issued with a higher version outlive the next revocation
+func tokenVersionKey(userID uint) string {
+	return fmt.Sprintf("auth:token_version:%d", userID)
+}
+
+func (d *redisDenylist) RevokeUser(ctx context.Context, userID uint) error {
+	return d.redis.Incr(ctx, tokenVersionKey(userID)).Err()
+}
+
+func (d *redisDenylist) TokenVersion(ctx context.Context, userID uint) (int64, error) {
+	version, err := d.redis.Get(ctx, tokenVersionKey(userID)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to check token version: %v", err)
+	}
+	return version, nil
+}
+
+// memoryDenylist is the single-process fallback used when no Redis server
+// is configured.
+type memoryDenylist struct {
+	mu       sync.Mutex
+	jtis     map[string]time.Time
+	versions map[uint]int64
+}
+
+func NewMemoryDenylist() Denylist {
+	return &memoryDenylist{
+		jtis:     make(map[string]time.Time),
+		versions: make(map[uint]int64),
+	}
+}
+
+func (d *memoryDenylist) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.evictExpired()
+	d.jtis[jti] = expiresAt
+	return nil
+}
+
+func (d *memoryDenylist) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	expiresAt, ok := d.jtis[jti]
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+func (d *memoryDenylist) RevokeUser(ctx context.Context, userID uint) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.versions[userID]++
+	return nil
+}
+
+func (d *memoryDenylist) TokenVersion(ctx context.Context, userID uint) (int64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.versions[userID], nil
+}
+
+func (d *memoryDenylist) evictExpired() {
+	now := time.Now()
+	for jti, expiresAt := range d.jtis {
+		if now.After(expiresAt) {
+			delete(d.jtis, jti)
+		}
+	}
+}
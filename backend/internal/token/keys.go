@@ -0,0 +1,516 @@
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vietgs03/translate/backend/internal/config"
+	"github.com/vietgs03/translate/backend/internal/logging"
+	"github.com/vietgs03/translate/backend/internal/model"
+	"go.uber.org/zap"
+)
+
+// Supported signing algorithms
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// refreshInterval is how often replicas pick up keys rotated by others
+const refreshInterval = time.Minute
+
+// reloadInterval limits the reloads triggered by tokens with an unknown
+// kid, so made-up kids can't hammer the database
+const reloadInterval = 10 * time.Second
+
+// JWKSMaxAge is how long verifiers may cache the JWKS
+const JWKSMaxAge = 5 * time.Minute
+
+// publishAhead is how long a new key is served in the JWKS before it
+// signs, so verifiers caching the JWKS and replicas refreshing their keys
+// all know it by then
+const publishAhead = JWKSMaxAge + refreshInterval
+
+// maxUnknownKids bounds the kids remembered as not found
+const maxUnknownKids = 1024
+
+// KeyStore persists signing keys so every replica signs and verifies with
+// the same set. repository.SigningKeyRepository implements it.
+type KeyStore interface {
+	List(ctx context.Context) ([]model.SigningKey, error)
+	// Rotate stores key if due, given the unexpired keys newest first,
+	// still holds. Replicas rotating at once are serialized, so only the
+	// first one stores its key.
+	Rotate(ctx context.Context, key *model.SigningKey, due func(stored []model.SigningKey) bool) (bool, error)
+	DeleteExpired(ctx context.Context) error
+}
+
+type signingKey struct {
+	id        string
+	algorithm string
+	method    jwt.SigningMethod
+	private   crypto.Signer
+	public    crypto.PublicKey
+	createdAt time.Time
+	expiresAt time.Time
+}
+
+// KeySet signs access tokens with the newest published key and verifies
+// them with any unexpired key, looked up by the kid header. A new key is
+// generated every rotation interval and published for publishAhead before
+// it takes over signing; the previous ones stay valid for verification for
+// the overlap window after that.
+type KeySet struct {
+	store    KeyStore
+	cfg      *config.JWTConfig
+	rotation time.Duration
+	overlap  time.Duration
+	kek      cipher.AEAD // nil stores private keys as plain PEM
+
+	mu   sync.RWMutex
+	keys []*signingKey // newest first
+
+	reloadMu   sync.Mutex
+	lastReload time.Time
+	unknown    map[string]time.Time // kids a reload didn't find, and when
+}
+
+func NewKeySet(ctx context.Context, store KeyStore, cfg *config.JWTConfig) (*KeySet, error) {
+	if signingMethod(cfg.Algorithm) == nil {
+		return nil, fmt.Errorf("unsupported JWT algorithm %q, use %s or %s", cfg.Algorithm, AlgorithmRS256, AlgorithmEdDSA)
+	}
+
+	// Tokens signed just before a rotation must outlive it
+	overlap := time.Duration(cfg.KeyOverlap) * time.Minute
+	if accessTTL := time.Duration(cfg.AccessExpiresIn) * time.Minute; overlap < accessTTL {
+		overlap = accessTTL
+	}
+
+	kek, err := newKeyCipher(cfg.KeyEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &KeySet{
+		store:    store,
+		cfg:      cfg,
+		rotation: time.Duration(cfg.KeyRotation) * time.Hour,
+		overlap:  overlap,
+		kek:      kek,
+		unknown:  make(map[string]time.Time),
+	}
+	if err := k.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Run reloads the key set and rotates it when due, until ctx is done
+func (k *KeySet) Run(ctx context.Context) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.refresh(ctx); err != nil {
+				logging.FromContext(ctx).Error("failed to refresh signing keys", zap.Error(err))
+			}
+			if err := k.store.DeleteExpired(ctx); err != nil {
+				logging.FromContext(ctx).Warn("failed to delete expired signing keys", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (k *KeySet) refresh(ctx context.Context) error {
+	stored, err := k.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load signing keys: %v", err)
+	}
+
+	if k.due(stored) {
+		key, err := k.generate(time.Now())
+		if err != nil {
+			return err
+		}
+		rotated, err := k.store.Rotate(ctx, key, k.due)
+		if err != nil {
+			return fmt.Errorf("failed to store signing key: %v", err)
+		}
+		if rotated {
+			logging.FromContext(ctx).Info("security: rotated JWT signing key", zap.String("kid", key.ID))
+		}
+		// Another replica may have rotated first, load whichever key won
+		if stored, err = k.store.List(ctx); err != nil {
+			return fmt.Errorf("failed to load signing keys: %v", err)
+		}
+	}
+	return k.load(ctx, stored)
+}
+
+// due reports whether the newest stored key must be replaced
+func (k *KeySet) due(stored []model.SigningKey) bool {
+	return len(stored) == 0 || time.Since(stored[0].CreatedAt) >= k.rotation || stored[0].Algorithm != k.cfg.Algorithm
+}
+
+func (k *KeySet) load(ctx context.Context, stored []model.SigningKey) error {
+	keys := make([]*signingKey, 0, len(stored))
+	for _, s := range stored {
+		key, err := k.parseSigningKey(s)
+		if err != nil {
+			logging.FromContext(ctx).Warn("skipping signing key", zap.String("kid", s.ID), zap.Error(err))
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("no usable signing key")
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.mu.Unlock()
+	return nil
+}
+
+func (k *KeySet) generate(now time.Time) (*model.SigningKey, error) {
+	var private crypto.Signer
+	var err error
+	switch k.cfg.Algorithm {
+	case AlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %v", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signing key: %v", err)
+	}
+	kid, err := keyID(private.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := k.seal(kid, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.SigningKey{
+		ID:         kid,
+		Algorithm:  k.cfg.Algorithm,
+		PrivateKey: sealed,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(publishAhead + k.rotation + k.overlap),
+	}, nil
+}
+
+// Sign signs claims with the current key
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	key := k.signer()
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.private)
+}
+
+// signer returns the newest key published for at least publishAhead. Only
+// when none is, e.g. for the first key ever, the oldest one signs.
+func (k *KeySet) signer() *signingKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	published := time.Now().Add(-publishAhead)
+	for _, key := range k.keys {
+		if !key.createdAt.After(published) {
+			return key
+		}
+	}
+	return k.keys[len(k.keys)-1]
+}
+
+// Parse verifies a token strictly: the algorithm must match the key named
+// by kid, issuer and audience must match, and exp and nbf are required.
+func (k *KeySet) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{AlgorithmRS256, AlgorithmEdDSA}),
+		jwt.WithIssuer(k.cfg.Issuer),
+		jwt.WithAudience(k.cfg.Audience),
+		jwt.WithIssuedAt(),
+	)
+
+	token, err := parser.ParseWithClaims(tokenString, claims, k.verificationKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if exp, err := claims.GetExpirationTime(); err != nil || exp == nil {
+		return nil, fmt.Errorf("token has no expiration time")
+	}
+	if nbf, err := claims.GetNotBefore(); err != nil || nbf == nil {
+		return nil, fmt.Errorf("token has no not-before time")
+	}
+	return token, nil
+}
+
+func (k *KeySet) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, fmt.Errorf("token has no kid")
+	}
+
+	key := k.find(kid)
+	if key == nil {
+		// Another replica may have rotated in a key since the last refresh
+		if key = k.lookupUnknown(kid); key == nil {
+			return nil, fmt.Errorf("unknown signing key %s", kid)
+		}
+	}
+	if token.Method.Alg() != key.algorithm {
+		return nil, fmt.Errorf("unexpected signing method %s for key %s", token.Method.Alg(), kid)
+	}
+	if time.Now().After(key.expiresAt) {
+		return nil, fmt.Errorf("signing key %s has expired", kid)
+	}
+	return key.public, nil
+}
+
+func (k *KeySet) find(kid string) *signingKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	for _, key := range k.keys {
+		if key.id == kid {
+			return key
+		}
+	}
+	return nil
+}
+
+// lookupUnknown reloads the stored keys, without rotating, for a kid this
+// replica doesn't know. Only kids keyID could have made are looked up, at
+// most one reload per reloadInterval, and a kid a reload didn't find isn't
+// looked up again until the periodic refresh would have found it, so
+// made-up kids can't keep the reloads busy. Concurrent callers wait for
+// the reload in progress.
+func (k *KeySet) lookupUnknown(kid string) *signingKey {
+	if !wellFormedKid(kid) {
+		return nil
+	}
+
+	k.reloadMu.Lock()
+	defer k.reloadMu.Unlock()
+	if missed, ok := k.unknown[kid]; ok && time.Since(missed) < refreshInterval {
+		return nil
+	}
+	// A reload in progress may have brought it in
+	if key := k.find(kid); key != nil {
+		return key
+	}
+	if time.Since(k.lastReload) < reloadInterval {
+		return nil
+	}
+	k.lastReload = time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stored, err := k.store.List(ctx)
+	if err == nil {
+		err = k.load(ctx, stored)
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to reload signing keys", zap.Error(err))
+		return nil
+	}
+
+	key := k.find(kid)
+	if key == nil {
+		if len(k.unknown) >= maxUnknownKids {
+			k.unknown = make(map[string]time.Time)
+		}
+		k.unknown[kid] = time.Now()
+	}
+	return key
+}
+
+// wellFormedKid reports whether keyID could have made kid
+func wellFormedKid(kid string) bool {
+	raw, err := base64.RawURLEncoding.DecodeString(kid)
+	return err == nil && len(raw) == 16
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of every key that tokens may be signed
+// with, including the next one before it signs
+func (k *KeySet) JWKS() JWKS {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	jwks := JWKS{Keys: make([]JWK, 0, len(k.keys))}
+	for _, key := range k.keys {
+		jwk := JWK{Use: "sig", Alg: key.algorithm, Kid: key.id}
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks
+}
+
+func (k *KeySet) parseSigningKey(stored model.SigningKey) (*signingKey, error) {
+	method := signingMethod(stored.Algorithm)
+	if method == nil {
+		return nil, fmt.Errorf("unsupported algorithm %q", stored.Algorithm)
+	}
+
+	pemKey, err := k.open(stored.ID, stored.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, fmt.Errorf("malformed PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var private crypto.Signer
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		private = key
+	case ed25519.PrivateKey:
+		private = key
+	}
+	if private == nil || (stored.Algorithm == AlgorithmRS256) != isRSA(private) {
+		return nil, fmt.Errorf("key type doesn't match algorithm %s", stored.Algorithm)
+	}
+
+	return &signingKey{
+		id:        stored.ID,
+		algorithm: stored.Algorithm,
+		method:    method,
+		private:   private,
+		public:    private.Public(),
+		createdAt: stored.CreatedAt,
+		expiresAt: stored.ExpiresAt,
+	}, nil
+}
+
+func signingMethod(algorithm string) jwt.SigningMethod {
+	switch algorithm {
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256
+	case AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA
+	}
+	return nil
+}
+
+func isRSA(key crypto.Signer) bool {
+	_, ok := key.(*rsa.PrivateKey)
+	return ok
+}
+
+// keyID derives the kid from the public key, like an RFC 7638 thumbprint
+func keyID(public crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %v", err)
+	}
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:16]), nil
+}
+
+// memoryKeyStore keeps keys in process; every replica then has its own
+// keys, so it is only suitable for a single instance and tests.
+type memoryKeyStore struct {
+	mu   sync.Mutex
+	keys []model.SigningKey
+}
+
+func NewMemoryKeyStore() KeyStore {
+	return &memoryKeyStore{}
+}
+
+func (s *memoryKeyStore) List(ctx context.Context) ([]model.SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list(), nil
+}
+
+func (s *memoryKeyStore) list() []model.SigningKey {
+	now := time.Now()
+	var keys []model.SigningKey
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		if s.keys[i].ExpiresAt.After(now) {
+			keys = append(keys, s.keys[i])
+		}
+	}
+	return keys
+}
+
+func (s *memoryKeyStore) Rotate(ctx context.Context, key *model.SigningKey, due func([]model.SigningKey) bool) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !due(s.list()) {
+		return false, nil
+	}
+	s.keys = append(s.keys, *key)
+	return true, nil
+}
+
+func (s *memoryKeyStore) DeleteExpired(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	kept := s.keys[:0]
+	for _, key := range s.keys {
+		if key.ExpiresAt.After(now) {
+			kept = append(kept, key)
+		}
+	}
+	s.keys = kept
+	return nil
+}
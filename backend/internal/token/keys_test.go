@@ -0,0 +1,231 @@
+package token
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vietgs03/translate/backend/internal/config"
+)
+
+func testClaims(issuer, audience string) *jwt.RegisteredClaims {
+	now := time.Now()
+	return &jwt.RegisteredClaims{
+		Issuer:    issuer,
+		Audience:  jwt.ClaimStrings{audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+	}
+}
+
+// publish ages every stored key past publishAhead, as if the JWKS caches
+// had expired since they were created, and reloads keys
+func publish(t *testing.T, store KeyStore, keys ...*KeySet) {
+	memory := store.(*memoryKeyStore)
+	memory.mu.Lock()
+	for i := range memory.keys {
+		memory.keys[i].CreatedAt = memory.keys[i].CreatedAt.Add(-publishAhead)
+	}
+	memory.mu.Unlock()
+	for _, k := range keys {
+		stored, err := store.List(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, k.load(context.Background(), stored))
+	}
+}
+
+func TestKeySet(t *testing.T) {
+	ctx := context.Background()
+
+	for _, algorithm := range []string{AlgorithmRS256, AlgorithmEdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			cfg := &config.JWTConfig{Algorithm: algorithm, Issuer: "iss", Audience: "aud", AccessExpiresIn: 15, KeyRotation: 24}
+			keys, err := NewKeySet(ctx, NewMemoryKeyStore(), cfg)
+			require.NoError(t, err)
+
+			signed, err := keys.Sign(testClaims("iss", "aud"))
+			require.NoError(t, err)
+			_, err = keys.Parse(signed, &jwt.RegisteredClaims{})
+			assert.NoError(t, err)
+
+			jwks := keys.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, algorithm, jwks.Keys[0].Alg)
+		})
+	}
+}
+
+func TestKeySetStrictValidation(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.JWTConfig{Algorithm: AlgorithmEdDSA, Issuer: "iss", Audience: "aud", AccessExpiresIn: 15, KeyRotation: 24}
+	keys, err := NewKeySet(ctx, NewMemoryKeyStore(), cfg)
+	require.NoError(t, err)
+
+	t.Run("WrongAudience", func(t *testing.T) {
+		signed, err := keys.Sign(testClaims("iss", "other"))
+		require.NoError(t, err)
+		_, err = keys.Parse(signed, &jwt.RegisteredClaims{})
+		assert.Error(t, err)
+	})
+
+	t.Run("WrongIssuer", func(t *testing.T) {
+		signed, err := keys.Sign(testClaims("other", "aud"))
+		require.NoError(t, err)
+		_, err = keys.Parse(signed, &jwt.RegisteredClaims{})
+		assert.Error(t, err)
+	})
+
+	t.Run("MissingNotBefore", func(t *testing.T) {
+		claims := testClaims("iss", "aud")
+		claims.NotBefore = nil
+		signed, err := keys.Sign(claims)
+		require.NoError(t, err)
+		_, err = keys.Parse(signed, &jwt.RegisteredClaims{})
+		assert.Error(t, err)
+	})
+
+	t.Run("HMACWithPublicKey", func(t *testing.T) {
+		kid := keys.JWKS().Keys[0].Kid
+		forged := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims("iss", "aud"))
+		forged.Header["kid"] = kid
+		signed, err := forged.SignedString([]byte(keys.JWKS().Keys[0].X))
+		require.NoError(t, err)
+		_, err = keys.Parse(signed, &jwt.RegisteredClaims{})
+		assert.Error(t, err)
+	})
+}
+
+func TestKeySetRotationKeepsOldKeys(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryKeyStore()
+	cfg := &config.JWTConfig{Algorithm: AlgorithmEdDSA, Issuer: "iss", Audience: "aud", AccessExpiresIn: 15, KeyRotation: 24}
+
+	keys, err := NewKeySet(ctx, store, cfg)
+	require.NoError(t, err)
+	before, err := keys.Sign(testClaims("iss", "aud"))
+	require.NoError(t, err)
+
+	// A changed algorithm forces a rotation on the next refresh
+	cfg.Algorithm = AlgorithmRS256
+	require.NoError(t, keys.refresh(ctx))
+
+	assert.Len(t, keys.JWKS().Keys, 2)
+	_, err = keys.Parse(before, &jwt.RegisteredClaims{})
+	assert.NoError(t, err, "tokens of the previous key verify during the overlap")
+
+	// The new key is only published at first, the previous one still signs
+	during, err := keys.Sign(testClaims("iss", "aud"))
+	require.NoError(t, err)
+	parsed, err := keys.Parse(during, &jwt.RegisteredClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmEdDSA, parsed.Method.Alg())
+
+	publish(t, store, keys)
+	after, err := keys.Sign(testClaims("iss", "aud"))
+	require.NoError(t, err)
+	parsed, err = keys.Parse(after, &jwt.RegisteredClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmRS256, parsed.Method.Alg())
+}
+
+func TestKeySetReplicasShareRotation(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryKeyStore()
+	cfgA := &config.JWTConfig{Algorithm: AlgorithmEdDSA, Issuer: "iss", Audience: "aud", AccessExpiresIn: 15, KeyRotation: 24}
+	cfgB := *cfgA
+
+	a, err := NewKeySet(ctx, store, cfgA)
+	require.NoError(t, err)
+	b, err := NewKeySet(ctx, store, &cfgB)
+	require.NoError(t, err)
+	assert.Equal(t, a.JWKS(), b.JWKS(), "the second replica adopts the first one's key")
+
+	cfgA.Algorithm, cfgB.Algorithm = AlgorithmRS256, AlgorithmRS256
+	require.NoError(t, a.refresh(ctx))
+	require.NoError(t, b.refresh(ctx))
+	stored, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, stored, 2, "only one replica rotates")
+
+	t.Run("UnknownKidReloads", func(t *testing.T) {
+		cfgC := cfgB
+		cfgC.Algorithm = AlgorithmEdDSA
+		c, err := NewKeySet(ctx, store, &cfgC)
+		require.NoError(t, err)
+		publish(t, store, c)
+
+		signed, err := c.Sign(testClaims("iss", "aud"))
+		require.NoError(t, err)
+		_, err = a.Parse(signed, &jwt.RegisteredClaims{})
+		assert.NoError(t, err, "a key rotated in by another replica verifies before the next refresh")
+	})
+
+	t.Run("MadeUpKidsDontReload", func(t *testing.T) {
+		forge := func(kid string) string {
+			token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, testClaims("iss", "aud"))
+			token.Header["kid"] = kid
+			_, private, err := ed25519.GenerateKey(rand.Reader)
+			require.NoError(t, err)
+			signed, err := token.SignedString(private)
+			require.NoError(t, err)
+			return signed
+		}
+		reloadedAt := func() time.Time {
+			a.reloadMu.Lock()
+			defer a.reloadMu.Unlock()
+			return a.lastReload
+		}
+
+		// Kids keyID can't have made never reach the store
+		a.reloadMu.Lock()
+		a.lastReload = time.Time{}
+		a.reloadMu.Unlock()
+		_, err := a.Parse(forge("not-a-kid"), &jwt.RegisteredClaims{})
+		assert.Error(t, err)
+		assert.True(t, reloadedAt().IsZero())
+
+		// A well-formed kid not found is remembered, so it costs one reload
+		kid := "AAAAAAAAAAAAAAAAAAAAAA"
+		_, err = a.Parse(forge(kid), &jwt.RegisteredClaims{})
+		assert.Error(t, err)
+		first := reloadedAt()
+		assert.False(t, first.IsZero())
+
+		a.reloadMu.Lock()
+		a.lastReload = time.Time{}
+		a.reloadMu.Unlock()
+		_, err = a.Parse(forge(kid), &jwt.RegisteredClaims{})
+		assert.Error(t, err)
+		assert.True(t, reloadedAt().IsZero(), "a kid no reload found is not looked up again")
+	})
+}
+
+func TestKeySetSealsPrivateKeys(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryKeyStore()
+	cfg := &config.JWTConfig{Algorithm: AlgorithmEdDSA, Issuer: "iss", Audience: "aud", AccessExpiresIn: 15, KeyRotation: 24,
+		KeyEncryptionKey: "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="}
+
+	keys, err := NewKeySet(ctx, store, cfg)
+	require.NoError(t, err)
+	stored, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.NotContains(t, stored[0].PrivateKey, "PRIVATE KEY")
+
+	signed, err := keys.Sign(testClaims("iss", "aud"))
+	require.NoError(t, err)
+	_, err = keys.Parse(signed, &jwt.RegisteredClaims{})
+	assert.NoError(t, err)
+
+	wrong := *cfg
+	wrong.KeyEncryptionKey = "ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA="
+	_, err = NewKeySet(ctx, store, &wrong)
+	assert.Error(t, err, "keys sealed with another key encryption key are unusable")
+}
@@ -0,0 +1,65 @@
+package token
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// sealedPrefix marks private keys encrypted with the key encryption key;
+// keys stored without it are plain PEM.
+const sealedPrefix = "sealed:v1:"
+
+// newKeyCipher returns the AES-GCM cipher for a base64 key encryption key,
+// or nil when none is configured
+func newKeyCipher(encoded string) (cipher.AEAD, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	kek, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(kek) != 32 {
+		return nil, fmt.Errorf("JWT key encryption key must be 32 bytes in base64")
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts a PEM private key. The kid is authenticated with it, so a
+// sealed key can't be moved to another row.
+func (k *KeySet) seal(kid string, pemKey []byte) (string, error) {
+	if k.kek == nil {
+		return string(pemKey), nil
+	}
+	nonce := make([]byte, k.kek.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to seal signing key: %v", err)
+	}
+	sealed := k.kek.Seal(nonce, nonce, pemKey, []byte(kid))
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// open returns the PEM of a stored private key, decrypting it if sealed
+func (k *KeySet) open(kid, stored string) ([]byte, error) {
+	if !strings.HasPrefix(stored, sealedPrefix) {
+		return []byte(stored), nil
+	}
+	if k.kek == nil {
+		return nil, fmt.Errorf("key is sealed but no key encryption key is configured")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, sealedPrefix))
+	if err != nil || len(sealed) < k.kek.NonceSize() {
+		return nil, fmt.Errorf("malformed sealed key")
+	}
+	nonce, ciphertext := sealed[:k.kek.NonceSize()], sealed[k.kek.NonceSize():]
+	pemKey, err := k.kek.Open(nil, nonce, ciphertext, []byte(kid))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unseal key, wrong key encryption key?")
+	}
+	return pemKey, nil
+}
@@ -1,40 +0,0 @@
-package types
-
-// ValidationError represents a validation error
-type ValidationError struct {
-	Message string
-}
-
-func (e *ValidationError) Error() string {
-	return e.Message
-}
-
-func NewValidationError(message string) error {
-	return &ValidationError{Message: message}
-}
-
-// UnauthorizedError represents an unauthorized access error
-type UnauthorizedError struct {
-	Message string
-}
-
-func (e *UnauthorizedError) Error() string {
-	return e.Message
-}
-
-func NewUnauthorizedError(message string) error {
-	return &UnauthorizedError{Message: message}
-}
-
-// NotFoundError represents a not found error
-type NotFoundError struct {
-	Message string
-}
-
-func (e *NotFoundError) Error() string {
-	return e.Message
-}
-
-func NewNotFoundError(message string) error {
-	return &NotFoundError{Message: message}
-} 
\ No newline at end of file
@@ -6,5 +6,10 @@ type JWTClaims struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	// Set only for principals authenticated with an API key
+	APIKeyID uint   `json:"api_key_id,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+	// TokenVersion is the user's token version at issue, see token.Denylist
+	TokenVersion int64 `json:"ver,omitempty"`
 	jwt.RegisteredClaims
-} 
\ No newline at end of file
+}
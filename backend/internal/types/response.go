@@ -1,8 +1,17 @@
 package types
 
-// APIError represents an error response
-type APIError struct {
-	Error string `json:"error" example:"error message"`
+import "github.com/vietgs03/translate/backend/internal/errors"
+
+// Problem is an RFC 7807 application/problem+json error response
+type Problem struct {
+	Type      string              `json:"type" example:"about:blank"`
+	Title     string              `json:"title" example:"Not Found"`
+	Status    int                 `json:"status" example:"404"`
+	Detail    string              `json:"detail,omitempty" example:"translation not found"`
+	Instance  string              `json:"instance,omitempty" example:"/api/v1/translations/42"`
+	Code      string              `json:"code" example:"NOT_FOUND"`
+	RequestID string              `json:"request_id,omitempty" example:"3f2b8c1e-5d7a-4e2b-9c61-0a8f4d7e9b12"`
+	Errors    []errors.FieldError `json:"errors,omitempty"`
 }
 
 // APIResponse represents a generic API response
@@ -12,17 +21,33 @@ type APIResponse struct {
 	Message string      `json:"message,omitempty" example:"Operation successful"`
 }
 
-// LoginResponse represents the response for login
+// LoginResponse represents the response for login and token refresh
 type LoginResponse struct {
-	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIs..."`
+	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIs..."`
+	RefreshToken string `json:"refresh_token" example:"q3Jd9c0b7x..."`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int    `json:"expires_in" example:"900"` // seconds until Token expires
+}
+
+// Pagination describes the position of a page within a listing
+type Pagination struct {
+	Page           int    `json:"page,omitempty" example:"1"`
+	PageSize       int    `json:"page_size" example:"10"`
+	Total          *int64 `json:"total,omitempty" example:"100"`
+	TotalEstimated bool   `json:"total_estimated,omitempty" example:"false"`
+	NextCursor     string `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyNC0wMS0wMVQwMDowMDowMFoiLCJpIjo0MiwiZCI6Im5leHQifQ"`
+	PrevCursor     string `json:"prev_cursor,omitempty"`
+}
+
+// PaginationLinks holds ready-to-follow URLs for the neighbouring pages
+type PaginationLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
 }
 
 // PaginatedResponse represents a paginated response
 type PaginatedResponse struct {
-	Data       interface{} `json:"data"`
-	Pagination struct {
-		Page     int `json:"page" example:"1"`
-		PageSize int `json:"page_size" example:"10"`
-		Total    int `json:"total" example:"100"`
-	} `json:"pagination"`
-} 
\ No newline at end of file
+	Data       interface{}     `json:"data"`
+	Pagination Pagination      `json:"pagination"`
+	Links      PaginationLinks `json:"links"`
+}
\ No newline at end of file